@@ -10,8 +10,11 @@ import (
 	"github.com/ykhdr/mss-bot/internal/config"
 	"github.com/ykhdr/mss-bot/internal/logging"
 	"github.com/ykhdr/mss-bot/internal/minecraft"
+	"github.com/ykhdr/mss-bot/internal/monitor"
+	"github.com/ykhdr/mss-bot/internal/secret"
 	"github.com/ykhdr/mss-bot/internal/service"
 	"github.com/ykhdr/mss-bot/internal/storage"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
 	"github.com/ykhdr/mss-bot/internal/storage/sqlite"
 )
 
@@ -20,6 +23,8 @@ type App struct {
 	cfg     *config.Config
 	storage storage.ServerStorage
 	bot     *bot.Bot
+	poller  *monitor.Poller
+	pins    *monitor.PinUpdater
 	cancel  context.CancelFunc
 }
 
@@ -43,32 +48,71 @@ func New(configPath string) (*App, error) {
 
 	// Initialize Minecraft client
 	mcClient := minecraft.NewClient(cfg.Minecraft.Timeout)
+	providers := service.StatusProviders{
+		models.EditionJava:    statusProvider(minecraft.JavaClient{Client: mcClient}, cfg.Minecraft),
+		models.EditionBedrock: statusProvider(minecraft.BedrockClient{Client: mcClient}, cfg.Minecraft),
+	}
 
-	// Initialize service
-	svc := service.NewServerService(store, mcClient)
+	// Initialize services
+	svc := service.NewServerService(store, store, providers, mcClient)
+	chatSvc := service.NewChatService(store)
+	pinSvc := service.NewPinService(store)
+
+	var box *secret.Box
+	if len(cfg.RCON.Key) > 0 {
+		box, err = secret.NewBox(cfg.RCON.Key)
+		if err != nil {
+			store.Close()
+			return nil, fmt.Errorf("failed to initialize rcon encryption: %w", err)
+		}
+	} else {
+		log.Info().Msg("rcon key is not configured, rcon is disabled")
+	}
+	rconSvc := service.NewRCONService(svc, box, mcClient)
 
 	// Initialize bot
-	b, err := bot.New(cfg.Bot.Token, svc)
+	b, err := bot.New(cfg.Bot.Token, svc, chatSvc, rconSvc, pinSvc)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to initialize bot")
 		store.Close()
 		return nil, fmt.Errorf("failed to initialize bot: %w", err)
 	}
 
+	// Initialize background status poller
+	poller := monitor.NewPoller(svc, chatSvc, b, cfg.Monitor.Interval, cfg.Monitor.FailureThreshold)
+	pins := monitor.NewPinUpdater(pinSvc, svc, chatSvc, b, cfg.Monitor.PinInterval)
+
 	log.Info().Msg("successful initialization")
 
 	return &App{
 		cfg:     cfg,
 		storage: store,
 		bot:     b,
+		poller:  poller,
+		pins:    pins,
 	}, nil
 }
 
+// statusProvider retries transient failures of a client and caches its results.
+// Caching goes outermost so that coalesced queries share the retries.
+func statusProvider(client minecraft.StatusProvider, cfg config.MinecraftConfig) minecraft.StatusProvider {
+	retrying := minecraft.NewRetryingProvider(client, minecraft.RetryPolicy{
+		Attempts:   cfg.RetryAttempts,
+		Backoff:    cfg.RetryBackoff,
+		MaxBackoff: cfg.RetryMaxBackoff,
+		Deadline:   cfg.RetryDeadline,
+	})
+	return minecraft.NewCachedProvider(retrying, cfg.CacheTTL)
+}
+
 // Run starts the application
 func (a *App) Run() error {
 	ctx, cancel := context.WithCancel(context.Background())
 	a.cancel = cancel
 
+	go a.poller.Run(ctx)
+	go a.pins.Run(ctx)
+
 	log.Info().Msg("starting bot")
 	return a.bot.Start(ctx)
 }
@@ -0,0 +1,162 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+)
+
+// groupAnonymousBotID is the user Telegram puts in From of the messages that anonymous admins send on behalf of a group
+const groupAnonymousBotID = 1087968824
+
+// adminCacheTTL is how long the admin list of a group is trusted before it is fetched again
+const adminCacheTTL = 5 * time.Minute
+
+// AdminCache keeps the admin lists of group chats, so checking a permission does not cost a request
+// to Telegram on every button press
+type AdminCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu    sync.Mutex
+	lists map[int64]adminList
+}
+
+type adminList struct {
+	admins    map[int64]bool
+	fetchedAt time.Time
+}
+
+// NewAdminCache creates an empty admin cache whose lists expire after ttl
+func NewAdminCache(ttl time.Duration) *AdminCache {
+	return &AdminCache{
+		ttl:   ttl,
+		now:   time.Now,
+		lists: make(map[int64]adminList),
+	}
+}
+
+// IsAdmin reports whether a user is in the cached admin list of a chat;
+// ok is false when the list is missing or expired
+func (c *AdminCache) IsAdmin(chatID, userID int64) (isAdmin, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	list, ok := c.lists[chatID]
+	if !ok || c.now().Sub(list.fetchedAt) >= c.ttl {
+		delete(c.lists, chatID)
+		return false, false
+	}
+	return list.admins[userID], true
+}
+
+// Store caches the admin list of a chat
+func (c *AdminCache) Store(chatID int64, userIDs []int64) {
+	admins := make(map[int64]bool, len(userIDs))
+	for _, id := range userIDs {
+		admins[id] = true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lists[chatID] = adminList{admins: admins, fetchedAt: c.now()}
+}
+
+// isChatAdmin reports whether a user administers a chat. Everyone administers their private chat with the bot.
+func (h *Handlers) isChatAdmin(chat *tgbotapi.Chat, userID int64) bool {
+	if chat.IsPrivate() {
+		return true
+	}
+	if isAdmin, ok := h.admins.IsAdmin(chat.ID, userID); ok {
+		return isAdmin
+	}
+
+	members, err := h.bot.GetChatAdministrators(tgbotapi.ChatAdministratorsConfig{
+		ChatConfig: tgbotapi.ChatConfig{ChatID: chat.ID},
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chat.ID).Msg("Failed to get chat administrators")
+		return h.isChatMemberAdmin(chat.ID, userID)
+	}
+
+	userIDs := make([]int64, 0, len(members))
+	for _, member := range members {
+		if member.User != nil {
+			userIDs = append(userIDs, member.User.ID)
+		}
+	}
+	h.admins.Store(chat.ID, userIDs)
+
+	isAdmin, _ := h.admins.IsAdmin(chat.ID, userID)
+	return isAdmin
+}
+
+// isChatMemberAdmin asks Telegram about a single member, for when the admin list cannot be fetched
+func (h *Handlers) isChatMemberAdmin(chatID, userID int64) bool {
+	member, err := h.bot.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: chatID, UserID: userID},
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Int64("user_id", userID).Msg("Failed to get chat member")
+		return false
+	}
+	return member.IsCreator() || member.IsAdministrator()
+}
+
+// canConfigure reports whether a user may change the servers and settings of a chat:
+// anyone in a private chat, admins in a group unless the group allows everyone
+func (h *Handlers) canConfigure(ctx context.Context, chat *tgbotapi.Chat, user *tgbotapi.User) bool {
+	if user == nil {
+		return false
+	}
+	if chat.IsPrivate() {
+		return true
+	}
+
+	settings, err := h.chatService.GetSettings(ctx, chat.ID)
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chat.ID).Msg("Failed to get chat settings")
+	} else if settings.AllowEveryone {
+		return true
+	}
+	return h.isChatAdmin(chat, user.ID)
+}
+
+// isAdminMessage reports whether a message comes from an admin of its chat. Anonymous admins send messages
+// on behalf of the group itself, which is not in the admin list; messages on behalf of other chats never qualify.
+func (h *Handlers) isAdminMessage(message *tgbotapi.Message) bool {
+	if message.SenderChat != nil {
+		return message.SenderChat.ID == message.Chat.ID
+	}
+	return message.From != nil && h.isChatAdmin(message.Chat, message.From.ID)
+}
+
+// canConfigureMessage is canConfigure for the sender of a message, letting anonymous admins configure the chat
+func (h *Handlers) canConfigureMessage(ctx context.Context, message *tgbotapi.Message) bool {
+	if message.SenderChat != nil {
+		return message.SenderChat.ID == message.Chat.ID
+	}
+	return h.canConfigure(ctx, message.Chat, message.From)
+}
+
+// deniedText explains why a message may not run an admin command. Messages sent on behalf of a channel
+// cannot be traced to a member, so their senders are asked to use their own account instead.
+func deniedText(lang i18n.Lang, message *tgbotapi.Message, key string) string {
+	if message.SenderChat != nil && message.SenderChat.ID != message.Chat.ID {
+		return lang.T("access.sender_chat")
+	}
+	return lang.T(key)
+}
+
+// fromAnonymousAdmin reports whether state kept for anonymous admins may be used by the user who pressed a button.
+// Anonymous admins send commands as groupAnonymousBotID, but press buttons from their own accounts.
+func (h *Handlers) fromAnonymousAdmin(callback *tgbotapi.CallbackQuery) bool {
+	chat := callback.Message.Chat
+	return !chat.IsPrivate() && h.isChatAdmin(chat, callback.From.ID)
+}
@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+func TestAdminCache(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewAdminCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	_, ok := cache.IsAdmin(-100, 1)
+	assert.False(t, ok, "nothing is cached yet")
+
+	cache.Store(-100, []int64{1, 2})
+
+	isAdmin, ok := cache.IsAdmin(-100, 1)
+	assert.True(t, ok)
+	assert.True(t, isAdmin)
+
+	isAdmin, ok = cache.IsAdmin(-100, 3)
+	assert.True(t, ok)
+	assert.False(t, isAdmin, "members missing from the list are not admins")
+
+	_, ok = cache.IsAdmin(-200, 1)
+	assert.False(t, ok, "lists are per chat")
+
+	now = now.Add(time.Minute)
+	_, ok = cache.IsAdmin(-100, 1)
+	assert.False(t, ok, "expired lists are fetched again")
+}
+
+var (
+	anonymousAdmin = &tgbotapi.User{ID: groupAnonymousBotID, FirstName: "Group", IsBot: true}
+	channelBot     = &tgbotapi.User{ID: 136817688, FirstName: "Channel", IsBot: true}
+	channel        = &tgbotapi.Chat{ID: -100999, Type: "channel"}
+)
+
+func TestAnonymousAdmin_AddsServer(t *testing.T) {
+	h, api, servers := newTestHandlers()
+	api.admins = []int64{testUser.ID}
+	servers.online["mc.example.com"] = true
+	ctx := context.Background()
+
+	message := commandMessage(groupChat, anonymousAdmin, "/set mc.example.com Main")
+	message.SenderChat = groupChat
+	h.HandleCommand(ctx, message)
+
+	wizard, ok := h.stateManager.GetWizard(groupChat.ID, groupAnonymousBotID)
+	require.True(t, ok, "anonymous admins may configure the chat")
+	require.Equal(t, WizardConfirm, wizard.Step)
+
+	// The button is pressed from the admin's own account
+	h.HandleCallback(ctx, callbackQuery(groupChat, testUser, wizard.PromptID, CallbackWizardSave))
+	require.Len(t, servers.saved, 1)
+	assert.Equal(t, "Main", servers.saved[0].name)
+
+	_, ok = h.stateManager.GetWizard(groupChat.ID, groupAnonymousBotID)
+	assert.False(t, ok, "the wizard is over once saved")
+}
+
+func TestAnonymousAdmin_WizardNotForMembers(t *testing.T) {
+	h, api, servers := newTestHandlers()
+	servers.online["mc.example.com"] = true
+	ctx := context.Background()
+
+	message := commandMessage(groupChat, anonymousAdmin, "/set mc.example.com Main")
+	message.SenderChat = groupChat
+	h.HandleCommand(ctx, message)
+	wizard, ok := h.stateManager.GetWizard(groupChat.ID, groupAnonymousBotID)
+	require.True(t, ok)
+
+	h.HandleCallback(ctx, callbackQuery(groupChat, testUser, wizard.PromptID, CallbackWizardSave))
+	assert.Empty(t, servers.saved, "members who are not admins cannot confirm it")
+	assert.Equal(t, i18n.EN.T("wizard.inactive"), api.lastMessage().Text)
+}
+
+func TestChannelMessage_Explained(t *testing.T) {
+	for _, command := range []string{"/set mc.example.com Main", "/pin", "/rcon list"} {
+		t.Run(command, func(t *testing.T) {
+			h, api, servers := newTestHandlers()
+			servers.online["mc.example.com"] = true
+
+			message := commandMessage(groupChat, channelBot, command)
+			message.SenderChat = channel
+			h.HandleCommand(context.Background(), message)
+
+			assert.Empty(t, servers.checked)
+			assert.Equal(t, i18n.EN.T("access.sender_chat"), api.lastMessage().Text)
+		})
+	}
+}
+
+func TestAnonymousAdmin_AnswersPrompt(t *testing.T) {
+	h, api, servers := newTestHandlers()
+	api.admins = []int64{testUser.ID}
+	server := &models.Server{ID: 7, ChatID: groupChat.ID, IP: "mc.example.com", Port: 25565}
+	servers.servers[7] = server
+	ctx := context.Background()
+
+	// The button is pressed from the admin's own account, the answer comes on behalf of the group
+	h.HandleCallback(ctx, callbackQuery(groupChat, testUser, 500, ServerCallback(CallbackQueryPort, 7)))
+	reply := replyMessage(groupChat, anonymousAdmin, api.lastID(), "25566")
+	reply.SenderChat = groupChat
+	h.HandleMessage(ctx, reply)
+
+	assert.True(t, server.QueryEnabled)
+	assert.Equal(t, 25566, server.QueryPort)
+}
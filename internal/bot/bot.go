@@ -9,15 +9,26 @@ import (
 	"github.com/ykhdr/mss-bot/internal/service"
 )
 
+// maxConcurrentInlineQueries limits the inline queries answered at once; inline queries
+// are answered concurrently so that slow status checks do not hold up other updates
+const maxConcurrentInlineQueries = 8
+
 // Bot represents the Telegram bot
 type Bot struct {
 	api          *tgbotapi.BotAPI
 	handlers     *Handlers
 	stateManager *StateManager
+	inlineSlots  chan struct{}
 }
 
 // New creates a new bot instance
-func New(token string, svc *service.ServerService) (*Bot, error) {
+func New(
+	token string,
+	svc *service.ServerService,
+	chatSvc *service.ChatService,
+	rconSvc *service.RCONService,
+	pinSvc *service.PinService,
+) (*Bot, error) {
 	api, err := tgbotapi.NewBotAPI(token)
 	if err != nil {
 		return nil, err
@@ -26,12 +37,13 @@ func New(token string, svc *service.ServerService) (*Bot, error) {
 	log.Printf("Authorized on account %s", api.Self.UserName)
 
 	sm := NewStateManager()
-	handlers := NewHandlers(api, svc, sm)
+	handlers := NewHandlers(api, svc, chatSvc, rconSvc, pinSvc, sm)
 
 	return &Bot{
 		api:          api,
 		handlers:     handlers,
 		stateManager: sm,
+		inlineSlots:  make(chan struct{}, maxConcurrentInlineQueries),
 	}, nil
 }
 
@@ -57,14 +69,45 @@ func (b *Bot) Stop() {
 	b.api.StopReceivingUpdates()
 }
 
+// Notify sends a MarkdownV2 formatted message to a chat
+func (b *Bot) Notify(ctx context.Context, chatID int64, text string) error {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = tgbotapi.ModeMarkdownV2
+
+	_, err := b.api.Send(msg)
+	return err
+}
+
 func (b *Bot) processUpdate(ctx context.Context, update tgbotapi.Update) {
 	if update.Message != nil {
 		if update.Message.IsCommand() {
 			b.handlers.HandleCommand(ctx, update.Message)
+		} else if update.Message.Text != "" {
+			b.handlers.HandleMessage(ctx, update.Message)
 		}
 	}
 
 	if update.CallbackQuery != nil {
 		b.handlers.HandleCallback(ctx, update.CallbackQuery)
 	}
+
+	if update.InlineQuery != nil {
+		b.answerInlineQuery(ctx, update.InlineQuery)
+	}
+}
+
+// answerInlineQuery handles an inline query in the background. Queries beyond the limit are dropped:
+// the user keeps typing and Telegram sends a fresh query soon.
+func (b *Bot) answerInlineQuery(ctx context.Context, query *tgbotapi.InlineQuery) {
+	select {
+	case b.inlineSlots <- struct{}{}:
+	default:
+		log.Printf("Too many inline queries, dropping query %s", query.ID)
+		return
+	}
+
+	go func() {
+		defer func() { <-b.inlineSlots }()
+		b.handlers.HandleInlineQuery(ctx, query)
+	}()
 }
@@ -0,0 +1,172 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/minecraft"
+	"github.com/ykhdr/mss-bot/internal/service"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+// setArguments holds the parsed arguments of the /set command
+type setArguments struct {
+	Edition models.Edition
+	Host    string
+	// Port is zero for Java servers given without a port, whose SRV record is followed
+	Port int
+	Name string
+}
+
+// parseSetArguments parses "[java|bedrock] <address> [name]".
+// Without an explicit edition, servers on the default Bedrock port are treated as Bedrock.
+func parseSetArguments(args string) (*setArguments, error) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return nil, &minecraft.AddressError{Err: minecraft.ErrEmptyHost}
+	}
+
+	var edition models.Edition
+	switch strings.ToLower(fields[0]) {
+	case string(models.EditionJava):
+		edition = models.EditionJava
+		fields = fields[1:]
+	case string(models.EditionBedrock):
+		edition = models.EditionBedrock
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return nil, &minecraft.AddressError{Err: minecraft.ErrEmptyHost}
+	}
+
+	defaultPort := minecraft.DefaultJavaPort
+	if edition == models.EditionBedrock {
+		defaultPort = minecraft.DefaultBedrockPort
+	}
+
+	host, port, explicit, err := minecraft.ParseAddressWithDefault(fields[0], defaultPort)
+	if err != nil {
+		return nil, err
+	}
+
+	if edition == "" {
+		edition = models.EditionJava
+		if port == minecraft.DefaultBedrockPort {
+			edition = models.EditionBedrock
+		}
+	}
+	if edition == models.EditionJava && !explicit {
+		port = 0
+	}
+
+	return &setArguments{
+		Edition: edition,
+		Host:    host,
+		Port:    port,
+		Name:    strings.Join(fields[1:], " "),
+	}, nil
+}
+
+// addressErrorText describes why an address was rejected, for showing to users
+func addressErrorText(lang i18n.Lang, err error) string {
+	var reason string
+	switch {
+	case errors.Is(err, minecraft.ErrEmptyHost):
+		return lang.T("address.empty")
+	case errors.Is(err, minecraft.ErrInvalidPort):
+		reason = lang.T("address.invalid_port")
+	case errors.Is(err, minecraft.ErrPortOutOfRange):
+		reason = lang.T("address.port_range")
+	case errors.Is(err, minecraft.ErrInvalidHost):
+		reason = lang.T("address.invalid_host")
+	default:
+		return err.Error()
+	}
+
+	var addrErr *minecraft.AddressError
+	if errors.As(err, &addrErr) && addrErr.Address != "" {
+		return reason + " (" + addrErr.Address + ")"
+	}
+	return reason
+}
+
+// parseProtocolVersion parses a protocol version reply: a positive number, or "auto" for the server's own protocol
+func parseProtocolVersion(text string) (int, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	switch text {
+	case "auto", "авто", "0":
+		return models.AutoProtocolVersion, nil
+	}
+
+	version, err := strconv.ParseInt(text, 10, 32)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("invalid protocol version: %q", text)
+	}
+	return int(version), nil
+}
+
+// rconSettings holds a parsed reply to the RCON settings prompt
+type rconSettings struct {
+	Port     int
+	Password string
+}
+
+// parseRCONSettings parses "[port] <password>", or "off" to disable RCON.
+// Without a port, the default RCON port is used; the password is the rest of the text, spaces included.
+func parseRCONSettings(text string) (*rconSettings, error) {
+	text = strings.TrimSpace(text)
+	switch strings.ToLower(text) {
+	case "":
+		return nil, fmt.Errorf("empty rcon settings")
+	case "off", "выкл":
+		return &rconSettings{}, nil
+	}
+
+	portStr, password, found := strings.Cut(text, " ")
+	if !found {
+		return &rconSettings{Port: minecraft.DefaultRCONPort, Password: text}, nil
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return &rconSettings{Port: minecraft.DefaultRCONPort, Password: text}, nil
+	}
+	password = strings.TrimSpace(password)
+	if port < 1 || port > 65535 || password == "" {
+		return nil, fmt.Errorf("invalid rcon settings: port %d", port)
+	}
+	return &rconSettings{Port: port, Password: password}, nil
+}
+
+// maxWhitelistNames is how many players can be added to the whitelist with one reply
+const maxWhitelistNames = 10
+
+// parsePlayerNames parses player names separated by spaces or commas, dropping duplicates
+func parsePlayerNames(text string) ([]string, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no player names")
+	}
+	if len(fields) > maxWhitelistNames {
+		return nil, fmt.Errorf("too many player names: %d", len(fields))
+	}
+
+	names := make([]string, 0, len(fields))
+	seen := make(map[string]bool, len(fields))
+	for _, name := range fields {
+		if !service.IsValidPlayerName(name) {
+			return nil, fmt.Errorf("%w: %q", service.ErrInvalidPlayerName, name)
+		}
+		if key := strings.ToLower(name); !seen[key] {
+			seen[key] = true
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,114 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+func TestParseSetArguments(t *testing.T) {
+	tests := []struct {
+		args    string
+		edition models.Edition
+		host    string
+		port    int
+		name    string
+	}{
+		{"mc.example.com:25565 My Server", models.EditionJava, "mc.example.com", 25565, "My Server"},
+		{"mc.example.com", models.EditionJava, "mc.example.com", 0, ""},
+		{"bedrock play.example.net:19132", models.EditionBedrock, "play.example.net", 19132, ""},
+		{"bedrock play.example.net Friends", models.EditionBedrock, "play.example.net", 19132, "Friends"},
+		{"BEDROCK play.example.net:19200", models.EditionBedrock, "play.example.net", 19200, ""},
+		{"java geyser.example.net:19132 Geyser", models.EditionJava, "geyser.example.net", 19132, "Geyser"},
+		{"play.example.net:19132", models.EditionBedrock, "play.example.net", 19132, ""},
+		{"[2001:db8::1]:25566 IPv6", models.EditionJava, "2001:db8::1", 25566, "IPv6"},
+		{"bedrock 2001:db8::1", models.EditionBedrock, "2001:db8::1", 19132, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			parsed, err := parseSetArguments(tt.args)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.edition, parsed.Edition)
+			assert.Equal(t, tt.host, parsed.Host)
+			assert.Equal(t, tt.port, parsed.Port)
+			assert.Equal(t, tt.name, parsed.Name)
+		})
+	}
+}
+
+func TestParseSetArguments_Invalid(t *testing.T) {
+	for _, args := range []string{"", "bedrock", "mc.example.com:abc", "mc.example.com:70000", "[2001:db8::1"} {
+		t.Run(args, func(t *testing.T) {
+			_, err := parseSetArguments(args)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestAddressErrorText(t *testing.T) {
+	_, err := parseSetArguments("mc.example.com:99999")
+	require.Error(t, err)
+	assert.Equal(t, "порт должен быть от 1 до 65535 (mc.example.com:99999)", addressErrorText(i18n.RU, err))
+
+	_, err = parseSetArguments("bedrock")
+	require.Error(t, err)
+	assert.Equal(t, "не указан адрес сервера", addressErrorText(i18n.RU, err))
+}
+
+func TestParseProtocolVersion(t *testing.T) {
+	for text, want := range map[string]int{"765": 765, " 47 ": 47, "авто": 0, "Auto": 0, "0": 0} {
+		version, err := parseProtocolVersion(text)
+		require.NoError(t, err, text)
+		assert.Equal(t, want, version, text)
+	}
+
+	for _, text := range []string{"", "-1", "1.20.4", "99999999999"} {
+		_, err := parseProtocolVersion(text)
+		assert.Error(t, err, text)
+	}
+}
+
+func TestParseRCONSettings(t *testing.T) {
+	tests := []struct {
+		text     string
+		port     int
+		password string
+	}{
+		{"25576 hunter2", 25576, "hunter2"},
+		{"hunter2", 25575, "hunter2"},
+		{"correct horse battery", 25575, "correct horse battery"},
+		{" 25575  two words ", 25575, "two words"},
+		{"выкл", 0, ""},
+		{"OFF", 0, ""},
+	}
+
+	for _, tt := range tests {
+		settings, err := parseRCONSettings(tt.text)
+		require.NoError(t, err, tt.text)
+		assert.Equal(t, tt.port, settings.Port, tt.text)
+		assert.Equal(t, tt.password, settings.Password, tt.text)
+	}
+
+	for _, text := range []string{"", "70000 hunter2", "0 hunter2"} {
+		_, err := parseRCONSettings(text)
+		assert.Error(t, err, text)
+	}
+}
+
+func TestParsePlayerNames(t *testing.T) {
+	names, err := parsePlayerNames(" Steve, alex\n.BedrockGuy steve ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Steve", "alex", ".BedrockGuy"}, names)
+
+	for _, text := range []string{"", " , ", "Steve Стив", strings.Repeat("a ", maxWhitelistNames+1)} {
+		_, err := parsePlayerNames(text)
+		assert.Error(t, err, text)
+	}
+}
@@ -2,41 +2,118 @@ package bot
 
 import (
 	"context"
-	"fmt"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/rs/zerolog/log"
 
-	"github.com/ykhdr/mss-bot/internal/minecraft"
+	"github.com/ykhdr/mss-bot/internal/i18n"
 	"github.com/ykhdr/mss-bot/internal/service"
 	"github.com/ykhdr/mss-bot/internal/storage"
 	"github.com/ykhdr/mss-bot/internal/storage/models"
 )
 
+// TelegramAPI is the part of the Telegram Bot API the handlers use
+type TelegramAPI interface {
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
+	GetChatAdministrators(config tgbotapi.ChatAdministratorsConfig) ([]tgbotapi.ChatMember, error)
+	GetChatMember(config tgbotapi.GetChatMemberConfig) (tgbotapi.ChatMember, error)
+}
+
+// ServerManager manages the servers of chats and checks their status
+type ServerManager interface {
+	ListServers(ctx context.Context, chatID int64) ([]*models.Server, error)
+	ListUserServers(ctx context.Context, userID int64) ([]*models.Server, error)
+	GetServer(ctx context.Context, chatID, serverID int64) (*models.Server, error)
+	RemoveServer(ctx context.Context, chatID, serverID int64) error
+	SetServerConfig(
+		ctx context.Context, chatID, userID int64, edition models.Edition, ip string, port int, name string,
+	) error
+	SetQuery(ctx context.Context, chatID, serverID int64, enabled bool, port int) (*models.Server, error)
+	SetProtocolVersion(ctx context.Context, chatID, serverID int64, version int) (*models.Server, error)
+	GetServerStatus(ctx context.Context, chatID, serverID int64) (*service.ServerStatusResult, error)
+	CheckServer(ctx context.Context, server *models.Server) *service.ServerStatusResult
+	RecentStatus(server *models.Server, maxAge time.Duration) (*service.ServerStatusResult, bool)
+	CheckAddress(ctx context.Context, edition models.Edition, host string, port int) *service.ServerStatusResult
+	CheckPublicAddress(ctx context.Context, edition models.Edition, host string, port int) *service.ServerStatusResult
+}
+
+// SettingsManager reads and changes per-chat settings
+type SettingsManager interface {
+	GetSettings(ctx context.Context, chatID int64) (*models.ChatSettings, error)
+	TogglePlayerNotifications(ctx context.Context, chatID int64) (*models.ChatSettings, error)
+	ToggleAllowEveryone(ctx context.Context, chatID int64) (*models.ChatSettings, error)
+	SetLanguage(ctx context.Context, chatID int64, lang i18n.Lang) (*models.ChatSettings, error)
+}
+
+// PinRecorder records the pinned status cards that are kept up to date
+type PinRecorder interface {
+	Pin(ctx context.Context, chatID, serverID int64, messageID int) (*models.PinnedMessage, error)
+}
+
+// RCONManager keeps the RCON settings of servers and runs console commands on them
+type RCONManager interface {
+	Enabled() bool
+	ListServers(ctx context.Context, chatID int64) ([]*models.Server, error)
+	SetRCON(ctx context.Context, chatID, serverID int64, port int, password string) (*models.Server, error)
+	Execute(ctx context.Context, chatID, serverID int64, command string) (string, error)
+	Whitelist(ctx context.Context, chatID, serverID int64) (*service.Whitelist, error)
+	AddToWhitelist(ctx context.Context, chatID, serverID int64, player string, actor service.Actor) (string, error)
+	RemoveFromWhitelist(ctx context.Context, chatID, serverID int64, player string, actor service.Actor) (string, error)
+}
+
 // Handlers contains all bot command and callback handlers
 type Handlers struct {
-	bot          *tgbotapi.BotAPI
-	service      *service.ServerService
+	bot          TelegramAPI
+	service      ServerManager
+	chatService  SettingsManager
+	rconService  RCONManager
+	pinService   PinRecorder
 	stateManager *StateManager
+	fileIDs      *FileIDCache
+	admins       *AdminCache
 }
 
 // NewHandlers creates a new handlers instance
-func NewHandlers(bot *tgbotapi.BotAPI, svc *service.ServerService, sm *StateManager) *Handlers {
+func NewHandlers(
+	bot TelegramAPI,
+	svc ServerManager,
+	chatSvc SettingsManager,
+	rconSvc RCONManager,
+	pinSvc PinRecorder,
+	sm *StateManager,
+) *Handlers {
 	return &Handlers{
 		bot:          bot,
 		service:      svc,
+		chatService:  chatSvc,
+		rconService:  rconSvc,
+		pinService:   pinSvc,
 		stateManager: sm,
+		fileIDs:      NewFileIDCache(),
+		admins:       NewAdminCache(adminCacheTTL),
 	}
 }
 
 // HandleCommand processes incoming commands
 func (h *Handlers) HandleCommand(ctx context.Context, message *tgbotapi.Message) {
+	ctx = h.withLanguage(ctx, message.Chat.ID, message.From)
+
 	switch message.Command() {
 	case "mss":
 		h.handleMSS(ctx, message)
 	case "set":
 		h.handleSet(ctx, message)
+	case "cancel":
+		h.handleCancel(ctx, message)
+	case "rcon":
+		h.handleRCON(ctx, message)
+	case "pin":
+		h.handlePin(ctx, message)
 	case "start":
 		h.handleStart(ctx, message)
 	case "help":
@@ -54,38 +131,154 @@ func (h *Handlers) HandleCallback(ctx context.Context, callback *tgbotapi.Callba
 
 	chatID := callback.Message.Chat.ID
 	messageID := callback.Message.MessageID
+	h.stateManager.SetPhotoMessage(chatID, messageID, len(callback.Message.Photo) > 0)
+	ctx = h.withLanguage(ctx, chatID, callback.From)
+
+	action, serverID := ParseCallback(callback.Data)
+	if isConfigCallback(action) && !h.canConfigure(ctx, callback.Message.Chat, callback.From) {
+		h.sendText(chatID, i18n.FromContext(ctx).T("config.admins_only"))
+		return
+	}
 
-	switch callback.Data {
-	case CallbackStatus:
-		h.showStatus(ctx, chatID, messageID)
+	switch action {
+	case CallbackStatus, CallbackRefresh:
+		if serverID == 0 {
+			h.showStatus(ctx, chatID, messageID)
+		} else {
+			h.showServerStatus(ctx, chatID, messageID, serverID)
+		}
+	case CallbackMods:
+		h.showMods(ctx, chatID, messageID, serverID, CallbackPage(callback.Data))
 	case CallbackSettings:
 		h.showSettings(ctx, chatID, messageID)
+	case CallbackServer:
+		h.showServerSettings(ctx, chatID, messageID, serverID)
+	case CallbackQuery:
+		h.toggleQuery(ctx, chatID, messageID, serverID)
+	case CallbackQueryPort:
+		h.promptQueryPort(ctx, callback, serverID)
+	case CallbackProtocol:
+		h.promptProtocolVersion(ctx, callback, serverID)
+	case CallbackRCONSetup:
+		h.promptRCON(ctx, callback, serverID)
+	case CallbackRCONServer:
+		h.pickRCONServer(ctx, callback, serverID)
+	case CallbackRCONConfirm:
+		h.confirmRCON(ctx, callback, serverID)
+	case CallbackRCONCancel:
+		h.cancelRCON(ctx, callback)
+	case CallbackPin:
+		h.pickPinServer(ctx, callback, serverID)
+	case CallbackPinCancel:
+		h.cancelPin(ctx, callback)
+	case CallbackWhitelist, CallbackWhitelistAdd, CallbackWhitelistPlayer, CallbackWhitelistRemove:
+		h.handleWhitelistCallback(ctx, callback, action, serverID)
+	case CallbackDelete:
+		h.confirmDeleteServer(ctx, chatID, messageID, serverID)
+	case CallbackDeleteConfirm:
+		h.deleteServer(ctx, chatID, messageID, serverID)
+	case CallbackPlayerNotifications:
+		h.togglePlayerNotifications(ctx, chatID, messageID)
+	case CallbackLanguage:
+		h.switchLanguage(ctx, chatID, messageID)
+	case CallbackAllowEveryone:
+		h.toggleAllowEveryone(ctx, callback)
+	case CallbackAddServer:
+		h.startWizard(ctx, chatID, callback.From.ID, 0, messageID)
+	case CallbackWizardSave:
+		h.saveWizard(ctx, callback)
+	case CallbackWizardCancel:
+		h.cancelWizard(ctx, callback)
 	case CallbackBack:
 		h.showMainMenu(ctx, chatID, messageID)
-	case CallbackRefresh:
-		h.showStatus(ctx, chatID, messageID)
 	}
 }
 
-func (h *Handlers) handleStart(ctx context.Context, message *tgbotapi.Message) {
-	text := "👋 Привет! Я бот для проверки статуса Minecraft серверов.\n\n" +
-		"Используйте /mss для открытия меню."
+// HandleMessage processes plain text messages answering a pending prompt
+func (h *Handlers) HandleMessage(ctx context.Context, message *tgbotapi.Message) {
+	if message.From == nil {
+		return
+	}
+
+	input, ok := h.stateManager.GetPendingInput(message.Chat.ID)
+	if !ok || !acceptsInput(message, input) {
+		wizard, ok := h.stateManager.GetWizard(message.Chat.ID, message.From.ID)
+		if ok && acceptsWizardInput(message, wizard) {
+			h.handleWizardInput(h.withLanguage(ctx, message.Chat.ID, message.From), message, wizard)
+		}
+		return
+	}
+	if _, ok := h.stateManager.TakePendingInput(message.Chat.ID, input.UserID); !ok {
+		return
+	}
+	ctx = h.withLanguage(ctx, message.Chat.ID, message.From)
+
+	switch input.Kind {
+	case InputQueryPort:
+		h.handleQueryPortInput(ctx, message, input.ServerID)
+	case InputProtocolVersion:
+		h.handleProtocolVersionInput(ctx, message, input.ServerID)
+	case InputRCON:
+		h.handleRCONInput(ctx, message, input.ServerID)
+	case InputWhitelistAdd:
+		h.handleWhitelistInput(ctx, message, input.ServerID)
+	}
+}
 
-	msg := tgbotapi.NewMessage(message.Chat.ID, text)
+// awaitInput registers a prompt for the user who pressed a button. Admins may answer it anonymously too:
+// anonymous admins press buttons from their own accounts, but write on behalf of the group.
+func (h *Handlers) awaitInput(callback *tgbotapi.CallbackQuery, kind InputKind, serverID int64, promptID int) {
+	h.stateManager.SetPendingInput(callback.Message.Chat.ID, PendingInput{
+		Kind:      kind,
+		UserID:    callback.From.ID,
+		ServerID:  serverID,
+		PromptID:  promptID,
+		Anonymous: h.fromAnonymousAdmin(callback),
+	})
+}
+
+// acceptsInput reports whether a message answers the chat's prompt: it comes from the user the prompt
+// was issued to, or from an anonymous admin when they may answer it, and in groups it replies to the prompt,
+// so that the rest of the conversation is not taken for it
+func acceptsInput(message *tgbotapi.Message, input PendingInput) bool {
+	anonymous := input.Anonymous && message.SenderChat != nil && message.SenderChat.ID == message.Chat.ID
+	return (message.From.ID == input.UserID || anonymous) && answersPrompt(message, input.PromptID)
+}
+
+// answersPrompt reports whether a message may be the answer to the bot message with promptID.
+// In private chats any message is, in groups only replies to the prompt.
+func answersPrompt(message *tgbotapi.Message, promptID int) bool {
+	if message.Chat.IsPrivate() {
+		return true
+	}
+	return message.ReplyToMessage != nil && message.ReplyToMessage.MessageID == promptID
+}
+
+// withLanguage returns a copy of ctx carrying the language of a chat: the one chosen in the chat settings,
+// or the language of the user's Telegram client
+func (h *Handlers) withLanguage(ctx context.Context, chatID int64, user *tgbotapi.User) context.Context {
+	var clientLanguage string
+	if user != nil {
+		clientLanguage = user.LanguageCode
+	}
+
+	settings, err := h.chatService.GetSettings(ctx, chatID)
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Msg("Failed to get chat settings")
+		return i18n.WithLang(ctx, i18n.Detect(clientLanguage))
+	}
+	return i18n.WithLang(ctx, i18n.Resolve(settings.Language, clientLanguage))
+}
+
+func (h *Handlers) handleStart(ctx context.Context, message *tgbotapi.Message) {
+	msg := tgbotapi.NewMessage(message.Chat.ID, i18n.FromContext(ctx).T("start"))
 	if _, err := h.bot.Send(msg); err != nil {
 		log.Error().Err(err).Msg("Failed to send start message")
 	}
 }
 
 func (h *Handlers) handleHelp(ctx context.Context, message *tgbotapi.Message) {
-	text := "📖 *Справка*\n\n" +
-		"*Команды:*\n" +
-		"/mss \\- Открыть главное меню\n" +
-		"/mss\\-set \\<ip:port\\> \\<name\\> \\- Настроить сервер\n\n" +
-		"*Пример:*\n" +
-		"`/mss-set mc.example.com:25565 My Server`"
-
-	msg := tgbotapi.NewMessage(message.Chat.ID, text)
+	msg := tgbotapi.NewMessage(message.Chat.ID, i18n.FromContext(ctx).T("help"))
 	msg.ParseMode = tgbotapi.ModeMarkdownV2
 	if _, err := h.bot.Send(msg); err != nil {
 		log.Error().Err(err).Msg("Failed to send help message")
@@ -93,11 +286,11 @@ func (h *Handlers) handleHelp(ctx context.Context, message *tgbotapi.Message) {
 }
 
 func (h *Handlers) handleMSS(ctx context.Context, message *tgbotapi.Message) {
-	text := "🎮 *Minecraft Server Status*\n\nВыберите действие:"
+	lang := i18n.FromContext(ctx)
 
-	msg := tgbotapi.NewMessage(message.Chat.ID, text)
+	msg := tgbotapi.NewMessage(message.Chat.ID, lang.T("menu"))
 	msg.ParseMode = tgbotapi.ModeMarkdownV2
-	msg.ReplyMarkup = MainMenuKeyboard()
+	msg.ReplyMarkup = MainMenuKeyboard(lang)
 
 	sent, err := h.bot.Send(msg)
 	if err != nil {
@@ -108,133 +301,416 @@ func (h *Handlers) handleMSS(ctx context.Context, message *tgbotapi.Message) {
 	h.stateManager.SetState(message.Chat.ID, StateMainMenu, sent.MessageID)
 }
 
+// handleSet starts the setup wizard, skipping the questions answered by "/set [java|bedrock] <address> <name>"
 func (h *Handlers) handleSet(ctx context.Context, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
+	lang := i18n.FromContext(ctx)
+
+	if !h.canConfigureMessage(ctx, message) {
+		h.sendText(chatID, deniedText(lang, message, "config.admins_only"))
+		return
+	}
+
+	// The settings message, if open, is refreshed once the server is added
+	var settingsID int
+	if h.stateManager.IsInState(chatID, StateSettings) {
+		settingsID = h.stateManager.GetMessageID(chatID)
+	}
+
+	args := message.CommandArguments()
+	if args == "" {
+		h.startWizard(ctx, chatID, message.From.ID, message.MessageID, settingsID)
+		return
+	}
+
+	// The address is checked like an answer to the wizard; with a name given, only the confirmation is left
+	wizard := SetupWizard{UserID: message.From.ID, Step: WizardAddress, SettingsID: settingsID}
+	h.handleWizardAddress(ctx, message, args, wizard)
+}
+
+func (h *Handlers) showMainMenu(ctx context.Context, chatID int64, messageID int) {
+	lang := i18n.FromContext(ctx)
+
+	messageID = h.editMessage(chatID, messageID, lang.T("menu"), MainMenuKeyboard(lang))
+	h.stateManager.SetState(chatID, StateMainMenu, messageID)
+}
+
+// showStatus shows the status of the chat's only server, or a server picker when there are several
+func (h *Handlers) showStatus(ctx context.Context, chatID int64, messageID int) {
+	lang := i18n.FromContext(ctx)
+
+	servers, err := h.service.ListServers(ctx, chatID)
+	if err != nil {
+		h.editMessage(chatID, messageID, lang.T("error", service.EscapeMarkdown(err.Error())), BackKeyboard(lang))
+		return
+	}
+
+	switch len(servers) {
+	case 0:
+		messageID = h.editMessage(chatID, messageID, lang.T("status.no_servers"), BackKeyboard(lang))
+	case 1:
+		h.showServerStatus(ctx, chatID, messageID, servers[0].ID)
+		return
+	default:
+		messageID = h.editMessage(chatID, messageID, lang.T("status.pick"), ServerListKeyboard(lang, servers))
+	}
+
+	h.stateManager.SetState(chatID, StateStatus, messageID)
+}
+
+func (h *Handlers) showServerStatus(ctx context.Context, chatID int64, messageID int, serverID int64) {
+	lang := i18n.FromContext(ctx)
+	result, err := h.service.GetServerStatus(ctx, chatID, serverID)
+
+	var text string
+	var icon []byte
+	withMods := false
+	if err != nil {
+		if isNotFound(err) {
+			text = lang.T("status.not_found")
+		} else {
+			text = lang.T("error", service.EscapeMarkdown(err.Error()))
+		}
+	} else {
+		text = result.FormatStatus(lang)
+		icon = result.Status.Favicon
+		withMods = result.ModPages() > 0
+	}
+
+	servers, err := h.service.ListServers(ctx, chatID)
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Msg("Failed to list servers")
+	}
+
+	keyboard := StatusKeyboard(lang, serverID, len(servers) > 1, withMods)
+	messageID = h.editStatusMessage(chatID, messageID, text, icon, keyboard)
+	h.stateManager.SetState(chatID, StateStatus, messageID)
+}
+
+// showMods shows a page of the server's mod list, or the server status when it advertises no mods
+func (h *Handlers) showMods(ctx context.Context, chatID int64, messageID int, serverID int64, page int) {
+	result, err := h.service.GetServerStatus(ctx, chatID, serverID)
+	if err != nil || result.ModPages() == 0 {
+		h.showServerStatus(ctx, chatID, messageID, serverID)
+		return
+	}
+
+	lang := i18n.FromContext(ctx)
+	pages := result.ModPages()
+	page = min(page, pages-1)
+	messageID = h.editMessage(chatID, messageID, result.FormatMods(lang, page), ModsKeyboard(lang, serverID, page, pages))
+	h.stateManager.SetState(chatID, StateStatus, messageID)
+}
+
+func (h *Handlers) showSettings(ctx context.Context, chatID int64, messageID int) {
+	lang := i18n.FromContext(ctx)
+	servers, err := h.service.ListServers(ctx, chatID)
+
+	var text string
+	if err != nil {
+		text = lang.T("error", service.EscapeMarkdown(err.Error()))
+	} else {
+		text = service.FormatConfig(lang, servers)
+	}
+
+	settings, err := h.chatService.GetSettings(ctx, chatID)
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Msg("Failed to get chat settings")
+	}
+
+	messageID = h.editMessage(chatID, messageID, text, SettingsKeyboard(lang, servers, settings))
+	h.stateManager.SetState(chatID, StateSettings, messageID)
+}
+
+func (h *Handlers) showServerSettings(ctx context.Context, chatID int64, messageID int, serverID int64) {
+	server, err := h.service.GetServer(ctx, chatID, serverID)
+	if err != nil {
+		if !isNotFound(err) {
+			log.Error().Err(err).Int64("chat_id", chatID).Int64("server_id", serverID).Msg("Failed to get server")
+		}
+		h.showSettings(ctx, chatID, messageID)
+		return
+	}
 
-	// Check if we're in settings state
-	if !h.stateManager.IsInState(chatID, StateSettings) {
-		msg := tgbotapi.NewMessage(chatID,
-			"⚠️ Эта команда доступна только из меню настроек.\n"+
-				"Используйте /mss и нажмите кнопку Настройки.")
+	lang := i18n.FromContext(ctx)
+	text := service.FormatServerSettings(lang, server)
+	messageID = h.editMessage(chatID, messageID, text, ServerSettingsKeyboard(lang, server))
+	h.stateManager.SetState(chatID, StateSettings, messageID)
+}
+
+func (h *Handlers) toggleQuery(ctx context.Context, chatID int64, messageID int, serverID int64) {
+	server, err := h.service.GetServer(ctx, chatID, serverID)
+	if err == nil {
+		_, err = h.service.SetQuery(ctx, chatID, serverID, !server.QueryEnabled, server.QueryPort)
+	}
+	if err != nil && !isNotFound(err) {
+		log.Error().Err(err).Int64("chat_id", chatID).Int64("server_id", serverID).Msg("Failed to toggle query")
+	}
+
+	h.showServerSettings(ctx, chatID, messageID, serverID)
+}
+
+// promptQueryPort asks the user who pressed the button for the server's query port
+func (h *Handlers) promptQueryPort(ctx context.Context, callback *tgbotapi.CallbackQuery, serverID int64) {
+	chatID := callback.Message.Chat.ID
+	server, err := h.service.GetServer(ctx, chatID, serverID)
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Int64("server_id", serverID).Msg("Failed to get server")
+		return
+	}
+
+	text := i18n.FromContext(ctx).T("query_port.prompt", service.EscapeMarkdown(service.ServerTitle(server)))
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = tgbotapi.ModeMarkdownV2
+	msg.ReplyMarkup = tgbotapi.ForceReply{ForceReply: true, InputFieldPlaceholder: "25565"}
+	sent, err := h.bot.Send(msg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to send query port prompt")
+		return
+	}
+
+	h.awaitInput(callback, InputQueryPort, serverID, sent.MessageID)
+}
+
+func (h *Handlers) handleQueryPortInput(ctx context.Context, message *tgbotapi.Message, serverID int64) {
+	chatID := message.Chat.ID
+	lang := i18n.FromContext(ctx)
+
+	port, err := strconv.Atoi(strings.TrimSpace(message.Text))
+	if err != nil || port < 0 || port > 65535 {
+		msg := tgbotapi.NewMessage(chatID, lang.T("query_port.invalid"))
 		if _, err := h.bot.Send(msg); err != nil {
 			log.Error().Err(err).Msg("Failed to send error message")
 		}
 		return
 	}
 
-	// Parse arguments
-	args := message.CommandArguments()
-	if args == "" {
-		msg := tgbotapi.NewMessage(chatID,
-			"❌ Неверный формат.\n\n"+
-				"Использование: `/mss-set <ip:port> <name>`\n"+
-				"Пример: `/mss-set mc.example.com:25565 My Server`")
-		msg.ParseMode = tgbotapi.ModeMarkdownV2
+	if _, err := h.service.SetQuery(ctx, chatID, serverID, true, port); err != nil {
+		msg := tgbotapi.NewMessage(chatID, lang.T("error.save", err))
 		if _, err := h.bot.Send(msg); err != nil {
 			log.Error().Err(err).Msg("Failed to send error message")
 		}
 		return
 	}
 
-	// Parse address and name
-	parts := strings.SplitN(args, " ", 2)
-	address := parts[0]
-	name := ""
-	if len(parts) > 1 {
-		name = parts[1]
+	if messageID := h.stateManager.GetMessageID(chatID); messageID != 0 {
+		h.showServerSettings(ctx, chatID, messageID, serverID)
 	}
 
-	host, port, err := minecraft.ParseAddress(address)
+	confirmMsg := tgbotapi.NewMessage(chatID, lang.T("query_port.saved"))
+	if _, err := h.bot.Send(confirmMsg); err != nil {
+		log.Error().Err(err).Msg("Failed to send confirmation")
+	}
+}
+
+// promptProtocolVersion asks the user who pressed the button for the protocol version advertised to the server
+func (h *Handlers) promptProtocolVersion(ctx context.Context, callback *tgbotapi.CallbackQuery, serverID int64) {
+	chatID := callback.Message.Chat.ID
+	server, err := h.service.GetServer(ctx, chatID, serverID)
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Int64("server_id", serverID).Msg("Failed to get server")
+		return
+	}
+
+	lang := i18n.FromContext(ctx)
+	text := lang.T("protocol.prompt", service.EscapeMarkdown(service.ServerTitle(server)))
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = tgbotapi.ModeMarkdownV2
+	msg.ReplyMarkup = tgbotapi.ForceReply{ForceReply: true, InputFieldPlaceholder: lang.T("auto")}
+	sent, err := h.bot.Send(msg)
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Неверный адрес: %v", err))
+		log.Error().Err(err).Msg("Failed to send protocol version prompt")
+		return
+	}
+
+	h.awaitInput(callback, InputProtocolVersion, serverID, sent.MessageID)
+}
+
+func (h *Handlers) handleProtocolVersionInput(ctx context.Context, message *tgbotapi.Message, serverID int64) {
+	chatID := message.Chat.ID
+	lang := i18n.FromContext(ctx)
+
+	version, err := parseProtocolVersion(message.Text)
+	if err != nil {
+		msg := tgbotapi.NewMessage(chatID, lang.T("protocol.invalid"))
 		if _, err := h.bot.Send(msg); err != nil {
 			log.Error().Err(err).Msg("Failed to send error message")
 		}
 		return
 	}
 
-	// Save server config
-	if err := h.service.SetServerConfig(ctx, chatID, host, port, name); err != nil {
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Ошибка сохранения: %v", err))
+	if _, err := h.service.SetProtocolVersion(ctx, chatID, serverID, version); err != nil {
+		msg := tgbotapi.NewMessage(chatID, lang.T("error.save", err))
 		if _, err := h.bot.Send(msg); err != nil {
 			log.Error().Err(err).Msg("Failed to send error message")
 		}
 		return
 	}
 
-	// Update settings message
-	messageID := h.stateManager.GetMessageID(chatID)
-	h.showSettings(ctx, chatID, messageID)
+	if messageID := h.stateManager.GetMessageID(chatID); messageID != 0 {
+		h.showServerSettings(ctx, chatID, messageID, serverID)
+	}
 
-	// Send confirmation
-	confirmMsg := tgbotapi.NewMessage(chatID, "✅ Сервер успешно настроен!")
+	confirmMsg := tgbotapi.NewMessage(chatID, lang.T("protocol.saved"))
 	if _, err := h.bot.Send(confirmMsg); err != nil {
 		log.Error().Err(err).Msg("Failed to send confirmation")
 	}
 }
 
-func (h *Handlers) showMainMenu(ctx context.Context, chatID int64, messageID int) {
-	text := "🎮 *Minecraft Server Status*\n\nВыберите действие:"
+func (h *Handlers) togglePlayerNotifications(ctx context.Context, chatID int64, messageID int) {
+	if _, err := h.chatService.TogglePlayerNotifications(ctx, chatID); err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Msg("Failed to toggle player notifications")
+	}
 
-	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
-	edit.ParseMode = tgbotapi.ModeMarkdownV2
-	edit.ReplyMarkup = pointerTo(MainMenuKeyboard())
+	h.showSettings(ctx, chatID, messageID)
+}
 
-	if _, err := h.bot.Send(edit); err != nil {
-		log.Error().Err(err).Msg("Failed to edit message to main menu")
+// toggleAllowEveryone switches whether every member of a group may configure the bot; only admins may do it
+func (h *Handlers) toggleAllowEveryone(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	if !h.isChatAdmin(callback.Message.Chat, callback.From.ID) {
+		h.sendText(chatID, i18n.FromContext(ctx).T("access.admins_only"))
+		return
 	}
 
-	h.stateManager.SetState(chatID, StateMainMenu, messageID)
+	if _, err := h.chatService.ToggleAllowEveryone(ctx, chatID); err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Msg("Failed to toggle configuration access")
+	}
+
+	h.showSettings(ctx, chatID, callback.Message.MessageID)
 }
 
-func (h *Handlers) showStatus(ctx context.Context, chatID int64, messageID int) {
-	result, err := h.service.GetServerStatus(ctx, chatID)
+// switchLanguage switches the chat to the next supported language and shows the settings in it
+func (h *Handlers) switchLanguage(ctx context.Context, chatID int64, messageID int) {
+	lang := i18n.FromContext(ctx).Next()
+	if _, err := h.chatService.SetLanguage(ctx, chatID, lang); err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Msg("Failed to set chat language")
+		return
+	}
 
-	var text string
+	h.showSettings(i18n.WithLang(ctx, lang), chatID, messageID)
+}
+
+// confirmDeleteServer asks to confirm the deletion of a server, which also deletes its history and pinned cards
+func (h *Handlers) confirmDeleteServer(ctx context.Context, chatID int64, messageID int, serverID int64) {
+	server, err := h.service.GetServer(ctx, chatID, serverID)
 	if err != nil {
-		if isNotFound(err) {
-			text = "⚠️ Сервер не настроен\\.\n\nИспользуйте настройки для добавления сервера\\."
-		} else {
-			text = fmt.Sprintf("❌ Ошибка: %v", escapeMarkdownV2(err.Error()))
+		h.showSettings(ctx, chatID, messageID)
+		return
+	}
+
+	lang := i18n.FromContext(ctx)
+	text := lang.T("server_settings.confirm_delete", service.EscapeMarkdown(service.ServerTitle(server)))
+	messageID = h.editMessage(chatID, messageID, text, DeleteServerKeyboard(lang, serverID))
+	h.stateManager.SetState(chatID, StateSettings, messageID)
+}
+
+func (h *Handlers) deleteServer(ctx context.Context, chatID int64, messageID int, serverID int64) {
+	if err := h.service.RemoveServer(ctx, chatID, serverID); err != nil && !isNotFound(err) {
+		log.Error().Err(err).Int64("chat_id", chatID).Int64("server_id", serverID).Msg("Failed to remove server")
+	}
+
+	h.showSettings(ctx, chatID, messageID)
+}
+
+// editMessage replaces the text and keyboard of a bot message and returns the ID of the message showing them.
+// Photo messages cannot be edited into text ones, so they are replaced with a new message.
+func (h *Handlers) editMessage(chatID int64, messageID int, text string, keyboard tgbotapi.InlineKeyboardMarkup) int {
+	if h.stateManager.IsPhotoMessage(chatID, messageID) {
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ParseMode = tgbotapi.ModeMarkdownV2
+		msg.ReplyMarkup = keyboard
+		if sent, ok := h.replaceMessage(chatID, messageID, msg); ok {
+			return sent.MessageID
 		}
-	} else {
-		text = result.FormatStatus()
+		return messageID
 	}
 
 	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
 	edit.ParseMode = tgbotapi.ModeMarkdownV2
-	edit.ReplyMarkup = pointerTo(StatusKeyboard())
+	edit.ReplyMarkup = &keyboard
 
-	if _, err := h.bot.Send(edit); err != nil {
-		if strings.Contains(err.Error(), "message is not modified") {
-			// No need to log this as an error
-			return
-		}
-		log.Error().Err(err).Msg("Failed to edit message to status")
+	if _, err := h.bot.Send(edit); err != nil && !isNotModified(err) {
+		log.Error().Err(err).Msg("Failed to edit message")
 	}
-
-	h.stateManager.SetState(chatID, StateStatus, messageID)
+	return messageID
 }
 
-func (h *Handlers) showSettings(ctx context.Context, chatID int64, messageID int) {
-	server, err := h.service.GetServerConfig(ctx, chatID)
+// editStatusMessage shows a server status as the caption of the server icon, like the in-game server list,
+// and returns the ID of the message showing it. Without an icon, or when the status does not fit
+// a caption, it is shown as text.
+func (h *Handlers) editStatusMessage(
+	chatID int64,
+	messageID int,
+	text string,
+	icon []byte,
+	keyboard tgbotapi.InlineKeyboardMarkup,
+) int {
+	if len(icon) == 0 || utf8.RuneCountInString(text) > captionLimit {
+		return h.editMessage(chatID, messageID, text, keyboard)
+	}
 
-	var text string
-	if err != nil && !isNotFound(err) {
-		text = fmt.Sprintf("❌ Ошибка: %v", escapeMarkdownV2(err.Error()))
-	} else {
-		text = service.FormatConfig(server)
+	hash := imageHash(icon)
+	file := h.fileIDs.File(hash, icon)
+
+	if !h.stateManager.IsPhotoMessage(chatID, messageID) {
+		photo := tgbotapi.NewPhoto(chatID, file)
+		photo.Caption = text
+		photo.ParseMode = tgbotapi.ModeMarkdownV2
+		photo.ReplyMarkup = keyboard
+		sent, ok := h.replaceMessage(chatID, messageID, photo)
+		if !ok {
+			return messageID
+		}
+		h.fileIDs.Remember(hash, sent)
+		h.stateManager.SetPhotoMessage(chatID, sent.MessageID, true)
+		return sent.MessageID
 	}
 
-	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
-	edit.ParseMode = tgbotapi.ModeMarkdownV2
-	edit.ReplyMarkup = pointerTo(SettingsKeyboard())
+	media := tgbotapi.NewInputMediaPhoto(file)
+	media.Caption = text
+	media.ParseMode = tgbotapi.ModeMarkdownV2
+	edit := tgbotapi.EditMessageMediaConfig{
+		BaseEdit: tgbotapi.BaseEdit{
+			ChatID:      chatID,
+			MessageID:   messageID,
+			ReplyMarkup: &keyboard,
+		},
+		Media: media,
+	}
 
-	if _, err := h.bot.Send(edit); err != nil {
-		log.Error().Err(err).Msg("Failed to edit message to settings")
+	sent, err := h.bot.Send(edit)
+	if err != nil {
+		if !isNotModified(err) {
+			log.Error().Err(err).Msg("Failed to edit status photo")
+		}
+		return messageID
 	}
+	h.fileIDs.Remember(hash, sent)
+	return messageID
+}
 
-	h.stateManager.SetState(chatID, StateSettings, messageID)
+// replaceMessage sends msg in place of a bot message and deletes the old one.
+// The old message is kept when sending fails.
+func (h *Handlers) replaceMessage(chatID int64, messageID int, msg tgbotapi.Chattable) (tgbotapi.Message, bool) {
+	sent, err := h.bot.Send(msg)
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Msg("Failed to send replacement message")
+		return tgbotapi.Message{}, false
+	}
+
+	if _, err := h.bot.Request(tgbotapi.NewDeleteMessage(chatID, messageID)); err != nil {
+		log.Warn().Err(err).Int64("chat_id", chatID).Int("message_id", messageID).Msg("Failed to delete replaced message")
+	}
+	h.stateManager.SetPhotoMessage(chatID, messageID, false)
+	return sent, true
+}
+
+func isNotModified(err error) bool {
+	// Telegram rejects edits that change nothing; no need to log this as an error
+	return strings.Contains(err.Error(), "message is not modified")
 }
 
 func isNotFound(err error) bool {
@@ -242,33 +718,5 @@ func isNotFound(err error) bool {
 	return ok
 }
 
-func pointerTo[T any](v T) *T {
-	return &v
-}
-
-func escapeMarkdownV2(s string) string {
-	replacer := strings.NewReplacer(
-		"_", "\\_",
-		"*", "\\*",
-		"[", "\\[",
-		"]", "\\]",
-		"(", "\\(",
-		")", "\\)",
-		"~", "\\~",
-		"`", "\\`",
-		">", "\\>",
-		"#", "\\#",
-		"+", "\\+",
-		"-", "\\-",
-		"=", "\\=",
-		"|", "\\|",
-		"{", "\\{",
-		"}", "\\}",
-		".", "\\.",
-		"!", "\\!",
-	)
-	return replacer.Replace(s)
-}
-
 // Ensure models is used
 var _ = models.Server{}
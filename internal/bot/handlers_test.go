@@ -0,0 +1,391 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/minecraft"
+	"github.com/ykhdr/mss-bot/internal/service"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+// fakeTelegram records what the handlers send and numbers the sent messages
+type fakeTelegram struct {
+	sent   []tgbotapi.Chattable
+	nextID int
+	admins []int64
+	pinErr error
+}
+
+func (f *fakeTelegram) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	f.sent = append(f.sent, c)
+	f.nextID++
+	return tgbotapi.Message{MessageID: 1000 + f.nextID}, nil
+}
+
+func (f *fakeTelegram) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	f.sent = append(f.sent, c)
+	if _, ok := c.(tgbotapi.PinChatMessageConfig); ok && f.pinErr != nil {
+		return nil, f.pinErr
+	}
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+func (f *fakeTelegram) GetChatAdministrators(config tgbotapi.ChatAdministratorsConfig) ([]tgbotapi.ChatMember, error) {
+	members := make([]tgbotapi.ChatMember, 0, len(f.admins))
+	for _, id := range f.admins {
+		members = append(members, tgbotapi.ChatMember{User: &tgbotapi.User{ID: id}, Status: "administrator"})
+	}
+	return members, nil
+}
+
+func (f *fakeTelegram) GetChatMember(config tgbotapi.GetChatMemberConfig) (tgbotapi.ChatMember, error) {
+	return tgbotapi.ChatMember{}, errors.New("not expected")
+}
+
+// lastID returns the ID given to the last sent message
+func (f *fakeTelegram) lastID() int {
+	return 1000 + f.nextID
+}
+
+// texts returns the texts of sent and edited messages
+func (f *fakeTelegram) texts() []string {
+	var texts []string
+	for _, c := range f.sent {
+		switch msg := c.(type) {
+		case tgbotapi.MessageConfig:
+			texts = append(texts, msg.Text)
+		case tgbotapi.EditMessageTextConfig:
+			texts = append(texts, msg.Text)
+		}
+	}
+	return texts
+}
+
+// deleted returns the IDs of the deleted messages
+func (f *fakeTelegram) deleted() []int {
+	var ids []int
+	for _, c := range f.sent {
+		if msg, ok := c.(tgbotapi.DeleteMessageConfig); ok {
+			ids = append(ids, msg.MessageID)
+		}
+	}
+	return ids
+}
+
+// lastMessage returns the last sent message
+func (f *fakeTelegram) lastMessage() tgbotapi.MessageConfig {
+	for i := len(f.sent) - 1; i >= 0; i-- {
+		if msg, ok := f.sent[i].(tgbotapi.MessageConfig); ok {
+			return msg
+		}
+	}
+	return tgbotapi.MessageConfig{}
+}
+
+// savedServer is a server added through fakeServers
+type savedServer struct {
+	chatID, userID int64
+	edition        models.Edition
+	host           string
+	port           int
+	name           string
+}
+
+// fakeServers reports the servers in online as online and keeps the servers added to it
+type fakeServers struct {
+	online  map[string]bool
+	checked []string
+	saved   []savedServer
+	servers map[int64]*models.Server
+	removed []int64
+}
+
+func (f *fakeServers) ListServers(ctx context.Context, chatID int64) ([]*models.Server, error) {
+	var list []*models.Server
+	for _, server := range f.servers {
+		if server.ChatID == chatID {
+			list = append(list, server)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeServers) ListUserServers(ctx context.Context, userID int64) ([]*models.Server, error) {
+	return nil, nil
+}
+
+func (f *fakeServers) GetServer(ctx context.Context, chatID, serverID int64) (*models.Server, error) {
+	server, ok := f.servers[serverID]
+	if !ok || server.ChatID != chatID {
+		return nil, errors.New("server not found")
+	}
+	return server, nil
+}
+
+func (f *fakeServers) RemoveServer(ctx context.Context, chatID, serverID int64) error {
+	if _, err := f.GetServer(ctx, chatID, serverID); err != nil {
+		return err
+	}
+	delete(f.servers, serverID)
+	f.removed = append(f.removed, serverID)
+	return nil
+}
+
+func (f *fakeServers) SetServerConfig(
+	ctx context.Context, chatID, userID int64, edition models.Edition, ip string, port int, name string,
+) error {
+	f.saved = append(f.saved, savedServer{chatID, userID, edition, ip, port, name})
+	return nil
+}
+
+func (f *fakeServers) SetQuery(ctx context.Context, chatID, serverID int64, enabled bool, port int) (*models.Server, error) {
+	server, err := f.GetServer(ctx, chatID, serverID)
+	if err != nil {
+		return nil, err
+	}
+	server.QueryEnabled = enabled
+	server.QueryPort = port
+	return server, nil
+}
+
+func (f *fakeServers) SetProtocolVersion(ctx context.Context, chatID, serverID int64, version int) (*models.Server, error) {
+	server, err := f.GetServer(ctx, chatID, serverID)
+	if err != nil {
+		return nil, err
+	}
+	server.ProtocolVersion = version
+	return server, nil
+}
+
+func (f *fakeServers) GetServerStatus(ctx context.Context, chatID, serverID int64) (*service.ServerStatusResult, error) {
+	server, err := f.GetServer(ctx, chatID, serverID)
+	if err != nil {
+		return nil, err
+	}
+	return f.CheckServer(ctx, server), nil
+}
+
+func (f *fakeServers) CheckServer(ctx context.Context, server *models.Server) *service.ServerStatusResult {
+	return f.CheckAddress(ctx, server.Edition, server.IP, server.Port)
+}
+
+func (f *fakeServers) RecentStatus(server *models.Server, maxAge time.Duration) (*service.ServerStatusResult, bool) {
+	return nil, false
+}
+
+func (f *fakeServers) CheckAddress(
+	ctx context.Context, edition models.Edition, host string, port int,
+) *service.ServerStatusResult {
+	address := minecraft.FormatAddress(host, port)
+	f.checked = append(f.checked, address)
+
+	result := &service.ServerStatusResult{
+		Server: &models.Server{IP: host, Port: port, Edition: edition},
+		Status: &minecraft.ServerStatus{Online: f.online[address], Version: "1.21"},
+	}
+	if !result.Status.Online {
+		result.Error = &minecraft.StatusError{Kind: minecraft.ErrorKindRefused, Err: errors.New("connection refused")}
+	}
+	return result
+}
+
+func (f *fakeServers) CheckPublicAddress(
+	ctx context.Context, edition models.Edition, host string, port int,
+) *service.ServerStatusResult {
+	return f.CheckAddress(ctx, edition, host, port)
+}
+
+// fakeChatSettings keeps the settings of every chat in memory
+type fakeChatSettings struct {
+	settings map[int64]*models.ChatSettings
+}
+
+func (f *fakeChatSettings) GetSettings(ctx context.Context, chatID int64) (*models.ChatSettings, error) {
+	if settings, ok := f.settings[chatID]; ok {
+		return settings, nil
+	}
+	return &models.ChatSettings{ChatID: chatID, Language: string(i18n.EN)}, nil
+}
+
+func (f *fakeChatSettings) TogglePlayerNotifications(ctx context.Context, chatID int64) (*models.ChatSettings, error) {
+	return f.GetSettings(ctx, chatID)
+}
+
+func (f *fakeChatSettings) ToggleAllowEveryone(ctx context.Context, chatID int64) (*models.ChatSettings, error) {
+	return f.GetSettings(ctx, chatID)
+}
+
+func (f *fakeChatSettings) SetLanguage(ctx context.Context, chatID int64, lang i18n.Lang) (*models.ChatSettings, error) {
+	return f.GetSettings(ctx, chatID)
+}
+
+// fakePins keeps the recorded status cards
+type fakePins struct {
+	pins []*models.PinnedMessage
+}
+
+func (f *fakePins) Pin(ctx context.Context, chatID, serverID int64, messageID int) (*models.PinnedMessage, error) {
+	pin := &models.PinnedMessage{ChatID: chatID, ServerID: serverID, MessageID: messageID}
+	f.pins = append(f.pins, pin)
+	return nil, nil
+}
+
+// fakeRCON runs commands on the RCON-enabled servers of fakeServers and records what it is asked to do
+type fakeRCON struct {
+	servers  *fakeServers
+	saved    []string
+	executed []string
+}
+
+func (f *fakeRCON) Enabled() bool {
+	return true
+}
+
+func (f *fakeRCON) ListServers(ctx context.Context, chatID int64) ([]*models.Server, error) {
+	servers, _ := f.servers.ListServers(ctx, chatID)
+	var configured []*models.Server
+	for _, server := range servers {
+		if server.RCONEnabled() {
+			configured = append(configured, server)
+		}
+	}
+	return configured, nil
+}
+
+func (f *fakeRCON) SetRCON(ctx context.Context, chatID, serverID int64, port int, password string) (*models.Server, error) {
+	f.saved = append(f.saved, fmt.Sprintf("%d %s", port, password))
+	return f.servers.GetServer(ctx, chatID, serverID)
+}
+
+func (f *fakeRCON) Execute(ctx context.Context, chatID, serverID int64, command string) (string, error) {
+	f.executed = append(f.executed, fmt.Sprintf("%d: %s", serverID, command))
+	return "", nil
+}
+
+func (f *fakeRCON) Whitelist(ctx context.Context, chatID, serverID int64) (*service.Whitelist, error) {
+	server, err := f.servers.GetServer(ctx, chatID, serverID)
+	if err != nil {
+		return nil, err
+	}
+	return &service.Whitelist{Server: server}, nil
+}
+
+func (f *fakeRCON) AddToWhitelist(
+	ctx context.Context, chatID, serverID int64, player string, actor service.Actor,
+) (string, error) {
+	return f.Execute(ctx, chatID, serverID, "whitelist add "+player)
+}
+
+func (f *fakeRCON) RemoveFromWhitelist(
+	ctx context.Context, chatID, serverID int64, player string, actor service.Actor,
+) (string, error) {
+	return f.Execute(ctx, chatID, serverID, "whitelist remove "+player)
+}
+
+// newTestHandlers creates handlers talking to fakes; chats are in English unless configured otherwise
+func newTestHandlers() (*Handlers, *fakeTelegram, *fakeServers) {
+	api := &fakeTelegram{}
+	servers := &fakeServers{online: make(map[string]bool), servers: make(map[int64]*models.Server)}
+	settings := &fakeChatSettings{settings: make(map[int64]*models.ChatSettings)}
+	rcon := &fakeRCON{servers: servers}
+	return NewHandlers(api, servers, settings, rcon, &fakePins{}, NewStateManager()), api, servers
+}
+
+var (
+	privateChat = &tgbotapi.Chat{ID: 12345, Type: "private"}
+	groupChat   = &tgbotapi.Chat{ID: -100123, Type: "supergroup"}
+	testUser    = &tgbotapi.User{ID: 12345, FirstName: "Steve"}
+)
+
+// commandMessage builds a message with a bot command such as "/set mc.example.com"
+func commandMessage(chat *tgbotapi.Chat, from *tgbotapi.User, text string) *tgbotapi.Message {
+	command, _, _ := strings.Cut(text, " ")
+	return &tgbotapi.Message{
+		MessageID: 1,
+		Chat:      chat,
+		From:      from,
+		Text:      text,
+		Entities:  []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(command)}},
+	}
+}
+
+// replyMessage builds a text message replying to a bot message
+func replyMessage(chat *tgbotapi.Chat, from *tgbotapi.User, replyTo int, text string) *tgbotapi.Message {
+	return &tgbotapi.Message{
+		MessageID:      2,
+		Chat:           chat,
+		From:           from,
+		Text:           text,
+		ReplyToMessage: &tgbotapi.Message{MessageID: replyTo},
+	}
+}
+
+// callbackQuery builds a press of a button of a bot message
+func callbackQuery(chat *tgbotapi.Chat, from *tgbotapi.User, messageID int, data string) *tgbotapi.CallbackQuery {
+	return &tgbotapi.CallbackQuery{
+		ID:      "callback",
+		From:    from,
+		Message: &tgbotapi.Message{MessageID: messageID, Chat: chat},
+		Data:    data,
+	}
+}
+
+func TestDeleteServer_AsksToConfirm(t *testing.T) {
+	h, api, servers := newTestHandlers()
+	servers.servers[7] = &models.Server{ID: 7, ChatID: privateChat.ID, IP: "mc.example.com", Port: 25565, Name: "Main"}
+	ctx := context.Background()
+
+	h.HandleCallback(ctx, callbackQuery(privateChat, testUser, 500, ServerCallback(CallbackDelete, 7)))
+	assert.Empty(t, servers.removed, "one tap does not delete the server")
+	edit := api.sent[len(api.sent)-1].(tgbotapi.EditMessageTextConfig)
+	assert.Equal(t, i18n.EN.T("server_settings.confirm_delete", "Main"), edit.Text)
+	assert.Equal(t, DeleteServerKeyboard(i18n.EN, 7), *edit.ReplyMarkup)
+
+	h.HandleCallback(ctx, callbackQuery(privateChat, testUser, 500, ServerCallback(CallbackDeleteConfirm, 7)))
+	assert.Equal(t, []int64{7}, servers.removed)
+}
+
+func TestQueryPortPrompt_IgnoresUnrelatedGroupMessages(t *testing.T) {
+	h, api, servers := newTestHandlers()
+	api.admins = []int64{testUser.ID}
+	server := &models.Server{ID: 7, ChatID: groupChat.ID, IP: "mc.example.com", Port: 25565}
+	servers.servers[7] = server
+	ctx := context.Background()
+
+	h.HandleCallback(ctx, callbackQuery(groupChat, testUser, 500, ServerCallback(CallbackQueryPort, 7)))
+	promptID := api.lastID()
+
+	h.HandleMessage(ctx, &tgbotapi.Message{MessageID: 3, Chat: groupChat, From: testUser, Text: "25566"})
+	assert.False(t, server.QueryEnabled, "messages not replying to the prompt are not taken for the port")
+
+	h.HandleMessage(ctx, replyMessage(groupChat, testUser, promptID, "25566"))
+	assert.True(t, server.QueryEnabled)
+	assert.Equal(t, 25566, server.QueryPort)
+}
+
+func TestProtocolPrompt_IgnoresUnrelatedGroupMessages(t *testing.T) {
+	h, api, servers := newTestHandlers()
+	api.admins = []int64{testUser.ID}
+	server := &models.Server{ID: 7, ChatID: groupChat.ID, IP: "mc.example.com", Port: 25565}
+	servers.servers[7] = server
+	ctx := context.Background()
+
+	h.HandleCallback(ctx, callbackQuery(groupChat, testUser, 500, ServerCallback(CallbackProtocol, 7)))
+	promptID := api.lastID()
+
+	h.HandleMessage(ctx, &tgbotapi.Message{MessageID: 3, Chat: groupChat, From: testUser, Text: "100"})
+	assert.Equal(t, models.AutoProtocolVersion, server.ProtocolVersion,
+		"messages not replying to the prompt are not taken for the version")
+
+	h.HandleMessage(ctx, replyMessage(groupChat, testUser, promptID, "765"))
+	assert.Equal(t, 765, server.ProtocolVersion)
+}
@@ -0,0 +1,150 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/minecraft"
+	"github.com/ykhdr/mss-bot/internal/service"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+const (
+	// inlineQueryTimeout bounds status checks so that inline queries are answered before the user gives up
+	inlineQueryTimeout = 5 * time.Second
+	// inlineCacheTime is how long Telegram reuses the results of an inline query, in seconds
+	inlineCacheTime = 30
+	// maxInlineResults limits how many servers are checked for one inline query
+	maxInlineResults = 10
+	// inlineStatusMaxAge is how old the last status of a saved server may be to be shown instead of checking it again
+	inlineStatusMaxAge = 2 * time.Minute
+)
+
+// HandleInlineQuery answers an inline query with the status of the typed server address
+// and of the servers the user added in any chat
+func (h *Handlers) HandleInlineQuery(ctx context.Context, query *tgbotapi.InlineQuery) {
+	ctx, cancel := context.WithTimeout(ctx, inlineQueryTimeout)
+	defer cancel()
+
+	// Inline queries are not tied to a chat, so results are in the language of the user's client
+	lang := i18n.Default
+	var userServers []*models.Server
+	if query.From != nil {
+		lang = i18n.Detect(query.From.LanguageCode)
+
+		var err error
+		userServers, err = h.service.ListUserServers(ctx, query.From.ID)
+		if err != nil {
+			log.Error().Err(err).Int64("user_id", query.From.ID).Msg("Failed to list user servers")
+		}
+	}
+
+	targets := inlineTargets(query.Query, userServers)
+	results := make([]any, len(targets))
+	var wg sync.WaitGroup
+	for i, server := range targets {
+		wg.Add(1)
+		go func(i int, server *models.Server) {
+			defer wg.Done()
+			results[i] = inlineArticle(lang, h.inlineStatus(ctx, server))
+		}(i, server)
+	}
+	wg.Wait()
+
+	answer := tgbotapi.InlineConfig{
+		InlineQueryID: query.ID,
+		Results:       results,
+		CacheTime:     inlineCacheTime,
+		IsPersonal:    true, // the results include the user's own servers
+	}
+	if len(results) == 0 {
+		answer.SwitchPMText = lang.T("inline.switch_pm")
+		answer.SwitchPMParameter = "inline"
+	}
+
+	if _, err := h.bot.Request(answer); err != nil {
+		log.Error().Err(err).Str("query", query.Query).Msg("Failed to answer inline query")
+	}
+}
+
+// inlineStatus returns the status of an inline query target. Typed addresses are checked
+// only if they lead to the internet, saved servers are answered from their last status when it is recent.
+func (h *Handlers) inlineStatus(ctx context.Context, server *models.Server) *service.ServerStatusResult {
+	if server.ID == 0 {
+		return h.service.CheckPublicAddress(ctx, server.Edition, server.IP, server.Port)
+	}
+	if result, ok := h.service.RecentStatus(server, inlineStatusMaxAge); ok {
+		return result
+	}
+	return h.service.CheckServer(ctx, server)
+}
+
+// inlineTargets returns the servers to show for an inline query: the typed address, if it looks like one,
+// followed by the user's servers whose name or address contains the query
+func inlineTargets(query string, userServers []*models.Server) []*models.Server {
+	query = strings.TrimSpace(query)
+
+	var targets []*models.Server
+	if typed := parseInlineAddress(query); typed != nil {
+		targets = append(targets, typed)
+	}
+
+	needle := strings.ToLower(query)
+	for _, server := range userServers {
+		if len(targets) == maxInlineResults {
+			break
+		}
+
+		if len(targets) > 0 && targets[0].ID == 0 && sameAddress(targets[0], server) {
+			// Show the user's own record of the typed server, with its name and settings
+			targets[0] = server
+			continue
+		}
+		title := strings.ToLower(service.ServerTitle(server) + " " + minecraft.FormatAddress(server.IP, server.Port))
+		if strings.Contains(title, needle) {
+			targets = append(targets, server)
+		}
+	}
+	return targets
+}
+
+// parseInlineAddress parses an inline query as "[java|bedrock] <address>".
+// Only queries that look like a domain name or an IP address are parsed,
+// so that server names typed to search the user's servers do not trigger lookups.
+func parseInlineAddress(query string) *models.Server {
+	parsed, err := parseSetArguments(query)
+	if err != nil || parsed.Name != "" || !strings.ContainsAny(parsed.Host, ".:") {
+		return nil
+	}
+	return &models.Server{IP: parsed.Host, Port: parsed.Port, Edition: parsed.Edition}
+}
+
+func sameAddress(a, b *models.Server) bool {
+	return strings.EqualFold(a.IP, b.IP) && a.Port == b.Port && a.Edition == b.Edition
+}
+
+// inlineArticle renders a status as an inline query result that posts the status card
+func inlineArticle(lang i18n.Lang, result *service.ServerStatusResult) tgbotapi.InlineQueryResultArticle {
+	article := tgbotapi.NewInlineQueryResultArticleMarkdownV2(
+		inlineResultID(result.Server),
+		service.ServerTitle(result.Server),
+		result.FormatStatus(lang),
+	)
+	article.Description = result.FormatSummary(lang)
+	return article
+}
+
+// inlineResultID identifies a server among inline query results; IDs are limited to 64 bytes
+func inlineResultID(server *models.Server) string {
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%d %s %s", server.ID, server.Edition, minecraft.FormatAddress(server.IP, server.Port))
+	return fmt.Sprintf("%016x", hash.Sum64())
+}
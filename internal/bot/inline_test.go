@@ -0,0 +1,61 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+func TestInlineTargets(t *testing.T) {
+	servers := []*models.Server{
+		{ID: 1, IP: "lobby.example.com", Port: 25565, Name: "Lobby", Edition: models.EditionJava},
+		{ID: 2, IP: "play.example.net", Name: "Survival", Edition: models.EditionJava},
+		{ID: 3, IP: "pe.example.net", Port: 19132, Edition: models.EditionBedrock},
+	}
+
+	targets := inlineTargets("", servers)
+	assert.Len(t, targets, 3, "an empty query lists all the user's servers")
+
+	targets = inlineTargets("surv", servers)
+	require.Len(t, targets, 1, "names do not look like addresses")
+	assert.Equal(t, int64(2), targets[0].ID)
+
+	targets = inlineTargets("mc.hypixel.net", servers)
+	require.Len(t, targets, 1)
+	assert.Zero(t, targets[0].ID)
+	assert.Equal(t, "mc.hypixel.net", targets[0].IP)
+	assert.Zero(t, targets[0].Port, "the SRV record of addresses without a port is followed")
+
+	targets = inlineTargets("play.example.net", servers)
+	require.Len(t, targets, 1, "the typed address is shown once")
+	assert.Equal(t, int64(2), targets[0].ID, "the user's record of the typed server is preferred")
+
+	targets = inlineTargets("bedrock pe.example.net", servers)
+	require.Len(t, targets, 1)
+	assert.Equal(t, int64(3), targets[0].ID)
+}
+
+func TestParseInlineAddress(t *testing.T) {
+	server := parseInlineAddress("[2001:db8::1]:25566")
+	require.NotNil(t, server)
+	assert.Equal(t, 25566, server.Port)
+
+	server = parseInlineAddress("pe.example.net:19132")
+	require.NotNil(t, server)
+	assert.Equal(t, models.EditionBedrock, server.Edition)
+
+	for _, query := range []string{"", "lobby", "play.example.net My Server", "bad host.com:99999"} {
+		assert.Nil(t, parseInlineAddress(query), query)
+	}
+}
+
+func TestInlineResultID(t *testing.T) {
+	a := inlineResultID(&models.Server{IP: "mc.example.com", Port: 25565})
+	b := inlineResultID(&models.Server{IP: "mc.example.com", Port: 25566})
+
+	assert.Len(t, a, 16)
+	assert.NotEqual(t, a, b)
+}
@@ -1,6 +1,16 @@
 package bot
 
-import tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/service"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
 
 // Callback data constants
 const (
@@ -8,44 +18,390 @@ const (
 	CallbackSettings = "settings"
 	CallbackBack     = "back"
 	CallbackRefresh  = "refresh"
+	CallbackDelete   = "delete"
+	CallbackServer   = "server"
+	CallbackMods     = "mods"
+
+	CallbackDeleteConfirm = "delete_confirm"
+
+	CallbackQuery     = "query"
+	CallbackQueryPort = "query_port"
+	CallbackProtocol  = "protocol"
+
+	CallbackRCONSetup   = "rcon_setup"
+	CallbackRCONServer  = "rcon_server"
+	CallbackRCONConfirm = "rcon_confirm"
+	CallbackRCONCancel  = "rcon_cancel"
+
+	CallbackWhitelist       = "whitelist"
+	CallbackWhitelistAdd    = "wl_add"
+	CallbackWhitelistPlayer = "wl_player"
+	CallbackWhitelistRemove = "wl_remove"
+
+	CallbackPin       = "pin"
+	CallbackPinCancel = "pin_cancel"
+
+	CallbackPlayerNotifications = "players_notify"
+	CallbackLanguage            = "language"
+	CallbackAllowEveryone       = "allow_everyone"
+
+	CallbackAddServer    = "add_server"
+	CallbackWizardSave   = "wizard_save"
+	CallbackWizardCancel = "wizard_cancel"
 )
 
+// callbackSeparator separates the action from the server ID in server-scoped callback data
+const callbackSeparator = ":"
+
+// ServerCallback builds server-scoped callback data, e.g. "status:42"
+func ServerCallback(action string, serverID int64) string {
+	return fmt.Sprintf("%s%s%d", action, callbackSeparator, serverID)
+}
+
+// PageCallback builds paginated server-scoped callback data, e.g. "mods:42:1"
+func PageCallback(action string, serverID int64, page int) string {
+	return fmt.Sprintf("%s%s%d", ServerCallback(action, serverID), callbackSeparator, page)
+}
+
+// PlayerCallback builds player-scoped server callback data, e.g. "wl_remove:42:Steve"
+func PlayerCallback(action string, serverID int64, player string) string {
+	return ServerCallback(action, serverID) + callbackSeparator + player
+}
+
+// isConfigCallback reports whether a callback changes the servers or settings of a chat,
+// so that in groups only the users allowed to configure the bot may press it
+func isConfigCallback(action string) bool {
+	switch action {
+	case CallbackSettings, CallbackServer, CallbackQuery, CallbackQueryPort, CallbackProtocol,
+		CallbackDelete, CallbackDeleteConfirm, CallbackPlayerNotifications, CallbackLanguage, CallbackAddServer:
+		return true
+	}
+	return false
+}
+
+// isGroupChat reports whether a chat ID belongs to a group; Telegram gives groups negative IDs
+func isGroupChat(chatID int64) bool {
+	return chatID < 0
+}
+
+// ParseCallback splits callback data into the action and an optional server ID.
+// A zero server ID means the callback is not scoped to a server.
+func ParseCallback(data string) (action string, serverID int64) {
+	action, idStr, found := strings.Cut(data, callbackSeparator)
+	if !found {
+		return data, 0
+	}
+	idStr, _, _ = strings.Cut(idStr, callbackSeparator)
+
+	serverID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return action, 0
+	}
+	return action, serverID
+}
+
+// CallbackPage returns the page of paginated callback data, zero when there is none
+func CallbackPage(data string) int {
+	parts := strings.Split(data, callbackSeparator)
+	if len(parts) < 3 {
+		return 0
+	}
+
+	page, err := strconv.Atoi(parts[2])
+	if err != nil || page < 0 {
+		return 0
+	}
+	return page
+}
+
+// CallbackPlayer returns the player of player-scoped callback data, empty when there is none
+func CallbackPlayer(data string) string {
+	parts := strings.SplitN(data, callbackSeparator, 3)
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[2]
+}
+
 // MainMenuKeyboard returns the main menu inline keyboard
-func MainMenuKeyboard() tgbotapi.InlineKeyboardMarkup {
+func MainMenuKeyboard(lang i18n.Lang) tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📊 Статус", CallbackStatus),
-			tgbotapi.NewInlineKeyboardButtonData("⚙️ Настройки", CallbackSettings),
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.status"), CallbackStatus),
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.settings"), CallbackSettings),
 		),
 	)
 }
 
-// StatusKeyboard returns the status view inline keyboard
-func StatusKeyboard() tgbotapi.InlineKeyboardMarkup {
+// ServerListKeyboard returns a keyboard for picking one of the chat's servers
+func ServerListKeyboard(lang i18n.Lang, servers []*models.Server) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(servers)+1)
+	for _, server := range servers {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🎮 "+service.ServerTitle(server), ServerCallback(CallbackStatus, server.ID)),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.back"), CallbackBack),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// StatusKeyboard returns the status view inline keyboard for a server.
+// When withList is set, a button leading back to the server list is added;
+// withMods adds a button opening the server's mod list.
+func StatusKeyboard(lang i18n.Lang, serverID int64, withList, withMods bool) tgbotapi.InlineKeyboardMarkup {
+	firstRow := tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.refresh"), ServerCallback(CallbackRefresh, serverID)),
+	)
+	if withMods {
+		firstRow = append(firstRow,
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.mods"), PageCallback(CallbackMods, serverID, 0)))
+	}
+
+	rows := [][]tgbotapi.InlineKeyboardButton{firstRow}
+	if withList {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.all_servers"), CallbackStatus),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.back"), CallbackBack),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// ModsKeyboard returns the mod list keyboard with page navigation and a button back to the server status
+func ModsKeyboard(lang i18n.Lang, serverID int64, page, pages int) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if pages > 1 {
+		var nav []tgbotapi.InlineKeyboardButton
+		if page > 0 {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("⬅️", PageCallback(CallbackMods, serverID, page-1)))
+		}
+		if page < pages-1 {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("➡️", PageCallback(CallbackMods, serverID, page+1)))
+		}
+		rows = append(rows, nav)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.back"), ServerCallback(CallbackStatus, serverID)),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// SettingsKeyboard returns the settings view inline keyboard with a button per server
+// and chat-wide toggles, including the language switch
+func SettingsKeyboard(
+	lang i18n.Lang, servers []*models.Server, settings *models.ChatSettings,
+) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(servers)+5)
+	for _, server := range servers {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⚙️ "+service.ServerTitle(server), ServerCallback(CallbackServer, server.ID)),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.add_server"), CallbackAddServer),
+	))
+	if settings != nil {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(
+				lang.T("btn.player_notifications", onOff(lang, settings.PlayerNotifications)),
+				CallbackPlayerNotifications,
+			),
+		))
+		if isGroupChat(settings.ChatID) {
+			access := lang.T("access.admins")
+			if settings.AllowEveryone {
+				access = lang.T("access.everyone")
+			}
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.access", access), CallbackAllowEveryone),
+			))
+		}
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.language", lang.Name()), CallbackLanguage),
+	))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.back"), CallbackBack),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// WizardConfirmKeyboard returns the keyboard confirming a server added with the setup wizard
+func WizardConfirmKeyboard(lang i18n.Lang) tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🔄 Обновить", CallbackRefresh),
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.save"), CallbackWizardSave),
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.cancel"), CallbackWizardCancel),
+		),
+	)
+}
+
+// ServerSettingsKeyboard returns the settings keyboard of a single server
+func ServerSettingsKeyboard(lang i18n.Lang, server *models.Server) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if !server.IsBedrock() {
+		queryRow := tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.query", onOff(lang, server.QueryEnabled)),
+				ServerCallback(CallbackQuery, server.ID)),
+		)
+		if server.QueryEnabled {
+			queryRow = append(queryRow,
+				tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.query_port"), ServerCallback(CallbackQueryPort, server.ID)),
+			)
+		}
+		rows = append(rows, queryRow, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.protocol", protocolLabel(lang, server.ProtocolVersion)),
+				ServerCallback(CallbackProtocol, server.ID)),
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.rcon", onOff(lang, server.RCONEnabled())),
+				ServerCallback(CallbackRCONSetup, server.ID)),
+		))
+		if server.RCONEnabled() {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.whitelist"), ServerCallback(CallbackWhitelist, server.ID)),
+			))
+		}
+	}
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.delete"), ServerCallback(CallbackDelete, server.ID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.back"), CallbackSettings),
 		),
+	)
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// DeleteServerKeyboard returns the keyboard confirming the deletion of a server
+func DeleteServerKeyboard(lang i18n.Lang, serverID int64) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("◀️ Назад", CallbackBack),
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.delete"), ServerCallback(CallbackDeleteConfirm, serverID)),
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.back"), ServerCallback(CallbackServer, serverID)),
 		),
 	)
 }
 
-// SettingsKeyboard returns the settings view inline keyboard
-func SettingsKeyboard() tgbotapi.InlineKeyboardMarkup {
+// RCONServerKeyboard returns a keyboard for picking the server a console command is run on
+func RCONServerKeyboard(lang i18n.Lang, servers []*models.Server) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(servers)+1)
+	for _, server := range servers {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🖥 "+service.ServerTitle(server), ServerCallback(CallbackRCONServer, server.ID)),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.cancel"), CallbackRCONCancel),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// RCONConfirmKeyboard returns the confirmation keyboard of a dangerous console command
+func RCONConfirmKeyboard(lang i18n.Lang, serverID int64) tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("◀️ Назад", CallbackBack),
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.run"), ServerCallback(CallbackRCONConfirm, serverID)),
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.cancel"), CallbackRCONCancel),
+		),
+	)
+}
+
+// PinServerKeyboard returns a keyboard for picking the server whose status card is pinned
+func PinServerKeyboard(lang i18n.Lang, servers []*models.Server) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(servers)+1)
+	for _, server := range servers {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📌 "+service.ServerTitle(server), ServerCallback(CallbackPin, server.ID)),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.cancel"), CallbackPinCancel),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// WhitelistKeyboard returns the whitelist keyboard with a button per player on the page,
+// page navigation and a button for adding players
+func WhitelistKeyboard(
+	lang i18n.Lang, serverID int64, players []string, page, pages int,
+) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i := 0; i < len(players); i += 2 {
+		row := tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("👤 "+players[i], PlayerCallback(CallbackWhitelistPlayer, serverID, players[i])),
+		)
+		if i+1 < len(players) {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData("👤 "+players[i+1],
+				PlayerCallback(CallbackWhitelistPlayer, serverID, players[i+1])))
+		}
+		rows = append(rows, row)
+	}
+	if pages > 1 {
+		var nav []tgbotapi.InlineKeyboardButton
+		if page > 0 {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("⬅️", PageCallback(CallbackWhitelist, serverID, page-1)))
+		}
+		if page < pages-1 {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("➡️", PageCallback(CallbackWhitelist, serverID, page+1)))
+		}
+		rows = append(rows, nav)
+	}
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.add"), ServerCallback(CallbackWhitelistAdd, serverID)),
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.refresh"), PageCallback(CallbackWhitelist, serverID, page)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.back"), ServerCallback(CallbackServer, serverID)),
+		),
+	)
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// WhitelistPlayerKeyboard returns the keyboard confirming the removal of a player from the whitelist
+func WhitelistPlayerKeyboard(lang i18n.Lang, serverID int64, player string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.delete"),
+				PlayerCallback(CallbackWhitelistRemove, serverID, player)),
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.back"), ServerCallback(CallbackWhitelist, serverID)),
 		),
 	)
 }
 
 // BackKeyboard returns a simple back button keyboard
-func BackKeyboard() tgbotapi.InlineKeyboardMarkup {
+func BackKeyboard(lang i18n.Lang) tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("◀️ Назад", CallbackBack),
+			tgbotapi.NewInlineKeyboardButtonData(lang.T("btn.back"), CallbackBack),
 		),
 	)
 }
+
+// protocolLabel renders the protocol version setting for button labels
+func protocolLabel(lang i18n.Lang, version int) string {
+	if version == models.AutoProtocolVersion {
+		return lang.T("auto")
+	}
+	return strconv.Itoa(version)
+}
+
+// onOff renders a toggle state for button labels
+func onOff(lang i18n.Lang, enabled bool) string {
+	if enabled {
+		return lang.T("on")
+	}
+	return lang.T("off")
+}
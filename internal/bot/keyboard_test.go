@@ -4,10 +4,14 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
 )
 
 func TestMainMenuKeyboard(t *testing.T) {
-	kb := MainMenuKeyboard()
+	kb := MainMenuKeyboard(i18n.RU)
 
 	assert.Len(t, kb.InlineKeyboard, 1)
 	assert.Len(t, kb.InlineKeyboard[0], 2)
@@ -20,29 +24,190 @@ func TestMainMenuKeyboard(t *testing.T) {
 }
 
 func TestStatusKeyboard(t *testing.T) {
-	kb := StatusKeyboard()
+	kb := StatusKeyboard(i18n.RU, 42, false, false)
 
 	assert.Len(t, kb.InlineKeyboard, 2)
 
 	assert.Equal(t, "🔄 Обновить", kb.InlineKeyboard[0][0].Text)
-	assert.Equal(t, CallbackRefresh, *kb.InlineKeyboard[0][0].CallbackData)
+	assert.Equal(t, "refresh:42", *kb.InlineKeyboard[0][0].CallbackData)
 
 	assert.Equal(t, "◀️ Назад", kb.InlineKeyboard[1][0].Text)
 	assert.Equal(t, CallbackBack, *kb.InlineKeyboard[1][0].CallbackData)
 }
 
-func TestSettingsKeyboard(t *testing.T) {
-	kb := SettingsKeyboard()
+func TestStatusKeyboard_WithList(t *testing.T) {
+	kb := StatusKeyboard(i18n.RU, 42, true, false)
+
+	assert.Len(t, kb.InlineKeyboard, 3)
+	assert.Equal(t, CallbackStatus, *kb.InlineKeyboard[1][0].CallbackData)
+	assert.Equal(t, CallbackBack, *kb.InlineKeyboard[2][0].CallbackData)
+}
+
+func TestStatusKeyboard_WithMods(t *testing.T) {
+	kb := StatusKeyboard(i18n.RU, 42, false, true)
 
+	assert.Len(t, kb.InlineKeyboard[0], 2)
+	assert.Equal(t, "🧩 Моды", kb.InlineKeyboard[0][1].Text)
+	assert.Equal(t, "mods:42:0", *kb.InlineKeyboard[0][1].CallbackData)
+}
+
+func TestModsKeyboard(t *testing.T) {
+	kb := ModsKeyboard(i18n.RU, 42, 0, 1)
 	assert.Len(t, kb.InlineKeyboard, 1)
+	assert.Equal(t, "status:42", *kb.InlineKeyboard[0][0].CallbackData)
+
+	kb = ModsKeyboard(i18n.RU, 42, 0, 3)
+	assert.Len(t, kb.InlineKeyboard, 2)
 	assert.Len(t, kb.InlineKeyboard[0], 1)
+	assert.Equal(t, "mods:42:1", *kb.InlineKeyboard[0][0].CallbackData)
 
-	assert.Equal(t, "◀️ Назад", kb.InlineKeyboard[0][0].Text)
-	assert.Equal(t, CallbackBack, *kb.InlineKeyboard[0][0].CallbackData)
+	kb = ModsKeyboard(i18n.RU, 42, 1, 3)
+	assert.Equal(t, "mods:42:0", *kb.InlineKeyboard[0][0].CallbackData)
+	assert.Equal(t, "mods:42:2", *kb.InlineKeyboard[0][1].CallbackData)
+
+	kb = ModsKeyboard(i18n.RU, 42, 2, 3)
+	assert.Len(t, kb.InlineKeyboard[0], 1)
+	assert.Equal(t, "mods:42:1", *kb.InlineKeyboard[0][0].CallbackData)
+}
+
+func TestServerListKeyboard(t *testing.T) {
+	servers := []*models.Server{
+		{ID: 1, IP: "lobby.example.com", Port: 25565, Name: "Lobby"},
+		{ID: 2, IP: "survival.example.com", Port: 25566},
+	}
+
+	kb := ServerListKeyboard(i18n.RU, servers)
+
+	assert.Len(t, kb.InlineKeyboard, 3)
+	assert.Equal(t, "🎮 Lobby", kb.InlineKeyboard[0][0].Text)
+	assert.Equal(t, "status:1", *kb.InlineKeyboard[0][0].CallbackData)
+	assert.Equal(t, "🎮 survival.example.com:25566", kb.InlineKeyboard[1][0].Text)
+	assert.Equal(t, "status:2", *kb.InlineKeyboard[1][0].CallbackData)
+	assert.Equal(t, CallbackBack, *kb.InlineKeyboard[2][0].CallbackData)
+}
+
+func TestSettingsKeyboard(t *testing.T) {
+	kb := SettingsKeyboard(i18n.RU, nil, nil)
+
+	assert.Len(t, kb.InlineKeyboard, 3)
+	assert.Len(t, kb.InlineKeyboard[2], 1)
+
+	assert.Equal(t, "➕ Добавить сервер", kb.InlineKeyboard[0][0].Text)
+	assert.Equal(t, CallbackAddServer, *kb.InlineKeyboard[0][0].CallbackData)
+	assert.Equal(t, "◀️ Назад", kb.InlineKeyboard[2][0].Text)
+	assert.Equal(t, CallbackBack, *kb.InlineKeyboard[2][0].CallbackData)
+}
+
+func TestSettingsKeyboard_Language(t *testing.T) {
+	kb := SettingsKeyboard(i18n.RU, nil, nil)
+	assert.Equal(t, "🌐 Язык: Русский", kb.InlineKeyboard[1][0].Text)
+	assert.Equal(t, CallbackLanguage, *kb.InlineKeyboard[1][0].CallbackData)
+
+	kb = SettingsKeyboard(i18n.EN, nil, nil)
+	assert.Equal(t, "🌐 Language: English", kb.InlineKeyboard[1][0].Text)
+	assert.Equal(t, "◀️ Back", kb.InlineKeyboard[2][0].Text)
+}
+
+func TestSettingsKeyboard_WithServers(t *testing.T) {
+	servers := []*models.Server{
+		{ID: 7, IP: "mc.example.com", Port: 25565, Name: "Main"},
+	}
+
+	kb := SettingsKeyboard(i18n.RU, servers, nil)
+
+	assert.Len(t, kb.InlineKeyboard, 4)
+	assert.Equal(t, "⚙️ Main", kb.InlineKeyboard[0][0].Text)
+	assert.Equal(t, "server:7", *kb.InlineKeyboard[0][0].CallbackData)
+	assert.Equal(t, CallbackAddServer, *kb.InlineKeyboard[1][0].CallbackData)
+}
+
+func TestSettingsKeyboard_PlayerNotificationsToggle(t *testing.T) {
+	kb := SettingsKeyboard(i18n.RU, nil, &models.ChatSettings{PlayerNotifications: true})
+
+	assert.Len(t, kb.InlineKeyboard, 4)
+	assert.Equal(t, "🔔 Вход/выход игроков: вкл", kb.InlineKeyboard[1][0].Text)
+	assert.Equal(t, CallbackPlayerNotifications, *kb.InlineKeyboard[1][0].CallbackData)
+
+	kb = SettingsKeyboard(i18n.RU, nil, &models.ChatSettings{})
+	assert.Equal(t, "🔔 Вход/выход игроков: выкл", kb.InlineKeyboard[1][0].Text)
+}
+
+func TestSettingsKeyboard_Access(t *testing.T) {
+	kb := SettingsKeyboard(i18n.RU, nil, &models.ChatSettings{ChatID: 12345})
+	for _, row := range kb.InlineKeyboard {
+		assert.NotEqual(t, CallbackAllowEveryone, *row[0].CallbackData, "private chats have no access toggle")
+	}
+
+	kb = SettingsKeyboard(i18n.RU, nil, &models.ChatSettings{ChatID: -100123})
+	require.Len(t, kb.InlineKeyboard, 5)
+	assert.Equal(t, "👥 Настраивать могут: админы", kb.InlineKeyboard[2][0].Text)
+	assert.Equal(t, CallbackAllowEveryone, *kb.InlineKeyboard[2][0].CallbackData)
+
+	kb = SettingsKeyboard(i18n.RU, nil, &models.ChatSettings{ChatID: -100123, AllowEveryone: true})
+	assert.Equal(t, "👥 Настраивать могут: все", kb.InlineKeyboard[2][0].Text)
+}
+
+func TestWizardConfirmKeyboard(t *testing.T) {
+	kb := WizardConfirmKeyboard(i18n.RU)
+
+	require.Len(t, kb.InlineKeyboard, 1)
+	require.Len(t, kb.InlineKeyboard[0], 2)
+	assert.Equal(t, CallbackWizardSave, *kb.InlineKeyboard[0][0].CallbackData)
+	assert.Equal(t, CallbackWizardCancel, *kb.InlineKeyboard[0][1].CallbackData)
+}
+
+func TestIsConfigCallback(t *testing.T) {
+	for _, action := range []string{
+		CallbackSettings, CallbackServer, CallbackQuery, CallbackDelete, CallbackDeleteConfirm, CallbackLanguage,
+		CallbackAddServer,
+	} {
+		assert.True(t, isConfigCallback(action), action)
+	}
+	for _, action := range []string{
+		CallbackStatus, CallbackRefresh, CallbackMods, CallbackBack, CallbackAllowEveryone, CallbackWizardSave,
+	} {
+		assert.False(t, isConfigCallback(action), action)
+	}
+}
+
+func TestParseCallback(t *testing.T) {
+	tests := []struct {
+		data     string
+		action   string
+		serverID int64
+	}{
+		{"status", CallbackStatus, 0},
+		{"status:42", CallbackStatus, 42},
+		{"refresh:7", CallbackRefresh, 7},
+		{"delete:abc", CallbackDelete, 0},
+		{"mods:42:3", CallbackMods, 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			action, serverID := ParseCallback(tt.data)
+			assert.Equal(t, tt.action, action)
+			assert.Equal(t, tt.serverID, serverID)
+		})
+	}
+}
+
+func TestServerCallback(t *testing.T) {
+	assert.Equal(t, "status:42", ServerCallback(CallbackStatus, 42))
+}
+
+func TestPageCallback(t *testing.T) {
+	data := PageCallback(CallbackMods, 42, 3)
+
+	assert.Equal(t, "mods:42:3", data)
+	assert.Equal(t, 3, CallbackPage(data))
+	assert.Equal(t, 0, CallbackPage("mods:42"))
+	assert.Equal(t, 0, CallbackPage("mods:42:-1"))
+	assert.Equal(t, 0, CallbackPage("mods:42:x"))
 }
 
 func TestBackKeyboard(t *testing.T) {
-	kb := BackKeyboard()
+	kb := BackKeyboard(i18n.RU)
 
 	assert.Len(t, kb.InlineKeyboard, 1)
 	assert.Len(t, kb.InlineKeyboard[0], 1)
@@ -56,4 +221,116 @@ func TestCallbackConstants(t *testing.T) {
 	assert.Equal(t, "settings", CallbackSettings)
 	assert.Equal(t, "back", CallbackBack)
 	assert.Equal(t, "refresh", CallbackRefresh)
+	assert.Equal(t, "delete", CallbackDelete)
+}
+
+func TestServerSettingsKeyboard(t *testing.T) {
+	server := &models.Server{ID: 7, IP: "mc.example.com", Port: 25565}
+
+	kb := ServerSettingsKeyboard(i18n.RU, server)
+
+	assert.Len(t, kb.InlineKeyboard, 4)
+	assert.Len(t, kb.InlineKeyboard[0], 1)
+	assert.Equal(t, "📡 Query: выкл", kb.InlineKeyboard[0][0].Text)
+	assert.Equal(t, "query:7", *kb.InlineKeyboard[0][0].CallbackData)
+	assert.Equal(t, "🧬 Протокол: авто", kb.InlineKeyboard[1][0].Text)
+	assert.Equal(t, "protocol:7", *kb.InlineKeyboard[1][0].CallbackData)
+	assert.Equal(t, "🖥 RCON: выкл", kb.InlineKeyboard[1][1].Text)
+	assert.Equal(t, "rcon_setup:7", *kb.InlineKeyboard[1][1].CallbackData)
+	assert.Equal(t, "delete:7", *kb.InlineKeyboard[2][0].CallbackData)
+	assert.Equal(t, CallbackSettings, *kb.InlineKeyboard[3][0].CallbackData)
+
+	server.QueryEnabled = true
+	server.ProtocolVersion = 765
+	server.RCONPort = 25575
+	server.RCONPassword = "sealed"
+	kb = ServerSettingsKeyboard(i18n.RU, server)
+
+	assert.Len(t, kb.InlineKeyboard[0], 2)
+	assert.Equal(t, "query_port:7", *kb.InlineKeyboard[0][1].CallbackData)
+	assert.Equal(t, "🧬 Протокол: 765", kb.InlineKeyboard[1][0].Text)
+	assert.Equal(t, "🖥 RCON: вкл", kb.InlineKeyboard[1][1].Text)
+	assert.Equal(t, "whitelist:7", *kb.InlineKeyboard[2][0].CallbackData)
+}
+
+func TestDeleteServerKeyboard(t *testing.T) {
+	kb := DeleteServerKeyboard(i18n.RU, 7)
+
+	assert.Len(t, kb.InlineKeyboard, 1)
+	assert.Equal(t, "🗑 Удалить", kb.InlineKeyboard[0][0].Text)
+	assert.Equal(t, "delete_confirm:7", *kb.InlineKeyboard[0][0].CallbackData)
+	assert.Equal(t, "server:7", *kb.InlineKeyboard[0][1].CallbackData)
+}
+
+func TestRCONKeyboards(t *testing.T) {
+	servers := []*models.Server{
+		{ID: 1, IP: "lobby.example.com", Port: 25565, Name: "Lobby"},
+		{ID: 2, IP: "survival.example.com", Port: 25565},
+	}
+
+	kb := RCONServerKeyboard(i18n.RU, servers)
+	require.Len(t, kb.InlineKeyboard, 3)
+	assert.Equal(t, "🖥 Lobby", kb.InlineKeyboard[0][0].Text)
+	assert.Equal(t, "rcon_server:2", *kb.InlineKeyboard[1][0].CallbackData)
+	assert.Equal(t, CallbackRCONCancel, *kb.InlineKeyboard[2][0].CallbackData)
+
+	kb = RCONConfirmKeyboard(i18n.RU, 2)
+	require.Len(t, kb.InlineKeyboard, 1)
+	assert.Equal(t, "rcon_confirm:2", *kb.InlineKeyboard[0][0].CallbackData)
+	assert.Equal(t, CallbackRCONCancel, *kb.InlineKeyboard[0][1].CallbackData)
+}
+
+func TestPinServerKeyboard(t *testing.T) {
+	servers := []*models.Server{
+		{ID: 1, IP: "lobby.example.com", Port: 25565, Name: "Lobby"},
+		{ID: 2, IP: "survival.example.com", Port: 25565},
+	}
+
+	kb := PinServerKeyboard(i18n.RU, servers)
+	require.Len(t, kb.InlineKeyboard, 3)
+	assert.Equal(t, "📌 Lobby", kb.InlineKeyboard[0][0].Text)
+	assert.Equal(t, "pin:2", *kb.InlineKeyboard[1][0].CallbackData)
+	assert.Equal(t, CallbackPinCancel, *kb.InlineKeyboard[2][0].CallbackData)
+}
+
+func TestWhitelistKeyboard(t *testing.T) {
+	kb := WhitelistKeyboard(i18n.RU, 7, []string{"Alex", "Steve", "jeb_"}, 0, 1)
+
+	require.Len(t, kb.InlineKeyboard, 4)
+	assert.Len(t, kb.InlineKeyboard[0], 2)
+	assert.Equal(t, "👤 Alex", kb.InlineKeyboard[0][0].Text)
+	assert.Equal(t, "wl_player:7:Alex", *kb.InlineKeyboard[0][0].CallbackData)
+	assert.Len(t, kb.InlineKeyboard[1], 1)
+	assert.Equal(t, "wl_add:7", *kb.InlineKeyboard[2][0].CallbackData)
+	assert.Equal(t, "whitelist:7:0", *kb.InlineKeyboard[2][1].CallbackData)
+	assert.Equal(t, "server:7", *kb.InlineKeyboard[3][0].CallbackData)
+
+	kb = WhitelistKeyboard(i18n.RU, 7, nil, 1, 3)
+	require.Len(t, kb.InlineKeyboard, 3)
+	assert.Equal(t, "whitelist:7:0", *kb.InlineKeyboard[0][0].CallbackData)
+	assert.Equal(t, "whitelist:7:2", *kb.InlineKeyboard[0][1].CallbackData)
+
+	kb = WhitelistPlayerKeyboard(i18n.RU, 7, "Steve")
+	assert.Equal(t, "wl_remove:7:Steve", *kb.InlineKeyboard[0][0].CallbackData)
+	assert.Equal(t, "whitelist:7", *kb.InlineKeyboard[0][1].CallbackData)
+}
+
+func TestPlayerCallback(t *testing.T) {
+	data := PlayerCallback(CallbackWhitelistRemove, 42, "Steve")
+
+	assert.Equal(t, "wl_remove:42:Steve", data)
+	action, serverID := ParseCallback(data)
+	assert.Equal(t, CallbackWhitelistRemove, action)
+	assert.Equal(t, int64(42), serverID)
+	assert.Equal(t, "Steve", CallbackPlayer(data))
+	assert.Equal(t, "", CallbackPlayer("whitelist:42"))
+}
+
+func TestServerSettingsKeyboard_Bedrock(t *testing.T) {
+	server := &models.Server{ID: 7, IP: "bedrock.example.com", Port: 19132, Edition: models.EditionBedrock}
+
+	kb := ServerSettingsKeyboard(i18n.RU, server)
+
+	assert.Len(t, kb.InlineKeyboard, 2)
+	assert.Equal(t, "delete:7", *kb.InlineKeyboard[0][0].CallbackData)
 }
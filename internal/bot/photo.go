@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// captionLimit is the maximum length of a photo caption; longer statuses are sent as text
+const captionLimit = 1024
+
+// FileIDCache maps the hashes of uploaded images to their Telegram file IDs,
+// so every server icon is uploaded only once
+type FileIDCache struct {
+	mu  sync.RWMutex
+	ids map[string]string
+}
+
+// NewFileIDCache creates an empty file ID cache
+func NewFileIDCache() *FileIDCache {
+	return &FileIDCache{
+		ids: make(map[string]string),
+	}
+}
+
+// File returns the cached file ID of an image, or the image itself when it was never uploaded
+func (c *FileIDCache) File(hash string, data []byte) tgbotapi.RequestFileData {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if id, ok := c.ids[hash]; ok {
+		return tgbotapi.FileID(id)
+	}
+	return tgbotapi.FileBytes{Name: hash + ".png", Bytes: data}
+}
+
+// Remember stores the file ID of the photo in a sent message under the image hash
+func (c *FileIDCache) Remember(hash string, message tgbotapi.Message) {
+	if len(message.Photo) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Sizes are ordered from smallest to largest
+	c.ids[hash] = message.Photo[len(message.Photo)-1].FileID
+}
+
+// imageHash returns the hex SHA-256 of image data
+func imageHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,33 @@
+package bot
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileIDCache(t *testing.T) {
+	cache := NewFileIDCache()
+	icon := []byte("\x89PNG\r\n\x1a\n")
+	hash := imageHash(icon)
+
+	file := cache.File(hash, icon)
+	assert.Equal(t, tgbotapi.FileBytes{Name: hash + ".png", Bytes: icon}, file)
+
+	// Messages without a photo are ignored
+	cache.Remember(hash, tgbotapi.Message{})
+	assert.IsType(t, tgbotapi.FileBytes{}, cache.File(hash, icon))
+
+	cache.Remember(hash, tgbotapi.Message{Photo: []tgbotapi.PhotoSize{
+		{FileID: "small", Width: 32, Height: 32},
+		{FileID: "large", Width: 64, Height: 64},
+	}})
+	assert.Equal(t, tgbotapi.FileID("large"), cache.File(hash, icon))
+}
+
+func TestImageHash(t *testing.T) {
+	assert.Equal(t, imageHash([]byte("icon")), imageHash([]byte("icon")))
+	assert.NotEqual(t, imageHash([]byte("icon")), imageHash([]byte("other icon")))
+	assert.Len(t, imageHash(nil), 64)
+}
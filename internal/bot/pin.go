@@ -0,0 +1,161 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/monitor"
+)
+
+// messageGoneErrors are descriptions of Telegram errors meaning a message can no longer be edited by the bot
+var messageGoneErrors = []string{
+	"message to edit not found",
+	"message can't be edited",
+	"message_id_invalid",
+	"chat not found",
+	"not enough rights",
+	"have no rights",
+}
+
+// handlePin posts a status card of the chat's server and pins it, asking which server when there are several
+func (h *Handlers) handlePin(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	lang := i18n.FromContext(ctx)
+	if !h.isAdminMessage(message) {
+		h.sendText(chatID, deniedText(lang, message, "pin.admins_only"))
+		return
+	}
+
+	servers, err := h.service.ListServers(ctx, chatID)
+	if err != nil {
+		h.sendText(chatID, lang.T("error", err))
+		return
+	}
+
+	switch len(servers) {
+	case 0:
+		h.sendText(chatID, lang.T("pin.no_servers"))
+	case 1:
+		h.pinStatus(ctx, chatID, servers[0].ID)
+	default:
+		msg := tgbotapi.NewMessage(chatID, lang.T("pin.pick"))
+		msg.ParseMode = tgbotapi.ModeMarkdownV2
+		msg.ReplyMarkup = PinServerKeyboard(lang, servers)
+		if _, err := h.bot.Send(msg); err != nil {
+			log.Error().Err(err).Msg("Failed to send pin server picker")
+		}
+	}
+}
+
+// pickPinServer pins the status card of the server picked by a chat admin in place of the picker
+func (h *Handlers) pickPinServer(ctx context.Context, callback *tgbotapi.CallbackQuery, serverID int64) {
+	chatID := callback.Message.Chat.ID
+	if !h.isChatAdmin(callback.Message.Chat, callback.From.ID) {
+		return
+	}
+
+	h.deletePinPicker(chatID, callback.Message.MessageID)
+	h.pinStatus(ctx, chatID, serverID)
+}
+
+// cancelPin removes the pin server picker
+func (h *Handlers) cancelPin(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	if !h.isChatAdmin(callback.Message.Chat, callback.From.ID) {
+		return
+	}
+	h.deletePinPicker(callback.Message.Chat.ID, callback.Message.MessageID)
+}
+
+func (h *Handlers) deletePinPicker(chatID int64, messageID int) {
+	if _, err := h.bot.Request(tgbotapi.NewDeleteMessage(chatID, messageID)); err != nil {
+		log.Warn().Err(err).Int64("chat_id", chatID).Msg("Failed to delete pin server picker")
+	}
+}
+
+// pinStatus posts and pins a status card that is kept up to date, replacing the previous card of the server.
+// Cards that could not be pinned are not updated, and the previous card stays until /pin succeeds.
+func (h *Handlers) pinStatus(ctx context.Context, chatID, serverID int64) {
+	lang := i18n.FromContext(ctx)
+	result, err := h.service.GetServerStatus(ctx, chatID, serverID)
+	if err != nil {
+		if isNotFound(err) {
+			h.sendText(chatID, lang.T("pin.not_found"))
+		} else {
+			h.sendText(chatID, lang.T("error", err))
+		}
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, result.FormatPinnedStatus(lang, time.Now()))
+	msg.ParseMode = tgbotapi.ModeMarkdownV2
+	sent, err := h.bot.Send(msg)
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Msg("Failed to send status card")
+		return
+	}
+
+	pin := tgbotapi.PinChatMessageConfig{ChatID: chatID, MessageID: sent.MessageID, DisableNotification: true}
+	if _, err := h.bot.Request(pin); err != nil {
+		log.Warn().Err(err).Int64("chat_id", chatID).Msg("Failed to pin status card")
+		h.sendText(chatID, lang.T("pin.no_rights"))
+		return
+	}
+
+	previous, err := h.pinService.Pin(ctx, chatID, serverID, sent.MessageID)
+	if err != nil {
+		h.sendText(chatID, lang.T("error.save", err))
+		return
+	}
+
+	if previous != nil {
+		unpin := tgbotapi.UnpinChatMessageConfig{ChatID: chatID, MessageID: previous.MessageID}
+		if _, err := h.bot.Request(unpin); err != nil {
+			log.Debug().Err(err).Int64("chat_id", chatID).Int("message_id", previous.MessageID).Msg("Failed to unpin replaced status card")
+		}
+	}
+}
+
+// EditMessage replaces the text of a bot message with MarkdownV2 formatted text.
+// Messages that were deleted or that the bot may no longer edit are reported as monitor.ErrMessageGone.
+func (b *Bot) EditMessage(ctx context.Context, chatID int64, messageID int, text string) error {
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit.ParseMode = tgbotapi.ModeMarkdownV2
+
+	_, err := b.api.Send(edit)
+	switch {
+	case err == nil || isNotModified(err):
+		return nil
+	case isMessageGone(err):
+		return fmt.Errorf("%w: %v", monitor.ErrMessageGone, err)
+	default:
+		return err
+	}
+}
+
+// isMessageGone reports whether a Telegram error means the message can no longer be edited:
+// it was deleted, or the bot was removed from the chat or lost its rights there
+func isMessageGone(err error) bool {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.Code == http.StatusForbidden {
+		return true
+	}
+
+	description := strings.ToLower(apiErr.Message)
+	for _, gone := range messageGoneErrors {
+		if strings.Contains(description, gone) {
+			return true
+		}
+	}
+	return false
+}
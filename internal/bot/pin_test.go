@@ -0,0 +1,53 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+func TestIsMessageGone(t *testing.T) {
+	gone := []error{
+		&tgbotapi.Error{Code: 400, Message: "Bad Request: message to edit not found"},
+		&tgbotapi.Error{Code: 400, Message: "Bad Request: message can't be edited"},
+		&tgbotapi.Error{Code: 400, Message: "Bad Request: chat not found"},
+		&tgbotapi.Error{Code: 403, Message: "Forbidden: bot was kicked from the supergroup chat"},
+		fmt.Errorf("wrapped: %w", &tgbotapi.Error{Code: 400, Message: "Bad Request: MESSAGE_ID_INVALID"}),
+	}
+	for _, err := range gone {
+		assert.True(t, isMessageGone(err), err.Error())
+	}
+
+	kept := []error{
+		&tgbotapi.Error{Code: 429, Message: "Too Many Requests: retry after 5"},
+		&tgbotapi.Error{Code: 400, Message: "Bad Request: can't parse entities"},
+		errors.New("connection reset by peer"),
+	}
+	for _, err := range kept {
+		assert.False(t, isMessageGone(err), err.Error())
+	}
+}
+
+func TestPinStatus_NotPinnedIsNotTracked(t *testing.T) {
+	h, api, servers := newTestHandlers()
+	servers.servers[7] = &models.Server{ID: 7, ChatID: privateChat.ID, IP: "mc.example.com", Port: 25565}
+	pins := h.pinService.(*fakePins)
+
+	api.pinErr = &tgbotapi.Error{Code: 400, Message: "Bad Request: not enough rights to pin a message"}
+	h.HandleCommand(context.Background(), commandMessage(privateChat, testUser, "/pin"))
+	assert.Equal(t, i18n.EN.T("pin.no_rights"), api.lastMessage().Text)
+	assert.Empty(t, pins.pins, "a card that is not pinned is not updated")
+
+	api.pinErr = nil
+	h.HandleCommand(context.Background(), commandMessage(privateChat, testUser, "/pin"))
+	require.Len(t, pins.pins, 1, "a later /pin retries")
+	assert.Equal(t, api.lastID(), pins.pins[0].MessageID)
+}
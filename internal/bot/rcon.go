@@ -0,0 +1,266 @@
+package bot
+
+import (
+	"context"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/service"
+)
+
+// handleRCON runs a console command on the chat's server, asking which one when RCON is set up for several
+func (h *Handlers) handleRCON(ctx context.Context, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	lang := i18n.FromContext(ctx)
+	if message.From == nil || !h.isAdminMessage(message) {
+		h.sendText(chatID, deniedText(lang, message, "rcon.admins_only"))
+		return
+	}
+
+	command := message.CommandArguments()
+	if command == "" {
+		msg := tgbotapi.NewMessage(chatID, lang.T("rcon.usage"))
+		msg.ParseMode = tgbotapi.ModeMarkdownV2
+		if _, err := h.bot.Send(msg); err != nil {
+			log.Error().Err(err).Msg("Failed to send error message")
+		}
+		return
+	}
+
+	servers, err := h.rconService.ListServers(ctx, chatID)
+	if err != nil {
+		h.sendText(chatID, lang.T("error", err))
+		return
+	}
+
+	switch len(servers) {
+	case 0:
+		h.sendText(chatID, lang.T("rcon.no_servers"))
+	case 1:
+		h.runRCON(ctx, chatID, 0, message.From.ID, servers[0].ID, command)
+	default:
+		msg := tgbotapi.NewMessage(chatID, lang.T("rcon.pick", service.EscapeMarkdown(command)))
+		msg.ParseMode = tgbotapi.ModeMarkdownV2
+		msg.ReplyMarkup = RCONServerKeyboard(lang, servers)
+		sent, err := h.bot.Send(msg)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to send rcon server picker")
+			return
+		}
+		h.stateManager.SetPendingCommand(chatID, PendingCommand{
+			UserID:    message.From.ID,
+			Command:   command,
+			MessageID: sent.MessageID,
+		})
+	}
+}
+
+// runRCON runs a command, asking for confirmation first when it is dangerous.
+// The result replaces the bot message with messageID, or is sent as a new message when it is zero.
+func (h *Handlers) runRCON(ctx context.Context, chatID int64, messageID int, userID, serverID int64, command string) {
+	lang := i18n.FromContext(ctx)
+	server, err := h.service.GetServer(ctx, chatID, serverID)
+	if err != nil {
+		h.showRCONResult(chatID, messageID, "❌ "+service.EscapeMarkdown(service.RCONErrorText(lang, err)))
+		return
+	}
+
+	if service.IsDangerousCommand(command) {
+		text := lang.T("rcon.confirm", service.EscapeMarkdown(command), service.EscapeMarkdown(service.ServerTitle(server)))
+		promptID := h.showRCONPrompt(chatID, messageID, text, RCONConfirmKeyboard(lang, serverID))
+		if promptID == 0 {
+			return
+		}
+		h.stateManager.SetPendingCommand(chatID, PendingCommand{
+			UserID:    userID,
+			Command:   command,
+			ServerID:  serverID,
+			MessageID: promptID,
+		})
+		return
+	}
+
+	h.executeRCON(ctx, chatID, messageID, serverID, command)
+}
+
+func (h *Handlers) executeRCON(ctx context.Context, chatID int64, messageID int, serverID int64, command string) {
+	lang := i18n.FromContext(ctx)
+	server, err := h.service.GetServer(ctx, chatID, serverID)
+	if err == nil {
+		var output string
+		output, err = h.rconService.Execute(ctx, chatID, serverID, command)
+		if err == nil {
+			h.showRCONResult(chatID, messageID, service.FormatRCONOutput(lang, server, command, output))
+			return
+		}
+	}
+
+	h.showRCONResult(chatID, messageID, lang.T("rcon.failed", service.EscapeMarkdown(service.RCONErrorText(lang, err))))
+}
+
+// pickRCONServer runs the pending command of the user on the picked server
+func (h *Handlers) pickRCONServer(ctx context.Context, callback *tgbotapi.CallbackQuery, serverID int64) {
+	chatID := callback.Message.Chat.ID
+	if !h.isChatAdmin(callback.Message.Chat, callback.From.ID) {
+		return
+	}
+
+	pending, ok := h.takePendingCommand(callback)
+	if !ok {
+		return
+	}
+	h.runRCON(ctx, chatID, callback.Message.MessageID, callback.From.ID, serverID, pending.Command)
+}
+
+// confirmRCON runs the dangerous command the user confirmed
+func (h *Handlers) confirmRCON(ctx context.Context, callback *tgbotapi.CallbackQuery, serverID int64) {
+	chatID := callback.Message.Chat.ID
+	if !h.isChatAdmin(callback.Message.Chat, callback.From.ID) {
+		return
+	}
+
+	// Only the confirmation shown for this command and server runs it
+	pending, ok := h.takePendingCommand(callback)
+	if !ok || pending.ServerID != serverID {
+		return
+	}
+	h.executeRCON(ctx, chatID, callback.Message.MessageID, serverID, pending.Command)
+}
+
+// cancelRCON drops the pending command of the user
+func (h *Handlers) cancelRCON(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	if _, ok := h.takePendingCommand(callback); !ok {
+		return
+	}
+	h.showRCONResult(chatID, callback.Message.MessageID, i18n.FromContext(ctx).T("rcon.canceled"))
+}
+
+// takePendingCommand takes the command the pressed button belongs to if the user who pressed it is running it,
+// or if an anonymous admin is running it and the user is an admin
+func (h *Handlers) takePendingCommand(callback *tgbotapi.CallbackQuery) (PendingCommand, bool) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	if pending, ok := h.stateManager.TakePendingCommand(chatID, callback.From.ID, messageID); ok {
+		return pending, true
+	}
+	if !h.fromAnonymousAdmin(callback) {
+		return PendingCommand{}, false
+	}
+	return h.stateManager.TakePendingCommand(chatID, groupAnonymousBotID, messageID)
+}
+
+// showRCONPrompt shows a message with a keyboard in place of messageID, or as a new message when it is zero.
+// It returns the ID of the shown message, or zero when it could not be sent.
+func (h *Handlers) showRCONPrompt(
+	chatID int64, messageID int, text string, keyboard tgbotapi.InlineKeyboardMarkup,
+) int {
+	if messageID != 0 {
+		return h.editMessage(chatID, messageID, text, keyboard)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = tgbotapi.ModeMarkdownV2
+	msg.ReplyMarkup = keyboard
+	sent, err := h.bot.Send(msg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to send rcon prompt")
+		return 0
+	}
+	return sent.MessageID
+}
+
+// showRCONResult shows a MarkdownV2 text without a keyboard in place of messageID, or as a new message when it is zero
+func (h *Handlers) showRCONResult(chatID int64, messageID int, text string) {
+	if messageID != 0 {
+		edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+		edit.ParseMode = tgbotapi.ModeMarkdownV2
+		if _, err := h.bot.Send(edit); err != nil && !isNotModified(err) {
+			log.Error().Err(err).Msg("Failed to edit rcon message")
+		}
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = tgbotapi.ModeMarkdownV2
+	if _, err := h.bot.Send(msg); err != nil {
+		log.Error().Err(err).Msg("Failed to send rcon result")
+	}
+}
+
+// promptRCON asks the admin who pressed the button for the server's RCON port and password
+func (h *Handlers) promptRCON(ctx context.Context, callback *tgbotapi.CallbackQuery, serverID int64) {
+	chatID := callback.Message.Chat.ID
+	lang := i18n.FromContext(ctx)
+	if !h.isChatAdmin(callback.Message.Chat, callback.From.ID) {
+		h.sendText(chatID, lang.T("rcon.setup_admins_only"))
+		return
+	}
+	if !h.rconService.Enabled() {
+		h.sendText(chatID, "⚠️ "+service.RCONErrorText(lang, service.ErrRCONDisabled)+".")
+		return
+	}
+
+	server, err := h.service.GetServer(ctx, chatID, serverID)
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Int64("server_id", serverID).Msg("Failed to get server")
+		return
+	}
+
+	text := lang.T("rcon.prompt", service.EscapeMarkdown(service.ServerTitle(server)))
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = tgbotapi.ModeMarkdownV2
+	msg.ReplyMarkup = tgbotapi.ForceReply{
+		ForceReply:            true,
+		Selective:             true,
+		InputFieldPlaceholder: lang.T("rcon.placeholder"),
+	}
+	sent, err := h.bot.Send(msg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to send rcon prompt")
+		return
+	}
+
+	h.awaitInput(callback, InputRCON, serverID, sent.MessageID)
+}
+
+func (h *Handlers) handleRCONInput(ctx context.Context, message *tgbotapi.Message, serverID int64) {
+	chatID := message.Chat.ID
+	lang := i18n.FromContext(ctx)
+
+	// The reply holds the password, so it should not stay in the chat
+	if _, err := h.bot.Request(tgbotapi.NewDeleteMessage(chatID, message.MessageID)); err != nil {
+		log.Warn().Err(err).Int64("chat_id", chatID).Msg("Failed to delete rcon password message")
+	}
+
+	settings, err := parseRCONSettings(message.Text)
+	if err != nil {
+		h.sendText(chatID, lang.T("rcon.invalid"))
+		return
+	}
+
+	if _, err := h.rconService.SetRCON(ctx, chatID, serverID, settings.Port, settings.Password); err != nil {
+		h.sendText(chatID, lang.T("error.save", err))
+		return
+	}
+
+	if messageID := h.stateManager.GetMessageID(chatID); messageID != 0 {
+		h.showServerSettings(ctx, chatID, messageID, serverID)
+	}
+
+	if settings.Port == 0 {
+		h.sendText(chatID, lang.T("rcon.disabled"))
+	} else {
+		h.sendText(chatID, lang.T("rcon.saved"))
+	}
+}
+
+// sendText sends a plain text message
+func (h *Handlers) sendText(chatID int64, text string) {
+	if _, err := h.bot.Send(tgbotapi.NewMessage(chatID, text)); err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Msg("Failed to send message")
+	}
+}
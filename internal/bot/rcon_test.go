@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+func TestRCONPrompt_IgnoresUnrelatedGroupMessages(t *testing.T) {
+	h, api, servers := newTestHandlers()
+	api.admins = []int64{testUser.ID}
+	servers.servers[7] = &models.Server{ID: 7, ChatID: groupChat.ID, IP: "mc.example.com", Port: 25565, Name: "Main"}
+	rcon := h.rconService.(*fakeRCON)
+	ctx := context.Background()
+
+	h.HandleCallback(ctx, callbackQuery(groupChat, testUser, 500, ServerCallback(CallbackRCONSetup, 7)))
+	promptID := api.lastID()
+
+	h.HandleMessage(ctx, &tgbotapi.Message{MessageID: 3, Chat: groupChat, From: testUser, Text: "hello everyone"})
+	assert.Empty(t, api.deleted(), "messages not replying to the prompt stay in the chat")
+	assert.Empty(t, rcon.saved, "nor are they taken for the password")
+
+	h.HandleMessage(ctx, replyMessage(groupChat, testUser, promptID, "25575 secret"))
+	assert.Equal(t, []string{"25575 secret"}, rcon.saved)
+	assert.Equal(t, []int{2}, api.deleted(), "the reply with the password is deleted")
+}
+
+func TestRCONConfirm_StaleKeyboard(t *testing.T) {
+	h, api, servers := newTestHandlers()
+	for _, id := range []int64{1, 2} {
+		servers.servers[id] = &models.Server{
+			ID: id, ChatID: privateChat.ID, IP: "mc.example.com", Port: 25565, RCONPort: 25575, RCONPassword: "secret",
+		}
+	}
+	rcon := h.rconService.(*fakeRCON)
+	ctx := context.Background()
+
+	h.HandleCommand(ctx, commandMessage(privateChat, testUser, "/rcon kick Bob"))
+	kickID := api.lastID()
+	h.HandleCallback(ctx, callbackQuery(privateChat, testUser, kickID, ServerCallback(CallbackRCONServer, 1)))
+
+	// Another command replaces the pending one before the first is confirmed
+	h.HandleCommand(ctx, commandMessage(privateChat, testUser, "/rcon stop"))
+	stopID := api.lastID()
+
+	h.HandleCallback(ctx, callbackQuery(privateChat, testUser, kickID, ServerCallback(CallbackRCONConfirm, 1)))
+	assert.Empty(t, rcon.executed, "the old confirmation does not run the new command")
+
+	h.HandleCallback(ctx, callbackQuery(privateChat, testUser, stopID, ServerCallback(CallbackRCONServer, 2)))
+	h.HandleCallback(ctx, callbackQuery(privateChat, testUser, stopID, ServerCallback(CallbackRCONConfirm, 2)))
+	assert.Equal(t, []string{"2: stop"}, rcon.executed)
+}
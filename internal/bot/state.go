@@ -1,6 +1,11 @@
 package bot
 
-import "sync"
+import (
+	"sync"
+	"time"
+
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
 
 // State represents the current state of the bot for a specific chat
 type State int
@@ -16,10 +21,95 @@ const (
 	StateSettings
 )
 
+// InputKind identifies what a pending text prompt expects
+type InputKind int
+
+const (
+	// InputNone - no prompt is pending
+	InputNone InputKind = iota
+	// InputQueryPort - a query port for a server
+	InputQueryPort
+	// InputProtocolVersion - a handshake protocol version for a server
+	InputProtocolVersion
+	// InputRCON - the RCON port and password of a server
+	InputRCON
+	// InputWhitelistAdd - names of players to add to the whitelist of a server
+	InputWhitelistAdd
+)
+
+// PendingInput is a prompt waiting for a text reply from a specific user
+type PendingInput struct {
+	Kind     InputKind
+	UserID   int64
+	ServerID int64
+	// PromptID is the message the answer must reply to in group chats
+	PromptID int
+	// Anonymous lets anonymous admins of the group answer the prompt too
+	Anonymous bool
+
+	createdAt time.Time
+}
+
+// PendingCommand is a console command waiting for its issuer to pick a server or confirm it
+type PendingCommand struct {
+	UserID  int64
+	Command string
+	// ServerID is the server the command is confirmed for, zero while the server is being picked
+	ServerID int64
+	// MessageID is the picker or confirmation message whose buttons act on the command
+	MessageID int
+
+	createdAt time.Time
+}
+
+// wizardTimeout is how long a setup wizard, a prompt or a console command waits for the next answer
+// before it is abandoned
+const wizardTimeout = 10 * time.Minute
+
+// WizardStep is the answer a setup wizard is waiting for
+type WizardStep int
+
+const (
+	// WizardAddress - the address of the server
+	WizardAddress WizardStep = iota + 1
+	// WizardName - the name of the server
+	WizardName
+	// WizardConfirm - a press of the save or cancel button
+	WizardConfirm
+)
+
+// SetupWizard is a server being added step by step by one user
+type SetupWizard struct {
+	UserID int64
+	Step   WizardStep
+	// PromptID is the message the answer must reply to in group chats
+	PromptID int
+	// SettingsID is the settings message to refresh once the server is saved
+	SettingsID int
+
+	Edition models.Edition
+	Host    string
+	Port    int
+	Name    string
+
+	updatedAt time.Time
+}
+
+// wizardKey identifies a setup wizard; members of a group run their wizards independently
+type wizardKey struct {
+	chatID int64
+	userID int64
+}
+
 // StateManager manages bot states for different chats
 type StateManager struct {
-	mu     sync.RWMutex
-	states map[int64]chatState
+	mu       sync.RWMutex
+	states   map[int64]chatState
+	pending  map[int64]PendingInput
+	commands map[int64]PendingCommand
+	photos   map[int64]int
+	wizards  map[wizardKey]SetupWizard
+	now      func() time.Time
 }
 
 type chatState struct {
@@ -30,7 +120,12 @@ type chatState struct {
 // NewStateManager creates a new state manager
 func NewStateManager() *StateManager {
 	return &StateManager{
-		states: make(map[int64]chatState),
+		states:   make(map[int64]chatState),
+		pending:  make(map[int64]PendingInput),
+		commands: make(map[int64]PendingCommand),
+		photos:   make(map[int64]int),
+		wizards:  make(map[wizardKey]SetupWizard),
+		now:      time.Now,
 	}
 }
 
@@ -79,3 +174,151 @@ func (sm *StateManager) ClearState(chatID int64) {
 func (sm *StateManager) IsInState(chatID int64, state State) bool {
 	return sm.GetState(chatID) == state
 }
+
+// SetPendingInput registers a prompt in a chat, replacing any previous one
+func (sm *StateManager) SetPendingInput(chatID int64, input PendingInput) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	input.createdAt = sm.now()
+	sm.pending[chatID] = input
+}
+
+// GetPendingInput returns the chat's prompt if it has not timed out
+func (sm *StateManager) GetPendingInput(chatID int64) (PendingInput, bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return sm.pendingInputLocked(chatID)
+}
+
+// TakePendingInput returns and clears the chat's prompt if it was issued to the user and has not timed out
+func (sm *StateManager) TakePendingInput(chatID, userID int64) (PendingInput, bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	input, ok := sm.pendingInputLocked(chatID)
+	if !ok || input.UserID != userID {
+		return PendingInput{}, false
+	}
+
+	delete(sm.pending, chatID)
+	return input, true
+}
+
+// pendingInputLocked returns the live prompt of a chat, dropping it once timed out; sm.mu must be held
+func (sm *StateManager) pendingInputLocked(chatID int64) (PendingInput, bool) {
+	input, ok := sm.pending[chatID]
+	if !ok {
+		return PendingInput{}, false
+	}
+	if sm.now().Sub(input.createdAt) >= wizardTimeout {
+		delete(sm.pending, chatID)
+		return PendingInput{}, false
+	}
+	return input, true
+}
+
+// SetPendingCommand registers a console command in a chat, replacing any previous one
+func (sm *StateManager) SetPendingCommand(chatID int64, command PendingCommand) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	command.createdAt = sm.now()
+	sm.commands[chatID] = command
+}
+
+// TakePendingCommand returns and clears the chat's console command if it was issued by the user,
+// belongs to the bot message with messageID and has not timed out
+func (sm *StateManager) TakePendingCommand(chatID, userID int64, messageID int) (PendingCommand, bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	command, ok := sm.commands[chatID]
+	if !ok {
+		return PendingCommand{}, false
+	}
+	if sm.now().Sub(command.createdAt) >= wizardTimeout {
+		delete(sm.commands, chatID)
+		return PendingCommand{}, false
+	}
+	if command.UserID != userID || command.MessageID != messageID {
+		return PendingCommand{}, false
+	}
+
+	delete(sm.commands, chatID)
+	return command, true
+}
+
+// SetPhotoMessage records whether a bot message in a chat is a photo.
+// Only the latest photo message of a chat is tracked.
+func (sm *StateManager) SetPhotoMessage(chatID int64, messageID int, photo bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if photo {
+		sm.photos[chatID] = messageID
+	} else if sm.photos[chatID] == messageID {
+		delete(sm.photos, chatID)
+	}
+}
+
+// IsPhotoMessage checks if a bot message in a chat is a photo
+func (sm *StateManager) IsPhotoMessage(chatID int64, messageID int) bool {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	id, ok := sm.photos[chatID]
+	return ok && id == messageID
+}
+
+// SetWizard starts or advances the setup wizard of wizard.UserID in a chat and restarts its timeout.
+// Wizards of other users are kept; the user's previous wizard in the chat is replaced.
+func (sm *StateManager) SetWizard(chatID int64, wizard SetupWizard) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	now := sm.now()
+	for id, w := range sm.wizards {
+		if now.Sub(w.updatedAt) >= wizardTimeout {
+			delete(sm.wizards, id)
+		}
+	}
+
+	wizard.updatedAt = now
+	sm.wizards[wizardKey{chatID: chatID, userID: wizard.UserID}] = wizard
+}
+
+// GetWizard returns the user's setup wizard in a chat if it has not timed out
+func (sm *StateManager) GetWizard(chatID, userID int64) (SetupWizard, bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return sm.wizardLocked(chatID, userID)
+}
+
+// TakeWizard returns and ends the user's setup wizard in a chat if it has not timed out
+func (sm *StateManager) TakeWizard(chatID, userID int64) (SetupWizard, bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	wizard, ok := sm.wizardLocked(chatID, userID)
+	if ok {
+		delete(sm.wizards, wizardKey{chatID: chatID, userID: userID})
+	}
+	return wizard, ok
+}
+
+// wizardLocked returns the user's live wizard of a chat, dropping it once timed out; sm.mu must be held
+func (sm *StateManager) wizardLocked(chatID, userID int64) (SetupWizard, bool) {
+	key := wizardKey{chatID: chatID, userID: userID}
+	wizard, ok := sm.wizards[key]
+	if !ok {
+		return SetupWizard{}, false
+	}
+	if sm.now().Sub(wizard.updatedAt) >= wizardTimeout {
+		delete(sm.wizards, key)
+		return SetupWizard{}, false
+	}
+	return wizard, true
+}
@@ -2,6 +2,7 @@ package bot
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -72,3 +73,141 @@ func TestStateManager_UpdateState(t *testing.T) {
 	sm.SetState(12345, StateSettings, 100)
 	assert.Equal(t, StateSettings, sm.GetState(12345))
 }
+
+func TestStateManager_PendingInput(t *testing.T) {
+	sm := NewStateManager()
+
+	_, ok := sm.TakePendingInput(12345, 1)
+	assert.False(t, ok)
+
+	sm.SetPendingInput(12345, PendingInput{Kind: InputQueryPort, UserID: 1, ServerID: 7})
+
+	_, ok = sm.TakePendingInput(12345, 2)
+	assert.False(t, ok, "other users cannot answer the prompt")
+
+	input, ok := sm.TakePendingInput(12345, 1)
+	assert.True(t, ok)
+	assert.Equal(t, InputQueryPort, input.Kind)
+	assert.Equal(t, int64(7), input.ServerID)
+
+	_, ok = sm.TakePendingInput(12345, 1)
+	assert.False(t, ok, "a prompt is answered once")
+}
+
+func TestStateManager_PendingCommand(t *testing.T) {
+	sm := NewStateManager()
+
+	sm.SetPendingCommand(12345, PendingCommand{UserID: 1, Command: "stop", ServerID: 7, MessageID: 100})
+
+	_, ok := sm.TakePendingCommand(12345, 2, 100)
+	assert.False(t, ok, "other users cannot confirm the command")
+
+	_, ok = sm.TakePendingCommand(12345, 1, 99)
+	assert.False(t, ok, "buttons of other messages do not act on the command")
+
+	command, ok := sm.TakePendingCommand(12345, 1, 100)
+	assert.True(t, ok)
+	assert.Equal(t, "stop", command.Command)
+	assert.Equal(t, int64(7), command.ServerID)
+
+	_, ok = sm.TakePendingCommand(12345, 1, 100)
+	assert.False(t, ok, "a command is confirmed once")
+}
+
+func TestStateManager_PendingTimeout(t *testing.T) {
+	sm := NewStateManager()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	sm.now = func() time.Time { return now }
+
+	sm.SetPendingInput(12345, PendingInput{Kind: InputRCON, UserID: 1, ServerID: 7, PromptID: 100})
+	sm.SetPendingCommand(12345, PendingCommand{UserID: 1, Command: "stop", ServerID: 7, MessageID: 101})
+
+	now = now.Add(wizardTimeout - time.Second)
+	_, ok := sm.GetPendingInput(12345)
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	_, ok = sm.GetPendingInput(12345)
+	assert.False(t, ok, "the prompt timed out")
+	_, ok = sm.TakePendingInput(12345, 1)
+	assert.False(t, ok)
+	_, ok = sm.TakePendingCommand(12345, 1, 101)
+	assert.False(t, ok, "the command timed out")
+}
+
+func TestStateManager_PhotoMessage(t *testing.T) {
+	sm := NewStateManager()
+
+	assert.False(t, sm.IsPhotoMessage(12345, 100))
+
+	sm.SetPhotoMessage(12345, 100, true)
+	assert.True(t, sm.IsPhotoMessage(12345, 100))
+	assert.False(t, sm.IsPhotoMessage(12345, 101))
+	assert.False(t, sm.IsPhotoMessage(54321, 100))
+
+	// A text message that is not the tracked photo leaves it alone
+	sm.SetPhotoMessage(12345, 101, false)
+	assert.True(t, sm.IsPhotoMessage(12345, 100))
+
+	sm.SetPhotoMessage(12345, 100, false)
+	assert.False(t, sm.IsPhotoMessage(12345, 100))
+}
+
+func TestStateManager_Wizard(t *testing.T) {
+	sm := NewStateManager()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	sm.now = func() time.Time { return now }
+
+	sm.SetWizard(12345, SetupWizard{UserID: 1, Step: WizardAddress, PromptID: 10})
+
+	_, ok := sm.GetWizard(12345, 2)
+	assert.False(t, ok, "only the user who started the wizard may answer it")
+
+	wizard, ok := sm.GetWizard(12345, 1)
+	assert.True(t, ok)
+	assert.Equal(t, WizardAddress, wizard.Step)
+
+	wizard.Step = WizardName
+	now = now.Add(wizardTimeout - time.Second)
+	sm.SetWizard(12345, wizard)
+
+	now = now.Add(wizardTimeout - time.Second)
+	wizard, ok = sm.GetWizard(12345, 1)
+	assert.True(t, ok, "every answer restarts the timeout")
+	assert.Equal(t, WizardName, wizard.Step)
+
+	_, ok = sm.TakeWizard(12345, 1)
+	assert.True(t, ok)
+	_, ok = sm.GetWizard(12345, 1)
+	assert.False(t, ok, "taken wizards are over")
+}
+
+func TestStateManager_Wizard_PerUser(t *testing.T) {
+	sm := NewStateManager()
+
+	sm.SetWizard(-100123, SetupWizard{UserID: 1, Step: WizardName, Name: "First"})
+	sm.SetWizard(-100123, SetupWizard{UserID: 2, Step: WizardAddress})
+
+	first, ok := sm.GetWizard(-100123, 1)
+	assert.True(t, ok, "a second member's wizard does not replace the first one")
+	assert.Equal(t, "First", first.Name)
+
+	_, ok = sm.TakeWizard(-100123, 2)
+	assert.True(t, ok)
+	_, ok = sm.GetWizard(-100123, 1)
+	assert.True(t, ok, "ending a wizard keeps the other members' ones")
+}
+
+func TestStateManager_Wizard_Timeout(t *testing.T) {
+	sm := NewStateManager()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	sm.now = func() time.Time { return now }
+
+	sm.SetWizard(12345, SetupWizard{UserID: 1, Step: WizardAddress})
+
+	now = now.Add(wizardTimeout)
+	_, ok := sm.GetWizard(12345, 1)
+	assert.False(t, ok)
+	_, ok = sm.TakeWizard(12345, 1)
+	assert.False(t, ok)
+}
@@ -0,0 +1,155 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/service"
+)
+
+// handleWhitelistCallback handles the whitelist buttons, which only chat admins may use
+func (h *Handlers) handleWhitelistCallback(ctx context.Context, callback *tgbotapi.CallbackQuery, action string, serverID int64) {
+	chatID := callback.Message.Chat.ID
+	if !h.isChatAdmin(callback.Message.Chat, callback.From.ID) {
+		h.sendText(chatID, i18n.FromContext(ctx).T("whitelist.admins_only"))
+		return
+	}
+
+	messageID := callback.Message.MessageID
+	switch action {
+	case CallbackWhitelist:
+		h.showWhitelist(ctx, chatID, messageID, serverID, CallbackPage(callback.Data))
+	case CallbackWhitelistAdd:
+		h.promptWhitelistAdd(ctx, callback, serverID)
+	case CallbackWhitelistPlayer:
+		h.showWhitelistPlayer(ctx, chatID, messageID, serverID, CallbackPlayer(callback.Data))
+	case CallbackWhitelistRemove:
+		h.removeFromWhitelist(ctx, chatID, messageID, serverID, CallbackPlayer(callback.Data), actorOf(callback.From))
+	}
+}
+
+// showWhitelist shows a page of the server's whitelist, read from the server over RCON
+func (h *Handlers) showWhitelist(ctx context.Context, chatID int64, messageID int, serverID int64, page int) {
+	lang := i18n.FromContext(ctx)
+	whitelist, err := h.rconService.Whitelist(ctx, chatID, serverID)
+	if err != nil {
+		if isNotFound(err) {
+			h.showSettings(ctx, chatID, messageID)
+			return
+		}
+		log.Error().Err(err).Int64("chat_id", chatID).Int64("server_id", serverID).Msg("Failed to get whitelist")
+		text := lang.T("whitelist.fetch_failed", service.EscapeMarkdown(service.RCONErrorText(lang, err)))
+		messageID = h.editMessage(chatID, messageID, text, WhitelistKeyboard(lang, serverID, nil, 0, 1))
+		h.stateManager.SetState(chatID, StateSettings, messageID)
+		return
+	}
+
+	pages := whitelist.Pages()
+	page = min(page, pages-1)
+	keyboard := WhitelistKeyboard(lang, serverID, whitelist.Page(page), page, pages)
+	messageID = h.editMessage(chatID, messageID, whitelist.Format(lang, page), keyboard)
+	h.stateManager.SetState(chatID, StateSettings, messageID)
+}
+
+// showWhitelistPlayer asks to confirm the removal of a player from the whitelist
+func (h *Handlers) showWhitelistPlayer(ctx context.Context, chatID int64, messageID int, serverID int64, player string) {
+	server, err := h.service.GetServer(ctx, chatID, serverID)
+	if err != nil {
+		h.showSettings(ctx, chatID, messageID)
+		return
+	}
+
+	lang := i18n.FromContext(ctx)
+	title := service.ServerTitle(server)
+	text := lang.T("whitelist.confirm_remove", service.EscapeMarkdown(player), service.EscapeMarkdown(title))
+	messageID = h.editMessage(chatID, messageID, text, WhitelistPlayerKeyboard(lang, serverID, player))
+	h.stateManager.SetState(chatID, StateSettings, messageID)
+}
+
+func (h *Handlers) removeFromWhitelist(
+	ctx context.Context,
+	chatID int64,
+	messageID int,
+	serverID int64,
+	player string,
+	actor service.Actor,
+) {
+	lang := i18n.FromContext(ctx)
+	reply, err := h.rconService.RemoveFromWhitelist(ctx, chatID, serverID, player, actor)
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Int64("server_id", serverID).Msg("Failed to remove player from whitelist")
+		h.sendText(chatID, lang.T("whitelist.remove_failed", service.RCONErrorText(lang, err)))
+	} else {
+		h.sendText(chatID, whitelistChangeText(lang, actor, []string{reply}))
+	}
+
+	h.showWhitelist(ctx, chatID, messageID, serverID, 0)
+}
+
+// promptWhitelistAdd asks the user who pressed the button for the players to add to the whitelist
+func (h *Handlers) promptWhitelistAdd(ctx context.Context, callback *tgbotapi.CallbackQuery, serverID int64) {
+	chatID := callback.Message.Chat.ID
+	server, err := h.service.GetServer(ctx, chatID, serverID)
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Int64("server_id", serverID).Msg("Failed to get server")
+		return
+	}
+
+	lang := i18n.FromContext(ctx)
+	text := lang.T("whitelist.add_prompt", service.EscapeMarkdown(service.ServerTitle(server)), maxWhitelistNames)
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = tgbotapi.ModeMarkdownV2
+	msg.ReplyMarkup = tgbotapi.ForceReply{ForceReply: true, Selective: true, InputFieldPlaceholder: "Steve"}
+	sent, err := h.bot.Send(msg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to send whitelist prompt")
+		return
+	}
+
+	h.awaitInput(callback, InputWhitelistAdd, serverID, sent.MessageID)
+}
+
+func (h *Handlers) handleWhitelistInput(ctx context.Context, message *tgbotapi.Message, serverID int64) {
+	chatID := message.Chat.ID
+	lang := i18n.FromContext(ctx)
+
+	names, err := parsePlayerNames(message.Text)
+	if err != nil {
+		h.sendText(chatID, lang.T("whitelist.invalid_names"))
+		return
+	}
+
+	actor := actorOf(message.From)
+	replies := make([]string, 0, len(names))
+	for _, name := range names {
+		reply, err := h.rconService.AddToWhitelist(ctx, chatID, serverID, name, actor)
+		if err != nil {
+			log.Error().Err(err).Int64("chat_id", chatID).Int64("server_id", serverID).Msg("Failed to add player to whitelist")
+			replies = append(replies, fmt.Sprintf("❌ %s: %s", name, service.RCONErrorText(lang, err)))
+			break
+		}
+		replies = append(replies, reply)
+	}
+
+	if messageID := h.stateManager.GetMessageID(chatID); messageID != 0 {
+		h.showWhitelist(ctx, chatID, messageID, serverID, 0)
+	}
+
+	h.sendText(chatID, whitelistChangeText(lang, actor, replies))
+}
+
+// whitelistChangeText reports the server's replies to a whitelist change along with who made it
+func whitelistChangeText(lang i18n.Lang, actor service.Actor, replies []string) string {
+	return lang.T("whitelist.changed", actor.Username, strings.Join(replies, "\n"))
+}
+
+// actorOf identifies a Telegram user in whitelist change logs
+func actorOf(user *tgbotapi.User) service.Actor {
+	return service.Actor{UserID: user.ID, Username: user.String()}
+}
@@ -0,0 +1,31 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+func TestWhitelistPrompt_IgnoresUnrelatedGroupMessages(t *testing.T) {
+	h, api, servers := newTestHandlers()
+	api.admins = []int64{testUser.ID}
+	servers.servers[7] = &models.Server{
+		ID: 7, ChatID: groupChat.ID, IP: "mc.example.com", Port: 25565, RCONPort: 25575, RCONPassword: "secret",
+	}
+	rcon := h.rconService.(*fakeRCON)
+	ctx := context.Background()
+
+	h.HandleCallback(ctx, callbackQuery(groupChat, testUser, 500, ServerCallback(CallbackWhitelistAdd, 7)))
+	promptID := api.lastID()
+
+	// A valid player name, but written to the chat rather than in reply to the prompt
+	h.HandleMessage(ctx, &tgbotapi.Message{MessageID: 3, Chat: groupChat, From: testUser, Text: "lol"})
+	assert.Empty(t, rcon.executed)
+
+	h.HandleMessage(ctx, replyMessage(groupChat, testUser, promptID, "Steve"))
+	assert.Equal(t, []string{"7: whitelist add Steve"}, rcon.executed)
+}
@@ -0,0 +1,248 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/minecraft"
+	"github.com/ykhdr/mss-bot/internal/service"
+)
+
+// maxServerNameLength limits the names entered in the setup wizard, in characters
+const maxServerNameLength = 64
+
+// startWizard starts adding a server step by step by asking for its address.
+// replyTo is the message that launched the wizard, if any, and settingsID the settings message to refresh at the end.
+func (h *Handlers) startWizard(ctx context.Context, chatID, userID int64, replyTo, settingsID int) {
+	lang := i18n.FromContext(ctx)
+	promptID, ok := h.sendWizardPrompt(chatID, replyTo, lang.T("wizard.address"), lang.T("wizard.address_placeholder"))
+	if !ok {
+		return
+	}
+
+	h.stateManager.SetWizard(chatID, SetupWizard{
+		UserID:     userID,
+		Step:       WizardAddress,
+		PromptID:   promptID,
+		SettingsID: settingsID,
+	})
+}
+
+// acceptsWizardInput reports whether a message answers the wizard's prompt. In groups the answer
+// must reply to the prompt, so that the rest of the conversation is not taken for it.
+func acceptsWizardInput(message *tgbotapi.Message, wizard SetupWizard) bool {
+	if wizard.Step != WizardAddress && wizard.Step != WizardName {
+		return false
+	}
+	return answersPrompt(message, wizard.PromptID)
+}
+
+// handleWizardInput processes an answer to the wizard's current question
+func (h *Handlers) handleWizardInput(ctx context.Context, message *tgbotapi.Message, wizard SetupWizard) {
+	switch wizard.Step {
+	case WizardAddress:
+		h.handleWizardAddress(ctx, message, message.Text, wizard)
+	case WizardName:
+		h.handleWizardName(ctx, message, wizard)
+	}
+}
+
+// handleWizardAddress checks that the server at the address in input responds, then asks for its name
+// unless input names it too
+func (h *Handlers) handleWizardAddress(
+	ctx context.Context, message *tgbotapi.Message, input string, wizard SetupWizard,
+) {
+	chatID := message.Chat.ID
+	lang := i18n.FromContext(ctx)
+	placeholder := lang.T("wizard.address_placeholder")
+
+	parsed, err := parseSetArguments(input)
+	if err != nil {
+		text := lang.T("wizard.invalid_address", service.EscapeMarkdown(addressErrorText(lang, err)))
+		h.repromptWizard(chatID, message.MessageID, text, placeholder, wizard)
+		return
+	}
+
+	if _, err := h.bot.Request(tgbotapi.NewChatAction(chatID, tgbotapi.ChatTyping)); err != nil {
+		log.Warn().Err(err).Int64("chat_id", chatID).Msg("Failed to send chat action")
+	}
+
+	address := minecraft.FormatAddress(parsed.Host, parsed.Port)
+	result := h.service.CheckAddress(ctx, parsed.Edition, parsed.Host, parsed.Port)
+	if !result.Status.Online {
+		reason := lang.T("explain.unknown")
+		if result.Error != nil {
+			reason = service.ExplainError(lang, result.Error)
+		}
+		text := lang.T("wizard.offline", address, service.EscapeMarkdown(reason))
+		h.repromptWizard(chatID, message.MessageID, text, placeholder, wizard)
+		return
+	}
+
+	wizard.Edition = parsed.Edition
+	wizard.Host = parsed.Host
+	wizard.Port = parsed.Port
+	if parsed.Name != "" && utf8.RuneCountInString(parsed.Name) <= maxServerNameLength {
+		wizard.Name = parsed.Name
+		h.confirmWizard(ctx, chatID, message.MessageID, wizard)
+		return
+	}
+
+	wizard.Step = WizardName
+	text := lang.T("wizard.name", service.EscapeMarkdown(result.FormatSummary(lang)), address)
+	h.repromptWizard(chatID, message.MessageID, text, lang.T("wizard.name_placeholder"), wizard)
+}
+
+// handleWizardName takes the name of the server and asks to confirm it
+func (h *Handlers) handleWizardName(ctx context.Context, message *tgbotapi.Message, wizard SetupWizard) {
+	lang := i18n.FromContext(ctx)
+
+	name := strings.Join(strings.Fields(message.Text), " ")
+	if name == "" || utf8.RuneCountInString(name) > maxServerNameLength {
+		text := lang.T("wizard.invalid_name", maxServerNameLength)
+		h.repromptWizard(message.Chat.ID, message.MessageID, text, lang.T("wizard.name_placeholder"), wizard)
+		return
+	}
+
+	wizard.Name = name
+	h.confirmWizard(ctx, message.Chat.ID, message.MessageID, wizard)
+}
+
+// confirmWizard shows the server about to be added with save and cancel buttons
+func (h *Handlers) confirmWizard(ctx context.Context, chatID int64, replyTo int, wizard SetupWizard) {
+	lang := i18n.FromContext(ctx)
+	text := lang.T("wizard.confirm",
+		service.EscapeMarkdown(wizard.Name),
+		service.EditionTitle(wizard.Edition),
+		minecraft.FormatAddress(wizard.Host, wizard.Port),
+	)
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = tgbotapi.ModeMarkdownV2
+	msg.ReplyToMessageID = replyTo
+	msg.ReplyMarkup = WizardConfirmKeyboard(lang)
+	sent, err := h.bot.Send(msg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to send wizard confirmation")
+		return
+	}
+
+	wizard.Step = WizardConfirm
+	wizard.PromptID = sent.MessageID
+	h.stateManager.SetWizard(chatID, wizard)
+}
+
+// saveWizard adds the server confirmed in the wizard
+func (h *Handlers) saveWizard(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	lang := i18n.FromContext(ctx)
+
+	wizard, ok := h.activeWizard(callback)
+	if !ok {
+		h.sendText(chatID, lang.T("wizard.inactive"))
+		return
+	}
+	// Permissions may have been taken away since the wizard was started
+	if !h.canConfigure(ctx, callback.Message.Chat, callback.From) {
+		h.sendText(chatID, lang.T("config.admins_only"))
+		return
+	}
+	h.stateManager.TakeWizard(chatID, wizard.UserID)
+
+	err := h.service.SetServerConfig(ctx, chatID, callback.From.ID, wizard.Edition, wizard.Host, wizard.Port, wizard.Name)
+	if err != nil {
+		h.finishWizard(chatID, messageID, lang.T("error.save", service.EscapeMarkdown(err.Error())))
+		return
+	}
+
+	h.finishWizard(chatID, messageID, lang.T("wizard.saved", service.EscapeMarkdown(wizard.Name)))
+	if wizard.SettingsID != 0 {
+		h.showSettings(ctx, chatID, wizard.SettingsID)
+	}
+}
+
+// cancelWizard abandons the wizard from its confirmation message
+func (h *Handlers) cancelWizard(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	lang := i18n.FromContext(ctx)
+	wizard, ok := h.activeWizard(callback)
+	if !ok {
+		h.sendText(callback.Message.Chat.ID, lang.T("wizard.inactive"))
+		return
+	}
+
+	h.stateManager.TakeWizard(callback.Message.Chat.ID, wizard.UserID)
+	h.finishWizard(callback.Message.Chat.ID, callback.Message.MessageID, lang.T("wizard.canceled"))
+}
+
+// handleCancel abandons the user's setup wizard or pending prompt
+func (h *Handlers) handleCancel(ctx context.Context, message *tgbotapi.Message) {
+	if message.From == nil {
+		return
+	}
+
+	chatID := message.Chat.ID
+	lang := i18n.FromContext(ctx)
+	_, wizard := h.stateManager.TakeWizard(chatID, message.From.ID)
+	_, input := h.stateManager.TakePendingInput(chatID, message.From.ID)
+	if !wizard && !input {
+		h.sendText(chatID, lang.T("cancel.nothing"))
+		return
+	}
+	h.sendText(chatID, lang.T("cancel.done"))
+}
+
+// activeWizard returns the user's wizard, or an anonymous admin's one when the user is an admin,
+// if the callback comes from its confirmation message
+func (h *Handlers) activeWizard(callback *tgbotapi.CallbackQuery) (SetupWizard, bool) {
+	wizard, ok := h.stateManager.GetWizard(callback.Message.Chat.ID, callback.From.ID)
+	if !ok && h.fromAnonymousAdmin(callback) {
+		wizard, ok = h.stateManager.GetWizard(callback.Message.Chat.ID, groupAnonymousBotID)
+	}
+	if !ok || wizard.Step != WizardConfirm || wizard.PromptID != callback.Message.MessageID {
+		return SetupWizard{}, false
+	}
+	return wizard, true
+}
+
+// repromptWizard asks the wizard's question again, or the next one, and waits for the answer to it
+func (h *Handlers) repromptWizard(chatID int64, replyTo int, text, placeholder string, wizard SetupWizard) {
+	promptID, ok := h.sendWizardPrompt(chatID, replyTo, text, placeholder)
+	if !ok {
+		return
+	}
+
+	wizard.PromptID = promptID
+	h.stateManager.SetWizard(chatID, wizard)
+}
+
+// sendWizardPrompt sends a question of the wizard that the user answers by replying to it.
+// The reply field is opened only for the author of replyTo; without a message to reply to,
+// such as after a button press, it is opened for everyone and answers of other members are ignored.
+func (h *Handlers) sendWizardPrompt(chatID int64, replyTo int, text, placeholder string) (int, bool) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = tgbotapi.ModeMarkdownV2
+	msg.ReplyToMessageID = replyTo
+	msg.ReplyMarkup = tgbotapi.ForceReply{ForceReply: true, Selective: replyTo != 0, InputFieldPlaceholder: placeholder}
+
+	sent, err := h.bot.Send(msg)
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Msg("Failed to send wizard prompt")
+		return 0, false
+	}
+	return sent.MessageID, true
+}
+
+// finishWizard replaces the wizard's confirmation message with its outcome, removing the buttons
+func (h *Handlers) finishWizard(chatID int64, messageID int, text string) {
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit.ParseMode = tgbotapi.ModeMarkdownV2
+	if _, err := h.bot.Send(edit); err != nil && !isNotModified(err) {
+		log.Error().Err(err).Msg("Failed to edit wizard message")
+	}
+}
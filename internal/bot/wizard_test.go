@@ -0,0 +1,213 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+func TestAcceptsWizardInput(t *testing.T) {
+	private := &tgbotapi.Chat{ID: 12345, Type: "private"}
+	group := &tgbotapi.Chat{ID: -100123, Type: "supergroup"}
+	wizard := SetupWizard{UserID: 1, Step: WizardAddress, PromptID: 10}
+
+	assert.True(t, acceptsWizardInput(&tgbotapi.Message{Chat: private}, wizard))
+
+	assert.False(t, acceptsWizardInput(&tgbotapi.Message{Chat: group}, wizard),
+		"in groups only replies to the prompt are answers")
+	assert.False(t, acceptsWizardInput(&tgbotapi.Message{Chat: group, ReplyToMessage: &tgbotapi.Message{MessageID: 9}}, wizard))
+	assert.True(t, acceptsWizardInput(&tgbotapi.Message{Chat: group, ReplyToMessage: &tgbotapi.Message{MessageID: 10}}, wizard))
+
+	wizard.Step = WizardConfirm
+	assert.False(t, acceptsWizardInput(&tgbotapi.Message{Chat: private}, wizard),
+		"the confirmation is answered with buttons")
+}
+
+func TestWizard_AddressNameConfirm(t *testing.T) {
+	h, api, servers := newTestHandlers()
+	servers.online["mc.example.com"] = true
+	ctx := context.Background()
+
+	h.HandleCommand(ctx, commandMessage(privateChat, testUser, "/set"))
+	prompt := api.lastMessage()
+	assert.Equal(t, i18n.EN.T("wizard.address"), prompt.Text)
+	assert.Equal(t, 1, prompt.ReplyToMessageID)
+	assert.Equal(t, tgbotapi.ForceReply{ForceReply: true, Selective: true, InputFieldPlaceholder: "mc.example.com:25565"},
+		prompt.ReplyMarkup)
+
+	h.HandleMessage(ctx, replyMessage(privateChat, testUser, api.lastID(), "mc.example.com"))
+	assert.Equal(t, []string{"mc.example.com"}, servers.checked)
+	wizard, ok := h.stateManager.GetWizard(privateChat.ID, testUser.ID)
+	require.True(t, ok)
+	assert.Equal(t, WizardName, wizard.Step)
+	assert.Equal(t, api.lastID(), wizard.PromptID)
+
+	h.HandleMessage(ctx, replyMessage(privateChat, testUser, api.lastID(), "  My   server "))
+	confirmation := api.lastMessage()
+	assert.Equal(t, WizardConfirmKeyboard(i18n.EN), confirmation.ReplyMarkup)
+	assert.Contains(t, confirmation.Text, "*My server*")
+
+	h.HandleCallback(ctx, callbackQuery(privateChat, testUser, api.lastID(), CallbackWizardSave))
+	require.Len(t, servers.saved, 1)
+	assert.Equal(t, savedServer{
+		chatID: privateChat.ID, userID: testUser.ID, edition: models.EditionJava, host: "mc.example.com", port: 0, name: "My server",
+	}, servers.saved[0])
+	assert.Equal(t, i18n.EN.T("wizard.saved", "My server"), api.texts()[len(api.texts())-1])
+
+	_, ok = h.stateManager.GetWizard(privateChat.ID, testUser.ID)
+	assert.False(t, ok, "the wizard is over once saved")
+}
+
+func TestWizard_OneShotSet(t *testing.T) {
+	h, api, servers := newTestHandlers()
+	servers.online["pe.example.com:19132"] = true
+	ctx := context.Background()
+
+	h.HandleCommand(ctx, commandMessage(privateChat, testUser, "/set bedrock pe.example.com Pocket"))
+	assert.Equal(t, []string{"pe.example.com:19132"}, servers.checked, "the server is checked before it is saved")
+	assert.Empty(t, servers.saved, "saving waits for the confirmation")
+
+	wizard, ok := h.stateManager.GetWizard(privateChat.ID, testUser.ID)
+	require.True(t, ok)
+	assert.Equal(t, WizardConfirm, wizard.Step, "the name step is skipped")
+	assert.Equal(t, "Pocket", wizard.Name)
+	assert.Equal(t, models.EditionBedrock, wizard.Edition)
+
+	h.HandleCallback(ctx, callbackQuery(privateChat, testUser, api.lastID(), CallbackWizardSave))
+	require.Len(t, servers.saved, 1)
+	assert.Equal(t, "Pocket", servers.saved[0].name)
+}
+
+func TestWizard_OfflineReprompt(t *testing.T) {
+	h, api, servers := newTestHandlers()
+	ctx := context.Background()
+
+	h.HandleCommand(ctx, commandMessage(privateChat, testUser, "/set"))
+	h.HandleMessage(ctx, replyMessage(privateChat, testUser, api.lastID(), "down.example.com"))
+
+	assert.Contains(t, api.lastMessage().Text, "did not respond")
+	wizard, ok := h.stateManager.GetWizard(privateChat.ID, testUser.ID)
+	require.True(t, ok)
+	assert.Equal(t, WizardAddress, wizard.Step, "the address is asked again")
+	assert.Equal(t, api.lastID(), wizard.PromptID)
+
+	servers.online["up.example.com"] = true
+	h.HandleMessage(ctx, replyMessage(privateChat, testUser, api.lastID(), "up.example.com"))
+	wizard, _ = h.stateManager.GetWizard(privateChat.ID, testUser.ID)
+	assert.Equal(t, WizardName, wizard.Step)
+	assert.Empty(t, servers.saved)
+}
+
+func TestWizard_NameLengthLimit(t *testing.T) {
+	h, api, servers := newTestHandlers()
+	servers.online["mc.example.com"] = true
+	ctx := context.Background()
+
+	h.HandleCommand(ctx, commandMessage(privateChat, testUser, "/set"))
+	h.HandleMessage(ctx, replyMessage(privateChat, testUser, api.lastID(), "mc.example.com"))
+	h.HandleMessage(ctx, replyMessage(privateChat, testUser, api.lastID(), strings.Repeat("я", maxServerNameLength+1)))
+
+	assert.Equal(t, i18n.EN.T("wizard.invalid_name", maxServerNameLength), api.lastMessage().Text)
+	wizard, _ := h.stateManager.GetWizard(privateChat.ID, testUser.ID)
+	assert.Equal(t, WizardName, wizard.Step)
+
+	h.HandleMessage(ctx, replyMessage(privateChat, testUser, api.lastID(), strings.Repeat("я", maxServerNameLength)))
+	wizard, _ = h.stateManager.GetWizard(privateChat.ID, testUser.ID)
+	assert.Equal(t, WizardConfirm, wizard.Step, "names of the maximum length in characters are accepted")
+
+	// A one-shot /set with a long name asks for another one
+	h.HandleCommand(ctx, commandMessage(privateChat, testUser, "/set mc.example.com "+strings.Repeat("x", maxServerNameLength+1)))
+	wizard, _ = h.stateManager.GetWizard(privateChat.ID, testUser.ID)
+	assert.Equal(t, WizardName, wizard.Step)
+}
+
+func TestWizard_SaveRechecksPermissions(t *testing.T) {
+	h, api, servers := newTestHandlers()
+	api.admins = []int64{testUser.ID}
+	servers.online["mc.example.com"] = true
+	ctx := context.Background()
+
+	h.HandleCommand(ctx, commandMessage(groupChat, testUser, "/set mc.example.com Main"))
+	wizard, ok := h.stateManager.GetWizard(groupChat.ID, testUser.ID)
+	require.True(t, ok)
+	require.Equal(t, WizardConfirm, wizard.Step)
+
+	// The user stops being an admin before pressing save
+	h.admins.Store(groupChat.ID, nil)
+	h.HandleCallback(ctx, callbackQuery(groupChat, testUser, wizard.PromptID, CallbackWizardSave))
+
+	assert.Empty(t, servers.saved)
+	assert.Equal(t, i18n.EN.T("config.admins_only"), api.lastMessage().Text)
+}
+
+func TestWizard_OtherMembersAnswersIgnored(t *testing.T) {
+	h, api, servers := newTestHandlers()
+	api.admins = []int64{testUser.ID}
+	servers.online["mc.example.com"] = true
+	ctx := context.Background()
+
+	h.HandleCommand(ctx, commandMessage(groupChat, testUser, "/set"))
+	other := &tgbotapi.User{ID: 777}
+	h.HandleMessage(ctx, replyMessage(groupChat, other, api.lastID(), "mc.example.com"))
+
+	assert.Empty(t, servers.checked)
+	wizard, _ := h.stateManager.GetWizard(groupChat.ID, testUser.ID)
+	assert.Equal(t, WizardAddress, wizard.Step)
+}
+
+func TestWizard_StartedFromButton(t *testing.T) {
+	h, api, _ := newTestHandlers()
+	api.admins = []int64{testUser.ID}
+	ctx := context.Background()
+
+	h.HandleCallback(ctx, callbackQuery(groupChat, testUser, 500, CallbackAddServer))
+
+	prompt := api.lastMessage()
+	assert.Equal(t, 0, prompt.ReplyToMessageID)
+	assert.False(t, prompt.ReplyMarkup.(tgbotapi.ForceReply).Selective,
+		"without a message to reply to, a selective reply field would be shown to no one")
+
+	wizard, ok := h.stateManager.GetWizard(groupChat.ID, testUser.ID)
+	require.True(t, ok)
+	assert.Equal(t, 500, wizard.SettingsID)
+}
+
+func TestWizard_Cancel(t *testing.T) {
+	h, api, servers := newTestHandlers()
+	ctx := context.Background()
+
+	h.HandleCommand(ctx, commandMessage(privateChat, testUser, "/set"))
+	h.HandleCommand(ctx, commandMessage(privateChat, testUser, "/cancel"))
+	assert.Equal(t, i18n.EN.T("cancel.done"), api.lastMessage().Text)
+
+	_, ok := h.stateManager.GetWizard(privateChat.ID, testUser.ID)
+	assert.False(t, ok)
+	h.HandleMessage(ctx, replyMessage(privateChat, testUser, api.lastID(), "mc.example.com"))
+	assert.Empty(t, servers.checked, "answers after /cancel are ignored")
+
+	h.HandleCommand(ctx, commandMessage(privateChat, testUser, "/cancel"))
+	assert.Equal(t, i18n.EN.T("cancel.nothing"), api.lastMessage().Text)
+}
+
+func TestWizard_CancelButton(t *testing.T) {
+	h, api, servers := newTestHandlers()
+	servers.online["mc.example.com"] = true
+	ctx := context.Background()
+
+	h.HandleCommand(ctx, commandMessage(privateChat, testUser, "/set mc.example.com Main"))
+	h.HandleCallback(ctx, callbackQuery(privateChat, testUser, api.lastID(), CallbackWizardCancel))
+
+	assert.Equal(t, i18n.EN.T("wizard.canceled"), api.texts()[len(api.texts())-1])
+	assert.Empty(t, servers.saved)
+
+	h.HandleCallback(ctx, callbackQuery(privateChat, testUser, api.lastID(), CallbackWizardSave))
+	assert.Empty(t, servers.saved, "a canceled wizard cannot be saved")
+	assert.Equal(t, i18n.EN.T("wizard.inactive"), api.lastMessage().Text)
+}
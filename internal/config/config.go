@@ -5,6 +5,8 @@ import (
 	"time"
 
 	kdlconfig "github.com/ykhdr/kdl-config"
+
+	"github.com/ykhdr/mss-bot/internal/secret"
 )
 
 // Config represents the application configuration
@@ -12,6 +14,8 @@ type Config struct {
 	Bot       BotConfig
 	Database  DatabaseConfig
 	Minecraft MinecraftConfig
+	Monitor   MonitorConfig
+	RCON      RCONConfig
 	Logging   LoggingConfig
 }
 
@@ -30,9 +34,39 @@ type DatabaseConfig struct {
 	Path string
 }
 
+// Defaults used when the status cache, retries and pinned status updates are not configured
+const (
+	defaultCacheTTL        = 30 * time.Second
+	defaultRetryAttempts   = 3
+	defaultRetryBackoff    = 500 * time.Millisecond
+	defaultRetryMaxBackoff = 5 * time.Second
+	defaultPinInterval     = 5 * time.Minute
+)
+
 // MinecraftConfig contains Minecraft query settings
 type MinecraftConfig struct {
-	Timeout time.Duration
+	Timeout  time.Duration
+	CacheTTL time.Duration // zero disables the status cache
+
+	// Transient query failures are retried with exponential backoff within RetryDeadline
+	RetryAttempts   int // queries made in total; one disables retries
+	RetryBackoff    time.Duration
+	RetryMaxBackoff time.Duration
+	RetryDeadline   time.Duration
+}
+
+// MonitorConfig contains background status polling settings
+type MonitorConfig struct {
+	Interval         time.Duration
+	FailureThreshold int
+	// PinInterval is how often pinned status cards are updated
+	PinInterval time.Duration
+}
+
+// RCONConfig contains remote console settings
+type RCONConfig struct {
+	// Key encrypts RCON passwords in the database; without it the remote console is disabled
+	Key []byte
 }
 
 // kdlConfig is the internal KDL structure for parsing
@@ -40,6 +74,8 @@ type kdlConfig struct {
 	Bot       kdlBotConfig       `kdl:"bot"`
 	Database  kdlDatabaseConfig  `kdl:"database"`
 	Minecraft kdlMinecraftConfig `kdl:"minecraft"`
+	Monitor   kdlMonitorConfig   `kdl:"monitor"`
+	RCON      kdlRCONConfig      `kdl:"rcon"`
 	Logging   kdlLoggingConfig   `kdl:"logging"`
 }
 
@@ -56,7 +92,22 @@ type kdlDatabaseConfig struct {
 }
 
 type kdlMinecraftConfig struct {
-	Timeout string `kdl:"timeout"`
+	Timeout         string `kdl:"timeout"`
+	CacheTTL        string `kdl:"cache-ttl"`
+	RetryAttempts   int    `kdl:"retry-attempts"`
+	RetryBackoff    string `kdl:"retry-backoff"`
+	RetryMaxBackoff string `kdl:"retry-max-backoff"`
+	RetryDeadline   string `kdl:"retry-deadline"`
+}
+
+type kdlMonitorConfig struct {
+	Interval         string `kdl:"interval"`
+	FailureThreshold int    `kdl:"failure-threshold"`
+	PinInterval      string `kdl:"pin-interval"`
+}
+
+type kdlRCONConfig struct {
+	Key string `kdl:"key"`
 }
 
 // Load reads and parses the KDL configuration file
@@ -73,6 +124,51 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("invalid timeout format: %w", err)
 	}
 
+	// Unlike other durations, zero is meaningful here, so the default is applied only when unset
+	cacheTTL := defaultCacheTTL
+	if kdlCfg.Minecraft.CacheTTL != "" {
+		cacheTTL, err = time.ParseDuration(kdlCfg.Minecraft.CacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache TTL format: %w", err)
+		}
+		if cacheTTL < 0 {
+			return nil, fmt.Errorf("cache TTL must not be negative")
+		}
+	}
+
+	retryBackoff, err := time.ParseDuration(kdlCfg.Minecraft.RetryBackoff)
+	if err != nil && kdlCfg.Minecraft.RetryBackoff != "" {
+		return nil, fmt.Errorf("invalid retry backoff format: %w", err)
+	}
+
+	retryMaxBackoff, err := time.ParseDuration(kdlCfg.Minecraft.RetryMaxBackoff)
+	if err != nil && kdlCfg.Minecraft.RetryMaxBackoff != "" {
+		return nil, fmt.Errorf("invalid retry max backoff format: %w", err)
+	}
+
+	retryDeadline, err := time.ParseDuration(kdlCfg.Minecraft.RetryDeadline)
+	if err != nil && kdlCfg.Minecraft.RetryDeadline != "" {
+		return nil, fmt.Errorf("invalid retry deadline format: %w", err)
+	}
+
+	interval, err := time.ParseDuration(kdlCfg.Monitor.Interval)
+	if err != nil && kdlCfg.Monitor.Interval != "" {
+		return nil, fmt.Errorf("invalid monitor interval format: %w", err)
+	}
+
+	pinInterval, err := time.ParseDuration(kdlCfg.Monitor.PinInterval)
+	if err != nil && kdlCfg.Monitor.PinInterval != "" {
+		return nil, fmt.Errorf("invalid pin interval format: %w", err)
+	}
+
+	var rconKey []byte
+	if kdlCfg.RCON.Key != "" {
+		rconKey, err = secret.ParseKey(kdlCfg.RCON.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RCON key: %w", err)
+		}
+	}
+
 	cfg := &Config{
 		Bot: BotConfig{
 			Token: kdlCfg.Bot.Token,
@@ -81,7 +177,20 @@ func Load(path string) (*Config, error) {
 			Path: kdlCfg.Database.Path,
 		},
 		Minecraft: MinecraftConfig{
-			Timeout: timeout,
+			Timeout:         timeout,
+			CacheTTL:        cacheTTL,
+			RetryAttempts:   kdlCfg.Minecraft.RetryAttempts,
+			RetryBackoff:    retryBackoff,
+			RetryMaxBackoff: retryMaxBackoff,
+			RetryDeadline:   retryDeadline,
+		},
+		Monitor: MonitorConfig{
+			Interval:         interval,
+			FailureThreshold: kdlCfg.Monitor.FailureThreshold,
+			PinInterval:      pinInterval,
+		},
+		RCON: RCONConfig{
+			Key: rconKey,
 		},
 		Logging: LoggingConfig{
 			Level: kdlCfg.Logging.Level,
@@ -109,6 +218,39 @@ func (c *Config) validate() error {
 		c.Minecraft.Timeout = 5 * time.Second
 	}
 
+	if c.Minecraft.RetryAttempts <= 0 {
+		c.Minecraft.RetryAttempts = defaultRetryAttempts
+	}
+
+	if c.Minecraft.RetryBackoff <= 0 {
+		c.Minecraft.RetryBackoff = defaultRetryBackoff
+	}
+
+	if c.Minecraft.RetryMaxBackoff <= 0 {
+		c.Minecraft.RetryMaxBackoff = defaultRetryMaxBackoff
+	}
+	if c.Minecraft.RetryMaxBackoff < c.Minecraft.RetryBackoff {
+		return fmt.Errorf("retry max backoff must not be less than retry backoff")
+	}
+
+	// By default every attempt has time to time out and wait out the longest backoff
+	if c.Minecraft.RetryDeadline <= 0 {
+		c.Minecraft.RetryDeadline = time.Duration(c.Minecraft.RetryAttempts) *
+			(c.Minecraft.Timeout + c.Minecraft.RetryMaxBackoff)
+	}
+
+	if c.Monitor.Interval <= 0 {
+		c.Monitor.Interval = time.Minute
+	}
+
+	if c.Monitor.FailureThreshold <= 0 {
+		c.Monitor.FailureThreshold = 3
+	}
+
+	if c.Monitor.PinInterval <= 0 {
+		c.Monitor.PinInterval = defaultPinInterval
+	}
+
 	if c.Logging.Level == "" {
 		c.Logging.Level = "info"
 	}
@@ -119,9 +261,28 @@ func (c *Config) validate() error {
 // String returns a string representation of the configuration (for logging)
 func (c *Config) String() string {
 	return fmt.Sprintf(
-		"Bot.Token: [REDACTED], Database.Path: %s, Minecraft.Timeout: %s, Logging.Level: %s",
+		"Bot.Token: [REDACTED], Database.Path: %s, Minecraft.Timeout: %s, Minecraft.CacheTTL: %s, "+
+			"Minecraft.Retry: %d attempts, backoff %s-%s within %s, "+
+			"Monitor.Interval: %s, Monitor.FailureThreshold: %d, Monitor.PinInterval: %s, RCON.Key: %s, Logging.Level: %s",
 		c.Database.Path,
 		c.Minecraft.Timeout,
+		c.Minecraft.CacheTTL,
+		c.Minecraft.RetryAttempts,
+		c.Minecraft.RetryBackoff,
+		c.Minecraft.RetryMaxBackoff,
+		c.Minecraft.RetryDeadline,
+		c.Monitor.Interval,
+		c.Monitor.FailureThreshold,
+		c.Monitor.PinInterval,
+		redacted(len(c.RCON.Key) > 0),
 		c.Logging.Level,
 	)
 }
+
+// redacted describes a secret setting without revealing it
+func redacted(set bool) string {
+	if set {
+		return "[REDACTED]"
+	}
+	return "[NOT SET]"
+}
@@ -83,6 +83,181 @@ minecraft {
 
 	assert.Equal(t, "./data/mss-bot.db", cfg.Database.Path)
 	assert.Equal(t, 5*time.Second, cfg.Minecraft.Timeout)
+	assert.Equal(t, 30*time.Second, cfg.Minecraft.CacheTTL)
+	assert.Equal(t, 3, cfg.Minecraft.RetryAttempts)
+	assert.Equal(t, 500*time.Millisecond, cfg.Minecraft.RetryBackoff)
+	assert.Equal(t, 5*time.Second, cfg.Minecraft.RetryMaxBackoff)
+	assert.Equal(t, 30*time.Second, cfg.Minecraft.RetryDeadline, "3 attempts of 5s timeout and 5s backoff")
+	assert.Equal(t, time.Minute, cfg.Monitor.Interval)
+	assert.Equal(t, 3, cfg.Monitor.FailureThreshold)
+	assert.Equal(t, 5*time.Minute, cfg.Monitor.PinInterval)
+}
+
+func TestLoad_CacheTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     string
+		want    time.Duration
+		wantErr string
+	}{
+		{name: "custom", ttl: "1m", want: time.Minute},
+		{name: "disabled", ttl: "0s", want: 0},
+		{name: "invalid", ttl: "soon", wantErr: "invalid cache TTL format"},
+		{name: "negative", ttl: "-5s", wantErr: "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := `
+bot {
+    token "valid-token"
+}
+
+minecraft {
+    cache-ttl "` + tt.ttl + `"
+}
+`
+			configPath := filepath.Join(t.TempDir(), "config.kdl")
+			require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+
+			cfg, err := Load(configPath)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Minecraft.CacheTTL)
+		})
+	}
+}
+
+func TestLoad_Retry(t *testing.T) {
+	tests := []struct {
+		name    string
+		retry   string
+		wantErr string
+	}{
+		{name: "custom", retry: `retry-attempts 5
+    retry-backoff "1s"
+    retry-max-backoff "10s"
+    retry-deadline "30s"`},
+		{name: "invalid backoff", retry: `retry-backoff "soon"`, wantErr: "invalid retry backoff format"},
+		{name: "invalid deadline", retry: `retry-deadline "later"`, wantErr: "invalid retry deadline format"},
+		{name: "max below backoff", retry: `retry-backoff "10s"`, wantErr: "must not be less than retry backoff"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := `
+bot {
+    token "valid-token"
+}
+
+minecraft {
+    ` + tt.retry + `
+}
+`
+			configPath := filepath.Join(t.TempDir(), "config.kdl")
+			require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+
+			cfg, err := Load(configPath)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 5, cfg.Minecraft.RetryAttempts)
+			assert.Equal(t, time.Second, cfg.Minecraft.RetryBackoff)
+			assert.Equal(t, 10*time.Second, cfg.Minecraft.RetryMaxBackoff)
+			assert.Equal(t, 30*time.Second, cfg.Minecraft.RetryDeadline)
+		})
+	}
+}
+
+func TestLoad_RCONKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr string
+	}{
+		{name: "valid", key: "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="},
+		{name: "not base64", key: "secret!", wantErr: "invalid RCON key"},
+		{name: "too short", key: "AAECAwQFBgc=", wantErr: "invalid key length"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := `
+bot {
+    token "valid-token"
+}
+
+rcon {
+    key "` + tt.key + `"
+}
+`
+			configPath := filepath.Join(t.TempDir(), "config.kdl")
+			require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+
+			cfg, err := Load(configPath)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, cfg.RCON.Key, 32)
+			assert.Contains(t, cfg.String(), "RCON.Key: [REDACTED]")
+			assert.NotContains(t, cfg.String(), tt.key)
+		})
+	}
+}
+
+func TestLoad_MonitorConfig(t *testing.T) {
+	content := `
+bot {
+    token "valid-token"
+}
+
+monitor {
+    interval "30s"
+    failure-threshold 5
+    pin-interval "10m"
+}
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.kdl")
+	err := os.WriteFile(configPath, []byte(content), 0644)
+	require.NoError(t, err)
+
+	cfg, err := Load(configPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, 30*time.Second, cfg.Monitor.Interval)
+	assert.Equal(t, 5, cfg.Monitor.FailureThreshold)
+	assert.Equal(t, 10*time.Minute, cfg.Monitor.PinInterval)
+}
+
+func TestLoad_InvalidMonitorInterval(t *testing.T) {
+	content := `
+bot {
+    token "valid-token"
+}
+
+monitor {
+    interval "often"
+}
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.kdl")
+	err := os.WriteFile(configPath, []byte(content), 0644)
+	require.NoError(t, err)
+
+	_, err = Load(configPath)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid monitor interval format")
 }
 
 func TestLoad_FileNotFound(t *testing.T) {
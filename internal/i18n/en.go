@@ -0,0 +1,227 @@
+package i18n
+
+// en is the English catalog. Messages sent with MarkdownV2 are escaped accordingly;
+// plural forms (one, other) are separated by "|".
+var en = map[string]string{
+	"language.name": "English",
+
+	// Common words and messages
+	"on":         "on",
+	"off":        "off",
+	"auto":       "auto",
+	"error":      "❌ Error: %v",
+	"error.save": "❌ Failed to save: %v",
+	"page":       "Page %d/%d",
+
+	// Buttons
+	"btn.status":               "📊 Status",
+	"btn.settings":             "⚙️ Settings",
+	"btn.back":                 "◀️ Back",
+	"btn.refresh":              "🔄 Refresh",
+	"btn.mods":                 "🧩 Mods",
+	"btn.all_servers":          "📋 All servers",
+	"btn.player_notifications": "🔔 Player joins/leaves: %s",
+	"btn.access":               "👥 Who can configure: %s",
+	"btn.language":             "🌐 Language: %s",
+	"btn.query":                "📡 Query: %s",
+	"btn.query_port":           "🔢 Query port",
+	"btn.protocol":             "🧬 Protocol: %s",
+	"btn.rcon":                 "🖥 RCON: %s",
+	"btn.whitelist":            "📋 Whitelist",
+	"btn.delete":               "🗑 Delete",
+	"btn.cancel":               "❌ Cancel",
+	"btn.run":                  "⚠️ Run",
+	"btn.add":                  "➕ Add",
+	"btn.add_server":           "➕ Add server",
+	"btn.save":                 "✅ Save",
+
+	// Commands and menus
+	"start": "👋 Hi! I check the status of Minecraft servers.\n\n" +
+		"Use /mss to open the menu.",
+	"help": "📖 *Help*\n\n" +
+		"*Commands:*\n" +
+		"/mss \\- Open the main menu\n" +
+		"/set \\- Add a server step by step\n" +
+		"/set \\[java\\|bedrock\\] \\<ip:port\\> \\<name\\> \\- Add a server with one command\n" +
+		"/cancel \\- Cancel adding a server\n" +
+		"/pin \\- Pin a server status that updates itself\n" +
+		"/rcon \\<command\\> \\- Run a command on the server \\(admins only\\)\n\n" +
+		"*Example:*\n" +
+		"`/set mc.example.com:25565 My Server`",
+	"menu": "🎮 *Minecraft Server Status*\n\nChoose an action:",
+
+	"address.empty":        "no server address given",
+	"address.invalid_port": "the port must be a number",
+	"address.port_range":   "the port must be from 1 to 65535",
+	"address.invalid_host": "invalid host name or IP address",
+
+	"status.no_servers": "⚠️ No server is set up\\.\n\nAdd one in the settings\\.",
+	"status.pick":       "📊 *Choose a server:*",
+	"status.not_found":  "⚠️ Server not found\\.\n\nIt may have been removed in the settings\\.",
+
+	"query_port.prompt": "🔢 Send the query port for *%s*\\.\n\n" +
+		"This is `query.port` from server\\.properties\\. Send `0` to use the server port\\.",
+	"query_port.invalid": "❌ Invalid port. Expected a number from 0 to 65535.",
+	"query_port.saved":   "✅ Query port saved!",
+
+	"protocol.prompt": "🧬 Send the protocol version for *%s*\\.\n\n" +
+		"It is sent to the server on connect; some proxies answer differently depending on it\\. " +
+		"For example, `765` is 1\\.20\\.4\\. Send `auto` to use the server's own protocol\\.",
+	"protocol.invalid": "❌ Invalid protocol version. Expected a positive number or \"auto\".",
+	"protocol.saved":   "✅ Protocol version saved!",
+
+	// Server status
+	"status.not_configured": "No server is set up. Add one in the settings.",
+	"status.offline":        "🔴 *%s*\n\nAddress: `%s`\nStatus: Offline%s%s",
+	"status.online":         "🟢 *%s*\n\n%sAddress: %s\nVersion: %s\n%s%s%s%sOnline: %d/%d%s%s",
+	"status.players":        "👥 *Players online:*",
+	"status.more_players":   "… and %d more player|… and %d more players",
+	"status.checked":        "🕒 _Checked %s_",
+	"status.reason":         "Reason: %s",
+	"status.ping":           "Ping: %s %d ms",
+	"status.ping_details":   " \\(DNS %d ms, connect %d ms\\)",
+	"status.edition":        "Edition: %s",
+	"status.game_mode":      "Game mode: %s",
+	"status.world":          "World: %s",
+	"status.map":            "Map: %s",
+	"status.software":       "Software: %s",
+	"status.plugins":        "Plugins \\(%d\\): %s",
+
+	"summary.online":         "🟢 Online %d/%d",
+	"summary.offline":        "🔴 Offline",
+	"summary.offline_reason": "🔴 Offline: %s",
+
+	"age.now":     "just now",
+	"age.seconds": "%ds ago",
+	"age.minutes": "%d min ago",
+
+	"explain.dns":         "server address not found (DNS error)",
+	"explain.refused":     "connection refused: the server is down or the port is closed",
+	"explain.unreachable": "no network route to the server",
+	"explain.timeout":     "the server did not respond in time",
+	"explain.reset":       "connection reset by the server or a proxy",
+	"explain.protocol":    "the server responded, but it does not look like Minecraft",
+	"explain.private":     "the address leads into a private network",
+	"explain.unknown":     "unknown error",
+
+	"mods.title":     "🧩 *Mods of %s*",
+	"mods.none":      "The server does not report its mods\\.",
+	"mods.loader":    "Loader: %s",
+	"mods.count":     "Total: %d mod|Total: %d mods",
+	"mods.truncated": "_The server did not send the full mod list\\._",
+	"mods.summary":   "Mods: %d \\(%s\\)",
+
+	// Settings
+	"config.admins_only": "⛔ In this group only chat admins can change the servers and settings.\n" +
+		"An admin can allow all members to do it in the settings.",
+	"access.admins_only": "⛔ Only chat admins can allow all members to configure the bot.",
+	"access.admins":      "admins",
+	"access.everyone":    "everyone",
+	"access.sender_chat": "⛔ Messages sent on behalf of a channel cannot be checked against the chat admins. " +
+		"Send the command from your own account or as an anonymous admin of this group.",
+
+	"config.empty": "⚙️ *Server settings*\n\n" +
+		"No server is set up\\.\n\n" +
+		"Press “➕ Add server”: the bot asks for the address and the name and checks that the server responds\\. " +
+		"Or send:\n" +
+		"`/set <ip>:<port> <name>`",
+	"config.title":   "⚙️ *Server settings*",
+	"config.unnamed": "Not set",
+	"config.server":  "%d\\. *%s*\nEdition: %s\nIP: `%s`\nPort: `%d`\n%s\n",
+	"config.footer": "To add a server, press “➕ Add server” or send:\n" +
+		"`/set [java|bedrock] <ip>:<port> <name>`\n\n" +
+		"To change or remove a server, tap it below\\.",
+	"config.protocol_auto": "Protocol: auto",
+	"config.protocol":      "Protocol: `%d`",
+	"config.query_off":     "Query: off",
+	"config.query_on":      "Query: on, port `%d`",
+	"config.rcon_off":      "RCON: off",
+	"config.rcon_on":       "RCON: on, port `%d`",
+
+	"server_settings": "⚙️ *%s*\n\nEdition: %s\nIP: `%s`\nPort: `%d`\n%s%s%s",
+	"server_settings.query_hint": "Query gives the full player list, map and plugins\\. " +
+		"Set `enable-query=true` in server\\.properties\\.",
+	"server_settings.confirm_delete": "🗑 *%s*\n\nDelete the server from this chat? " +
+		"Its status history and pinned status cards are deleted too\\.",
+
+	// Setup wizard
+	"wizard.address": "➕ *Adding a server*\n\n" +
+		"Send the server address, e\\.g\\. `mc\\.example\\.com` or `bedrock play\\.example\\.net:19132`\\. " +
+		"The port is optional\\.\n\n" +
+		"Send /cancel to cancel\\.",
+	"wizard.address_placeholder": "mc.example.com:25565",
+	"wizard.invalid_address": "❌ Invalid address: %s\\.\n\n" +
+		"Send the address again or /cancel to cancel\\.",
+	"wizard.offline": "🔴 The server `%s` did not respond: %s\\.\n\n" +
+		"Check the address and send it again or /cancel to cancel\\.",
+	"wizard.name":             "%s\nAddress: `%s`\n\nWhat should the server be called? Send its name\\.",
+	"wizard.name_placeholder": "My server",
+	"wizard.invalid_name":     "❌ The name must be from 1 to %d characters long\\. Send another name\\.",
+	"wizard.confirm":          "➕ *Add the server?*\n\nName: *%s*\nEdition: %s\nAddress: `%s`",
+	"wizard.saved":            "✅ Server *%s* added\\!",
+	"wizard.canceled":         "❌ Adding the server was canceled\\.",
+	"wizard.inactive":         "⚠️ This server setup is already finished, expired or was started by another member.",
+
+	"cancel.done":    "❌ Canceled.",
+	"cancel.nothing": "Nothing to cancel.",
+
+	// Notifications
+	"notify.offline": "🔴 *%s* stopped responding\n\nAddress: `%s`%s",
+	"notify.online":  "🟢 *%s* is back online\n\nAddress: `%s`\nOnline: %d/%d",
+	"notify.joined":  "➕ Joined: %s",
+	"notify.left":    "➖ Left: %s",
+
+	// Pinned status cards
+	"pin.admins_only": "⛔ Only chat admins can pin the status.",
+	"pin.no_servers":  "⚠️ No server is set up.\nUse /mss and press Settings.",
+	"pin.pick":        "📌 *Choose a server to pin:*",
+	"pin.not_found":   "⚠️ Server not found.",
+	"pin.no_rights": "⚠️ Could not pin the message: allow the bot to pin messages. " +
+		"It will not be updated; run /pin again once the bot can pin messages.",
+	"pin.updated": "🕒 _Updated: %s_",
+
+	// RCON
+	"rcon.admins_only": "⛔ Only chat admins can run RCON commands.",
+	"rcon.usage": "❌ Invalid format\\.\n\n" +
+		"Usage: `/rcon <command>`\n" +
+		"Example: `/rcon whitelist add Steve`",
+	"rcon.no_servers": "⚠️ RCON is not set up for any server.\n" +
+		"Open /mss → Settings → a server and press RCON.",
+	"rcon.pick":              "🖥 Choose a server for `%s`:",
+	"rcon.confirm":           "⚠️ Run `%s` on *%s*?\n\nThis command may stop the server or affect players\\.",
+	"rcon.failed":            "❌ Command failed: %s",
+	"rcon.canceled":          "❌ Command canceled\\.",
+	"rcon.setup_admins_only": "⛔ Only chat admins can set up RCON.",
+	"rcon.prompt": "🖥 Send the RCON port and password for *%s* separated by a space\\.\n\n" +
+		"These are `rcon.port` and `rcon.password` from server\\.properties, e\\.g\\. `25575 password`\\. " +
+		"Without a port, `25575` is used\\. Send `off` to disable RCON\\.\n\n" +
+		"The password is stored encrypted, and the message with it will be deleted\\.",
+	"rcon.placeholder": "25575 password",
+	"rcon.invalid":     "❌ Invalid format. Expected \"port password\", \"password\" or \"off\".",
+	"rcon.disabled":    "✅ RCON disabled!",
+	"rcon.saved":       "✅ RCON settings saved!",
+	"rcon.empty_reply": "(empty reply)",
+
+	"rcon.error.disabled":       "RCON is disabled: no encryption key is set in the bot configuration",
+	"rcon.error.not_configured": "RCON is not set up for the server",
+	"rcon.error.auth":           "the server rejected the RCON password",
+	"rcon.error.invalid_player": "invalid player name",
+	"rcon.error.not_found":      "server not found",
+
+	// Whitelist
+	"whitelist.admins_only":    "⛔ Only chat admins can manage the whitelist.",
+	"whitelist.fetch_failed":   "❌ Could not get the whitelist: %s",
+	"whitelist.confirm_remove": "👤 *%s*\n\nRemove the player from the whitelist of *%s*?",
+	"whitelist.remove_failed":  "❌ Could not remove the player: %s",
+	"whitelist.add_prompt": "➕ Send the names of the players to add to the whitelist of *%s*\\.\n\n" +
+		"Separate names with spaces or commas, up to %d at a time\\.",
+	"whitelist.invalid_names": "❌ Invalid name. Names consist of Latin letters, digits and \"_\", up to 16 characters.",
+	"whitelist.changed":       "📋 Whitelist changed (%s):\n%s",
+	"whitelist.title":         "📋 *Whitelist of %s*",
+	"whitelist.empty":         "The whitelist is empty\\.",
+	"whitelist.count":         "Total: %d player|Total: %d players",
+	"whitelist.hint":          "Tap a player to remove them from the whitelist\\.",
+
+	// Inline mode
+	"inline.switch_pm": "Type a server address or add your own",
+}
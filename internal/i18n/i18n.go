@@ -0,0 +1,143 @@
+// Package i18n provides the message catalogs of the bot and picks the language of a chat.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Lang is a language the bot speaks, identified by its ISO 639-1 code
+type Lang string
+
+const (
+	// RU is Russian
+	RU Lang = "ru"
+	// EN is English
+	EN Lang = "en"
+)
+
+// Default is the language used when nothing is known about a chat
+const Default = RU
+
+// Languages lists the supported languages in the order the language switch cycles through them
+var Languages = []Lang{RU, EN}
+
+// pluralSeparator separates the plural forms of a catalog message
+const pluralSeparator = "|"
+
+// catalogs maps each supported language to its messages
+var catalogs = map[Lang]map[string]string{
+	RU: ru,
+	EN: en,
+}
+
+// Parse returns the supported language with the given code, ignoring the region, e.g. "en-US"
+func Parse(code string) (Lang, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(code)), "-")
+	lang := Lang(base)
+	_, ok := catalogs[lang]
+	return lang, ok
+}
+
+// Detect picks the language for a Telegram client language code.
+// Unknown languages get English; an empty code, e.g. for background messages, gets the default language.
+func Detect(code string) Lang {
+	if code == "" {
+		return Default
+	}
+	if lang, ok := Parse(code); ok {
+		return lang
+	}
+	return EN
+}
+
+// Resolve picks the language of a chat: the one chosen in the chat settings,
+// or the language of the user's Telegram client when none was chosen
+func Resolve(chosen, clientCode string) Lang {
+	if lang, ok := Parse(chosen); ok {
+		return lang
+	}
+	return Detect(clientCode)
+}
+
+// Name returns the name of the language in the language itself
+func (l Lang) Name() string {
+	return l.T("language.name")
+}
+
+// Next returns the language after l in Languages, wrapping around
+func (l Lang) Next() Lang {
+	for i, lang := range Languages {
+		if lang == l {
+			return Languages[(i+1)%len(Languages)]
+		}
+	}
+	return Default
+}
+
+// T returns the message with the given key formatted with args.
+// Messages missing from the catalog fall back to the default language, then to the key itself.
+func (l Lang) T(key string, args ...any) string {
+	message := l.message(key)
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// N returns the plural form of the message with the given key for n, formatted with n followed by args
+func (l Lang) N(key string, n int, args ...any) string {
+	forms := strings.Split(l.message(key), pluralSeparator)
+	form := forms[min(l.pluralForm(n), len(forms)-1)]
+	return fmt.Sprintf(form, append([]any{n}, args...)...)
+}
+
+func (l Lang) message(key string) string {
+	if message, ok := catalogs[l][key]; ok {
+		return message
+	}
+	if message, ok := catalogs[Default][key]; ok {
+		return message
+	}
+	return key
+}
+
+// pluralForm returns the index of the plural form for n: one, few and many in Russian, one and other in English
+func (l Lang) pluralForm(n int) int {
+	if n < 0 {
+		n = -n
+	}
+
+	switch l {
+	case RU:
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return 0
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return 1
+		default:
+			return 2
+		}
+	default:
+		if n == 1 {
+			return 0
+		}
+		return 1
+	}
+}
+
+type contextKey struct{}
+
+// WithLang returns a copy of ctx carrying the language of the chat being served
+func WithLang(ctx context.Context, lang Lang) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// FromContext returns the language carried by ctx, or the default language
+func FromContext(ctx context.Context) Lang {
+	if lang, ok := ctx.Value(contextKey{}).(Lang); ok {
+		return lang
+	}
+	return Default
+}
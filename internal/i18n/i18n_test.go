@@ -0,0 +1,117 @@
+package i18n
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pluralKeys lists the messages formatted with N
+var pluralKeys = []string{"status.more_players", "mods.count", "whitelist.count"}
+
+var verbPattern = regexp.MustCompile(`%[-+# 0]*\d*[a-zA-Z%]`)
+
+func TestCatalogs_SameKeys(t *testing.T) {
+	for key := range ru {
+		_, ok := en[key]
+		assert.True(t, ok, "%q is missing from the English catalog", key)
+	}
+	for key := range en {
+		_, ok := ru[key]
+		assert.True(t, ok, "%q is missing from the Russian catalog", key)
+	}
+}
+
+func TestCatalogs_SameVerbs(t *testing.T) {
+	for key, message := range ru {
+		ruVerbs := verbPattern.FindAllString(strings.Split(message, pluralSeparator)[0], -1)
+		enVerbs := verbPattern.FindAllString(strings.Split(en[key], pluralSeparator)[0], -1)
+		assert.Equal(t, ruVerbs, enVerbs, "format verbs of %q differ", key)
+	}
+}
+
+func TestCatalogs_PluralForms(t *testing.T) {
+	for _, key := range pluralKeys {
+		assert.Len(t, strings.Split(ru[key], pluralSeparator), 3, key)
+		assert.Len(t, strings.Split(en[key], pluralSeparator), 2, key)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		code string
+		lang Lang
+		ok   bool
+	}{
+		{"ru", RU, true},
+		{"en", EN, true},
+		{"en-US", EN, true},
+		{" RU ", RU, true},
+		{"de", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		lang, ok := Parse(tt.code)
+		assert.Equal(t, tt.ok, ok, tt.code)
+		if tt.ok {
+			assert.Equal(t, tt.lang, lang, tt.code)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	assert.Equal(t, Default, Detect(""))
+	assert.Equal(t, RU, Detect("ru"))
+	assert.Equal(t, EN, Detect("en-GB"))
+	assert.Equal(t, EN, Detect("de"), "unsupported languages get English")
+}
+
+func TestResolve(t *testing.T) {
+	assert.Equal(t, EN, Resolve("en", "ru"), "the chosen language wins")
+	assert.Equal(t, RU, Resolve("", "ru"))
+	assert.Equal(t, EN, Resolve("", "uk"))
+	assert.Equal(t, Default, Resolve("", ""))
+}
+
+func TestLang_Next(t *testing.T) {
+	assert.Equal(t, EN, RU.Next())
+	assert.Equal(t, RU, EN.Next())
+	assert.Equal(t, Default, Lang("de").Next())
+}
+
+func TestLang_N(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{1, "Всего: 1 игрок"},
+		{2, "Всего: 2 игрока"},
+		{5, "Всего: 5 игроков"},
+		{11, "Всего: 11 игроков"},
+		{21, "Всего: 21 игрок"},
+		{22, "Всего: 22 игрока"},
+		{112, "Всего: 112 игроков"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, RU.N("whitelist.count", tt.n))
+	}
+
+	assert.Equal(t, "Total: 1 player", EN.N("whitelist.count", 1))
+	assert.Equal(t, "Total: 2 players", EN.N("whitelist.count", 2))
+}
+
+func TestLang_T(t *testing.T) {
+	assert.Equal(t, "Page 2/3", EN.T("page", 2, 3))
+	assert.Equal(t, "English", EN.Name())
+	assert.Equal(t, "Русский", Lang("de").Name(), "missing catalogs fall back to the default language")
+	assert.Equal(t, "no.such.key", EN.T("no.such.key"))
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, Default, FromContext(context.Background()))
+	assert.Equal(t, EN, FromContext(WithLang(context.Background(), EN)))
+}
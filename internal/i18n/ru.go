@@ -0,0 +1,227 @@
+package i18n
+
+// ru is the Russian catalog. Messages sent with MarkdownV2 are escaped accordingly;
+// plural forms (one, few, many) are separated by "|".
+var ru = map[string]string{
+	"language.name": "Русский",
+
+	// Common words and messages
+	"on":         "вкл",
+	"off":        "выкл",
+	"auto":       "авто",
+	"error":      "❌ Ошибка: %v",
+	"error.save": "❌ Ошибка сохранения: %v",
+	"page":       "Страница %d/%d",
+
+	// Buttons
+	"btn.status":               "📊 Статус",
+	"btn.settings":             "⚙️ Настройки",
+	"btn.back":                 "◀️ Назад",
+	"btn.refresh":              "🔄 Обновить",
+	"btn.mods":                 "🧩 Моды",
+	"btn.all_servers":          "📋 Все серверы",
+	"btn.player_notifications": "🔔 Вход/выход игроков: %s",
+	"btn.access":               "👥 Настраивать могут: %s",
+	"btn.language":             "🌐 Язык: %s",
+	"btn.query":                "📡 Query: %s",
+	"btn.query_port":           "🔢 Порт query",
+	"btn.protocol":             "🧬 Протокол: %s",
+	"btn.rcon":                 "🖥 RCON: %s",
+	"btn.whitelist":            "📋 Вайтлист",
+	"btn.delete":               "🗑 Удалить",
+	"btn.cancel":               "❌ Отмена",
+	"btn.run":                  "⚠️ Выполнить",
+	"btn.add":                  "➕ Добавить",
+	"btn.add_server":           "➕ Добавить сервер",
+	"btn.save":                 "✅ Сохранить",
+
+	// Commands and menus
+	"start": "👋 Привет! Я бот для проверки статуса Minecraft серверов.\n\n" +
+		"Используйте /mss для открытия меню.",
+	"help": "📖 *Справка*\n\n" +
+		"*Команды:*\n" +
+		"/mss \\- Открыть главное меню\n" +
+		"/set \\- Добавить сервер пошагово\n" +
+		"/set \\[java\\|bedrock\\] \\<ip:port\\> \\<название\\> \\- Добавить сервер одной командой\n" +
+		"/cancel \\- Отменить добавление сервера\n" +
+		"/pin \\- Закрепить статус сервера, который обновляется сам\n" +
+		"/rcon \\<команда\\> \\- Выполнить команду на сервере \\(только для администраторов\\)\n\n" +
+		"*Пример:*\n" +
+		"`/set mc.example.com:25565 My Server`",
+	"menu": "🎮 *Minecraft Server Status*\n\nВыберите действие:",
+
+	"address.empty":        "не указан адрес сервера",
+	"address.invalid_port": "порт должен быть числом",
+	"address.port_range":   "порт должен быть от 1 до 65535",
+	"address.invalid_host": "недопустимое имя хоста или IP-адрес",
+
+	"status.no_servers": "⚠️ Сервер не настроен\\.\n\nИспользуйте настройки для добавления сервера\\.",
+	"status.pick":       "📊 *Выберите сервер:*",
+	"status.not_found":  "⚠️ Сервер не найден\\.\n\nВозможно, он был удалён в настройках\\.",
+
+	"query_port.prompt": "🔢 Отправьте порт query для *%s*\\.\n\n" +
+		"Это значение `query.port` из server\\.properties\\. Отправьте `0`, чтобы использовать порт сервера\\.",
+	"query_port.invalid": "❌ Неверный порт. Ожидается число от 0 до 65535.",
+	"query_port.saved":   "✅ Порт query сохранён!",
+
+	"protocol.prompt": "🧬 Отправьте версию протокола для *%s*\\.\n\n" +
+		"Она передаётся серверу при подключении; некоторые прокси отвечают по\\-разному в зависимости от неё\\. " +
+		"Например, `765` — это 1\\.20\\.4\\. Отправьте `авто`, чтобы использовать протокол самого сервера\\.",
+	"protocol.invalid": "❌ Неверная версия протокола. Ожидается положительное число или «авто».",
+	"protocol.saved":   "✅ Версия протокола сохранена!",
+
+	// Server status
+	"status.not_configured": "Сервер не настроен. Используйте настройки для добавления сервера.",
+	"status.offline":        "🔴 *%s*\n\nАдрес: `%s`\nСтатус: Недоступен%s%s",
+	"status.online":         "🟢 *%s*\n\n%sАдрес: %s\nВерсия: %s\n%s%s%s%sОнлайн: %d/%d%s%s",
+	"status.players":        "👥 *Игроки онлайн:*",
+	"status.more_players":   "… и ещё %d игрок|… и ещё %d игрока|… и ещё %d игроков",
+	"status.checked":        "🕒 _Проверено %s_",
+	"status.reason":         "Причина: %s",
+	"status.ping":           "Пинг: %s %d мс",
+	"status.ping_details":   " \\(DNS %d мс, подключение %d мс\\)",
+	"status.edition":        "Издание: %s",
+	"status.game_mode":      "Режим: %s",
+	"status.world":          "Мир: %s",
+	"status.map":            "Карта: %s",
+	"status.software":       "ПО: %s",
+	"status.plugins":        "Плагины \\(%d\\): %s",
+
+	"summary.online":         "🟢 Онлайн %d/%d",
+	"summary.offline":        "🔴 Недоступен",
+	"summary.offline_reason": "🔴 Недоступен: %s",
+
+	"age.now":     "только что",
+	"age.seconds": "%d с назад",
+	"age.minutes": "%d мин назад",
+
+	"explain.dns":         "адрес сервера не найден (ошибка DNS)",
+	"explain.refused":     "соединение отклонено: сервер выключен или порт закрыт",
+	"explain.unreachable": "нет сетевого маршрута до сервера",
+	"explain.timeout":     "сервер не ответил вовремя",
+	"explain.reset":       "соединение сброшено сервером или прокси",
+	"explain.protocol":    "сервер ответил, но это не похоже на Minecraft",
+	"explain.private":     "адрес ведёт во внутреннюю сеть",
+	"explain.unknown":     "неизвестная ошибка",
+
+	"mods.title":     "🧩 *Моды %s*",
+	"mods.none":      "Сервер не сообщает о модах\\.",
+	"mods.loader":    "Загрузчик: %s",
+	"mods.count":     "Всего: %d мод|Всего: %d мода|Всего: %d модов",
+	"mods.truncated": "_Сервер передал не весь список модов\\._",
+	"mods.summary":   "Моды: %d \\(%s\\)",
+
+	// Settings
+	"config.admins_only": "⛔ В этой группе менять серверы и настройки могут только администраторы чата.\n" +
+		"Администратор может разрешить это всем участникам в настройках.",
+	"access.admins_only": "⛔ Разрешить настройку всем участникам могут только администраторы чата.",
+	"access.admins":      "админы",
+	"access.everyone":    "все",
+	"access.sender_chat": "⛔ Сообщения от имени канала нельзя сверить со списком администраторов чата. " +
+		"Отправьте команду со своего аккаунта или анонимно от имени этой группы.",
+
+	"config.empty": "⚙️ *Настройки сервера*\n\n" +
+		"Сервер не настроен\\.\n\n" +
+		"Нажмите «➕ Добавить сервер» — бот спросит адрес и название и проверит, что сервер отвечает\\. " +
+		"Или отправьте команду:\n" +
+		"`/set <ip>:<port> <name>`",
+	"config.title":   "⚙️ *Настройки серверов*",
+	"config.unnamed": "Не указано",
+	"config.server":  "%d\\. *%s*\nИздание: %s\nIP: `%s`\nПорт: `%d`\n%s\n",
+	"config.footer": "Чтобы добавить сервер, нажмите «➕ Добавить сервер» или отправьте команду:\n" +
+		"`/set [java|bedrock] <ip>:<port> <name>`\n\n" +
+		"Чтобы изменить или удалить сервер, нажмите на него ниже\\.",
+	"config.protocol_auto": "Протокол: авто",
+	"config.protocol":      "Протокол: `%d`",
+	"config.query_off":     "Query: выкл",
+	"config.query_on":      "Query: вкл, порт `%d`",
+	"config.rcon_off":      "RCON: выкл",
+	"config.rcon_on":       "RCON: вкл, порт `%d`",
+
+	"server_settings": "⚙️ *%s*\n\nИздание: %s\nIP: `%s`\nПорт: `%d`\n%s%s%s",
+	"server_settings.query_hint": "Query даёт полный список игроков, карту и плагины\\. " +
+		"Включите `enable-query=true` в server\\.properties\\.",
+	"server_settings.confirm_delete": "🗑 *%s*\n\nУдалить сервер из этого чата? " +
+		"Его история статусов и закреплённые карточки тоже будут удалены\\.",
+
+	// Setup wizard
+	"wizard.address": "➕ *Добавление сервера*\n\n" +
+		"Отправьте адрес сервера, например `mc\\.example\\.com` или `bedrock play\\.example\\.net:19132`\\. " +
+		"Порт можно не указывать\\.\n\n" +
+		"Отправьте /cancel, чтобы отменить\\.",
+	"wizard.address_placeholder": "mc.example.com:25565",
+	"wizard.invalid_address": "❌ Неверный адрес: %s\\.\n\n" +
+		"Отправьте адрес ещё раз или /cancel, чтобы отменить\\.",
+	"wizard.offline": "🔴 Сервер `%s` не ответил: %s\\.\n\n" +
+		"Проверьте адрес и отправьте его ещё раз или /cancel, чтобы отменить\\.",
+	"wizard.name":             "%s\nАдрес: `%s`\n\nКак назвать сервер? Отправьте название\\.",
+	"wizard.name_placeholder": "Мой сервер",
+	"wizard.invalid_name":     "❌ Название должно быть длиной от 1 до %d символов\\. Отправьте другое название\\.",
+	"wizard.confirm":          "➕ *Добавить сервер?*\n\nНазвание: *%s*\nИздание: %s\nАдрес: `%s`",
+	"wizard.saved":            "✅ Сервер *%s* добавлен\\!",
+	"wizard.canceled":         "❌ Добавление сервера отменено\\.",
+	"wizard.inactive":         "⚠️ Это добавление сервера уже завершено, истекло или начато другим участником.",
+
+	"cancel.done":    "❌ Отменено.",
+	"cancel.nothing": "Нечего отменять.",
+
+	// Notifications
+	"notify.offline": "🔴 *%s* перестал отвечать\n\nАдрес: `%s`%s",
+	"notify.online":  "🟢 *%s* снова в сети\n\nАдрес: `%s`\nОнлайн: %d/%d",
+	"notify.joined":  "➕ Зашли: %s",
+	"notify.left":    "➖ Вышли: %s",
+
+	// Pinned status cards
+	"pin.admins_only": "⛔ Закреплять статус могут только администраторы чата.",
+	"pin.no_servers":  "⚠️ Сервер не настроен.\nИспользуйте /mss и нажмите кнопку Настройки.",
+	"pin.pick":        "📌 *Выберите сервер для закрепления:*",
+	"pin.not_found":   "⚠️ Сервер не найден.",
+	"pin.no_rights": "⚠️ Не удалось закрепить сообщение: дайте боту право закреплять сообщения. " +
+		"Оно не будет обновляться; выполните /pin ещё раз, когда бот сможет закреплять сообщения.",
+	"pin.updated": "🕒 _Обновлено: %s_",
+
+	// RCON
+	"rcon.admins_only": "⛔ Команды RCON доступны только администраторам чата.",
+	"rcon.usage": "❌ Неверный формат\\.\n\n" +
+		"Использование: `/rcon <команда>`\n" +
+		"Пример: `/rcon whitelist add Steve`",
+	"rcon.no_servers": "⚠️ RCON не настроен ни для одного сервера.\n" +
+		"Откройте /mss → Настройки → сервер и нажмите кнопку RCON.",
+	"rcon.pick":              "🖥 Выберите сервер для `%s`:",
+	"rcon.confirm":           "⚠️ Выполнить `%s` на *%s*?\n\nЭта команда может остановить сервер или затронуть игроков\\.",
+	"rcon.failed":            "❌ Команда не выполнена: %s",
+	"rcon.canceled":          "❌ Команда отменена\\.",
+	"rcon.setup_admins_only": "⛔ Настраивать RCON могут только администраторы чата.",
+	"rcon.prompt": "🖥 Отправьте порт и пароль RCON для *%s* через пробел\\.\n\n" +
+		"Это значения `rcon.port` и `rcon.password` из server\\.properties, например `25575 пароль`\\. " +
+		"Без порта используется `25575`\\. Отправьте `выкл`, чтобы отключить RCON\\.\n\n" +
+		"Пароль хранится в зашифрованном виде, а сообщение с ним будет удалено\\.",
+	"rcon.placeholder": "25575 пароль",
+	"rcon.invalid":     "❌ Неверный формат. Ожидается «порт пароль», «пароль» или «выкл».",
+	"rcon.disabled":    "✅ RCON отключён!",
+	"rcon.saved":       "✅ Настройки RCON сохранены!",
+	"rcon.empty_reply": "(пустой ответ)",
+
+	"rcon.error.disabled":       "RCON отключён: ключ шифрования не задан в конфигурации бота",
+	"rcon.error.not_configured": "RCON для сервера не настроен",
+	"rcon.error.auth":           "сервер отклонил пароль RCON",
+	"rcon.error.invalid_player": "неверное имя игрока",
+	"rcon.error.not_found":      "сервер не найден",
+
+	// Whitelist
+	"whitelist.admins_only":    "⛔ Управлять вайтлистом могут только администраторы чата.",
+	"whitelist.fetch_failed":   "❌ Не удалось получить вайтлист: %s",
+	"whitelist.confirm_remove": "👤 *%s*\n\nУдалить игрока из вайтлиста *%s*?",
+	"whitelist.remove_failed":  "❌ Не удалось удалить игрока: %s",
+	"whitelist.add_prompt": "➕ Отправьте ники игроков, которых нужно добавить в вайтлист *%s*\\.\n\n" +
+		"Несколько ников разделяйте пробелами или запятыми, не больше %d за раз\\.",
+	"whitelist.invalid_names": "❌ Неверный ник. Ник состоит из латинских букв, цифр и «_», не длиннее 16 символов.",
+	"whitelist.changed":       "📋 Вайтлист изменён (%s):\n%s",
+	"whitelist.title":         "📋 *Вайтлист %s*",
+	"whitelist.empty":         "Вайтлист пуст\\.",
+	"whitelist.count":         "Всего: %d игрок|Всего: %d игрока|Всего: %d игроков",
+	"whitelist.hint":          "Нажмите на игрока, чтобы удалить его из вайтлиста\\.",
+
+	// Inline mode
+	"inline.switch_pm": "Введите адрес сервера или добавьте свой",
+}
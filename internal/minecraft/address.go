@@ -0,0 +1,259 @@
+package minecraft
+
+import (
+	"errors"
+	"math"
+	"net/netip"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxHostLength is the longest host name DNS allows, without the trailing dot.
+	maxHostLength = 253
+	// maxLabelLength is the longest label of a host name.
+	maxLabelLength = 63
+	// idnaPrefix marks a punycode-encoded label.
+	idnaPrefix = "xn--"
+)
+
+// Address validation errors, reported wrapped in an *AddressError
+var (
+	ErrEmptyHost      = errors.New("host is empty")
+	ErrInvalidHost    = errors.New("invalid host name")
+	ErrInvalidPort    = errors.New("port is not a number")
+	ErrPortOutOfRange = errors.New("port is out of range 1-65535")
+)
+
+// AddressError is returned when a server address cannot be parsed
+type AddressError struct {
+	Address string
+	Err     error
+}
+
+func (e *AddressError) Error() string {
+	if e.Address == "" {
+		return e.Err.Error()
+	}
+	return e.Err.Error() + ": " + e.Address
+}
+
+func (e *AddressError) Unwrap() error {
+	return e.Err
+}
+
+// splitHostPort splits an address into host and port, where the port is optional.
+// IPv6 literals may be given bare or, to add a port, in brackets.
+func splitHostPort(address string) (host, port string, hasPort bool, err error) {
+	if strings.HasPrefix(address, "[") {
+		end := strings.IndexByte(address, ']')
+		if end < 0 {
+			return "", "", false, ErrInvalidHost
+		}
+		host, rest := address[1:end], address[end+1:]
+		if _, err := netip.ParseAddr(host); err != nil || !strings.Contains(host, ":") {
+			return "", "", false, ErrInvalidHost
+		}
+		if rest == "" {
+			return host, "", false, nil
+		}
+		if !strings.HasPrefix(rest, ":") {
+			return "", "", false, ErrInvalidHost
+		}
+		return host, rest[1:], true, nil
+	}
+
+	switch strings.Count(address, ":") {
+	case 0:
+		return address, "", false, nil
+	case 1:
+		host, port, _ = strings.Cut(address, ":")
+		return host, port, true, nil
+	default:
+		// Only a bare IPv6 literal has several colons; with a port it must be bracketed
+		return address, "", false, nil
+	}
+}
+
+// parsePort parses a port number in the range 1-65535.
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, ErrInvalidPort
+	}
+	if port < 1 || port > math.MaxUint16 {
+		return 0, ErrPortOutOfRange
+	}
+	return port, nil
+}
+
+// normalizeHost validates a host name or IP literal and returns it in the form used for queries:
+// IP literals in their canonical form, host names lower-cased without the trailing dot
+// and with international labels encoded in punycode.
+func normalizeHost(host string) (string, error) {
+	if host == "" {
+		return "", ErrEmptyHost
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return addr.String(), nil
+	}
+	if strings.Contains(host, ":") {
+		return "", ErrInvalidHost
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	labels := strings.Split(host, ".")
+	for i, label := range labels {
+		if !isASCII(label) {
+			encoded, err := punycodeEncode(label)
+			if err != nil {
+				return "", err
+			}
+			label = idnaPrefix + encoded
+			labels[i] = label
+		}
+		if !validLabel(label) {
+			return "", ErrInvalidHost
+		}
+	}
+
+	// Something like 300.1.1.1 is a mistyped IP address rather than a host name
+	if isNumeric(labels[len(labels)-1]) {
+		return "", ErrInvalidHost
+	}
+
+	host = strings.Join(labels, ".")
+	if len(host) > maxHostLength {
+		return "", ErrInvalidHost
+	}
+	return host, nil
+}
+
+// validLabel reports whether a host name label is 1-63 letters, digits, hyphens or underscores,
+// not starting or ending with a hyphen. Underscores are not valid in host names,
+// but are common enough in internal DNS names to let through.
+func validLabel(label string) bool {
+	if label == "" || len(label) > maxLabelLength || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, c := range label {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+func isNumeric(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Punycode parameters from RFC 3492.
+const (
+	punyBase        = 36
+	punyTMin        = 1
+	punyTMax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+)
+
+// punycodeEncode encodes a label as described in RFC 3492, without the xn-- prefix.
+func punycodeEncode(label string) (string, error) {
+	if !utf8.ValidString(label) {
+		return "", ErrInvalidHost
+	}
+	runes := []rune(label)
+
+	var out []byte
+	for _, r := range runes {
+		if r < utf8.RuneSelf {
+			out = append(out, byte(r))
+		}
+	}
+	basic := len(out)
+	if basic > 0 {
+		out = append(out, '-')
+	}
+
+	n, delta, bias := punyInitialN, 0, punyInitialBias
+	for handled := basic; handled < len(runes); {
+		next := math.MaxInt32
+		for _, r := range runes {
+			if int(r) >= n && int(r) < next {
+				next = int(r)
+			}
+		}
+		delta += (next - n) * (handled + 1)
+		n = next
+
+		for _, r := range runes {
+			if int(r) < n {
+				delta++
+			}
+			if int(r) != n {
+				continue
+			}
+
+			q := delta
+			for k := punyBase; ; k += punyBase {
+				t := min(max(k-bias, punyTMin), punyTMax)
+				if q < t {
+					break
+				}
+				out = append(out, punyDigit(t+(q-t)%(punyBase-t)))
+				q = (q - t) / (punyBase - t)
+			}
+			out = append(out, punyDigit(q))
+
+			bias = punyAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+		delta++
+		n++
+	}
+
+	return string(out), nil
+}
+
+func punyAdapt(delta, points int, first bool) int {
+	if first {
+		delta /= punyDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / points
+
+	k := 0
+	for delta > (punyBase-punyTMin)*punyTMax/2 {
+		delta /= punyBase - punyTMin
+		k += punyBase
+	}
+	return k + (punyBase-punyTMin+1)*delta/(delta+punySkew)
+}
+
+func punyDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
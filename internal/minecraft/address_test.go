@@ -0,0 +1,111 @@
+package minecraft
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddress_IPv6(t *testing.T) {
+	tests := []struct {
+		address string
+		host    string
+		port    int
+	}{
+		{"[2001:db8::1]:25566", "2001:db8::1", 25566},
+		{"[2001:db8::1]", "2001:db8::1", 25565},
+		{"2001:db8::1", "2001:db8::1", 25565},
+		{"2001:DB8:0:0::1", "2001:db8::1", 25565},
+		{"[::1]:19132", "::1", 19132},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			host, port, err := ParseAddress(tt.address)
+			require.NoError(t, err)
+			assert.Equal(t, tt.host, host)
+			assert.Equal(t, tt.port, port)
+		})
+	}
+}
+
+func TestParseAddress_NormalizesHost(t *testing.T) {
+	tests := []struct {
+		address string
+		host    string
+	}{
+		{"  MC.Example.COM.  ", "mc.example.com"},
+		{"münchen.example:25566", "xn--mnchen-3ya.example"},
+		{"ИСПЫТАНИЕ.рф", "xn--80akhbyknj4f.xn--p1ai"},
+		{"xn--80akhbyknj4f.xn--p1ai", "xn--80akhbyknj4f.xn--p1ai"},
+		{"mc_internal.lan", "mc_internal.lan"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			host, _, err := ParseAddress(tt.address)
+			require.NoError(t, err)
+			assert.Equal(t, tt.host, host)
+		})
+	}
+}
+
+func TestParseAddress_Invalid(t *testing.T) {
+	tests := []struct {
+		address string
+		err     error
+	}{
+		{"", ErrEmptyHost},
+		{":25565", ErrEmptyHost},
+		{"mc.example.com:", ErrInvalidPort},
+		{"mc.example.com:abc", ErrInvalidPort},
+		{"mc.example.com:0", ErrPortOutOfRange},
+		{"mc.example.com:99999", ErrPortOutOfRange},
+		{"[2001:db8::1]:65536", ErrPortOutOfRange},
+		{"mc example.com", ErrInvalidHost},
+		{"mc..example.com", ErrInvalidHost},
+		{"-mc.example.com", ErrInvalidHost},
+		{"mc.example!.com", ErrInvalidHost},
+		{"300.1.1.1", ErrInvalidHost},
+		{"[2001:db8::1", ErrInvalidHost},
+		{"[mc.example.com]:25565", ErrInvalidHost},
+		{"[2001:db8::1]25565", ErrInvalidHost},
+		{"2001:db8::zz", ErrInvalidHost},
+		{strings.Repeat("a", 64) + ".example.com", ErrInvalidHost},
+		{strings.Repeat("abcdefghi.", 26) + "com", ErrInvalidHost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			_, _, err := ParseAddress(tt.address)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tt.err)
+
+			var addrErr *AddressError
+			assert.ErrorAs(t, err, &addrErr)
+		})
+	}
+}
+
+func TestFormatAddress_IPv6(t *testing.T) {
+	assert.Equal(t, "2001:db8::1", FormatAddress("2001:db8::1", 25565))
+	assert.Equal(t, "[2001:db8::1]:25566", FormatAddress("2001:db8::1", 25566))
+}
+
+func TestPunycodeEncode(t *testing.T) {
+	tests := map[string]string{
+		"münchen":   "mnchen-3ya",
+		"bücher":    "bcher-kva",
+		"испытание": "80akhbyknj4f",
+		"рф":        "p1ai",
+		"例え":        "r8jz45g",
+	}
+
+	for label, want := range tests {
+		got, err := punycodeEncode(label)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, label)
+	}
+}
@@ -0,0 +1,174 @@
+package minecraft
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultBedrockPort is the default Bedrock Edition server port.
+const DefaultBedrockPort = 19132
+
+const (
+	// raknetUnconnectedPing is the RakNet packet ID of an unconnected ping.
+	raknetUnconnectedPing byte = 0x01
+	// raknetUnconnectedPong is the RakNet packet ID of an unconnected pong.
+	raknetUnconnectedPong byte = 0x1c
+	// bedrockMaxPacketSize is large enough for any unconnected pong.
+	bedrockMaxPacketSize = 2048
+)
+
+// raknetMagic is the offline message ID every unconnected RakNet packet carries.
+var raknetMagic = []byte{
+	0x00, 0xff, 0xff, 0x00, 0xfe, 0xfe, 0xfe, 0xfe,
+	0xfd, 0xfd, 0xfd, 0xfd, 0x12, 0x34, 0x56, 0x78,
+}
+
+// GetBedrockStatus queries a Bedrock Edition server using a RakNet unconnected ping.
+// When the server cannot be queried, an offline status is returned together with a *StatusError.
+func (c *Client) GetBedrockStatus(ctx context.Context, host string, port int) (*ServerStatus, error) {
+	status, err := c.getBedrockStatus(ctx, host, port)
+	status.CheckedAt = time.Now()
+	return status, err
+}
+
+func (c *Client) getBedrockStatus(ctx context.Context, host string, port int) (*ServerStatus, error) {
+	log.Debug().Str("host", host).Int("port", port).Dur("timeout", c.timeout).Msg("starting bedrock server query")
+
+	status, err := c.pingBedrock(ctx, host, port)
+	if err != nil {
+		if ctx.Err() != nil {
+			log.Warn().Str("host", host).Int("port", port).Msg("bedrock query canceled")
+			return &ServerStatus{Online: false}, ctx.Err()
+		}
+		log.Warn().Err(err).Str("host", host).Int("port", port).Msg("bedrock server ping failed")
+		return &ServerStatus{Online: false}, newStatusError(err, err)
+	}
+
+	log.Debug().
+		Str("host", host).
+		Int("port", port).
+		Str("version", status.Version).
+		Int("players", status.Players.Online).
+		Msg("bedrock server query successful")
+	return status, nil
+}
+
+func (c *Client) pingBedrock(ctx context.Context, host string, port int) (*ServerStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, fmt.Errorf("could not dial: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, fmt.Errorf("could not set deadline: %w", err)
+		}
+	}
+
+	start := time.Now()
+	if _, err := conn.Write(buildBedrockPing(start, rand.Int63())); err != nil { //nolint:gosec // GUID needs no crypto randomness
+		return nil, fmt.Errorf("could not write ping packet: %w", err)
+	}
+
+	buf := make([]byte, bedrockMaxPacketSize)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, fmt.Errorf("could not read pong packet: %w", err)
+	}
+	latency := time.Since(start)
+
+	status, err := parseBedrockPong(buf[:n])
+	if err != nil {
+		return nil, err
+	}
+	status.Latency = latency
+	return status, nil
+}
+
+// buildBedrockPing builds a RakNet unconnected ping packet.
+func buildBedrockPing(now time.Time, clientGUID int64) []byte {
+	packet := bytes.NewBuffer(make([]byte, 0, 1+8+len(raknetMagic)+8))
+	packet.WriteByte(raknetUnconnectedPing)
+	_ = binary.Write(packet, binary.BigEndian, now.UnixMilli())
+	packet.Write(raknetMagic)
+	_ = binary.Write(packet, binary.BigEndian, clientGUID)
+	return packet.Bytes()
+}
+
+// parseBedrockPong parses a RakNet unconnected pong packet.
+//
+// The server ID string has the form
+// "MCPE;MOTD;protocol;version;online;max;serverID;level name;game mode;game mode ID;port v4;port v6;".
+func parseBedrockPong(packet []byte) (*ServerStatus, error) {
+	// ID (1) + time (8) + server GUID (8) + magic (16) + string length (2)
+	const headerSize = 1 + 8 + 8 + 16 + 2
+
+	if len(packet) < headerSize {
+		return nil, fmt.Errorf("pong packet too short: %d bytes", len(packet))
+	}
+	if packet[0] != raknetUnconnectedPong {
+		return nil, fmt.Errorf("unexpected packet ID: 0x%02x", packet[0])
+	}
+	if !bytes.Equal(packet[17:33], raknetMagic) {
+		return nil, fmt.Errorf("invalid RakNet magic")
+	}
+
+	length := int(binary.BigEndian.Uint16(packet[33:35]))
+	if len(packet) < headerSize+length {
+		return nil, fmt.Errorf("truncated server ID string: want %d bytes, got %d", length, len(packet)-headerSize)
+	}
+
+	fields := strings.Split(string(packet[headerSize:headerSize+length]), ";")
+	if len(fields) < 6 {
+		return nil, fmt.Errorf("malformed server ID string: %d fields", len(fields))
+	}
+
+	protocol, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid protocol version %q: %w", fields[2], err)
+	}
+	online, err := strconv.Atoi(fields[4])
+	if err != nil {
+		return nil, fmt.Errorf("invalid online player count %q: %w", fields[4], err)
+	}
+	maxPlayers, err := strconv.Atoi(fields[5])
+	if err != nil {
+		return nil, fmt.Errorf("invalid max player count %q: %w", fields[5], err)
+	}
+
+	motd := ParseLegacyText(fields[1])
+	status := &ServerStatus{
+		Online:      true,
+		Edition:     fields[0],
+		Description: motd.String(),
+		MOTD:        motd,
+		Protocol:    protocol,
+		Version:     fields[3],
+		Players: PlayersInfo{
+			Online: online,
+			Max:    maxPlayers,
+		},
+	}
+	if len(fields) > 7 {
+		status.LevelName = fields[7]
+	}
+	if len(fields) > 8 {
+		status.GameMode = fields[8]
+	}
+
+	return status, nil
+}
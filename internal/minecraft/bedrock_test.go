@@ -0,0 +1,111 @@
+package minecraft
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildTestPong(serverID string) []byte {
+	packet := new(bytes.Buffer)
+	packet.WriteByte(raknetUnconnectedPong)
+	_ = binary.Write(packet, binary.BigEndian, int64(1234))
+	_ = binary.Write(packet, binary.BigEndian, int64(5678))
+	packet.Write(raknetMagic)
+	_ = binary.Write(packet, binary.BigEndian, uint16(len(serverID)))
+	packet.WriteString(serverID)
+	return packet.Bytes()
+}
+
+func TestBuildBedrockPing(t *testing.T) {
+	packet := buildBedrockPing(time.UnixMilli(1000), 42)
+
+	require.Len(t, packet, 33)
+	assert.Equal(t, raknetUnconnectedPing, packet[0])
+	assert.Equal(t, int64(1000), int64(binary.BigEndian.Uint64(packet[1:9])))
+	assert.Equal(t, raknetMagic, packet[9:25])
+	assert.Equal(t, int64(42), int64(binary.BigEndian.Uint64(packet[25:33])))
+}
+
+func TestParseBedrockPong(t *testing.T) {
+	pong := buildTestPong("MCPE;Dedicated Server;594;1.20.10;3;10;13253860892328930865;Bedrock level;Survival;1;19132;19133;")
+
+	status, err := parseBedrockPong(pong)
+	require.NoError(t, err)
+
+	assert.True(t, status.Online)
+	assert.Equal(t, "MCPE", status.Edition)
+	assert.Equal(t, "Dedicated Server", status.Description)
+	assert.Equal(t, 594, status.Protocol)
+	assert.Equal(t, "1.20.10", status.Version)
+	assert.Equal(t, 3, status.Players.Online)
+	assert.Equal(t, 10, status.Players.Max)
+	assert.Equal(t, "Bedrock level", status.LevelName)
+	assert.Equal(t, "Survival", status.GameMode)
+}
+
+func TestParseBedrockPong_MinimalFields(t *testing.T) {
+	status, err := parseBedrockPong(buildTestPong("MCPE;Old Server;100;1.2.0;0;20"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.2.0", status.Version)
+	assert.Empty(t, status.LevelName)
+	assert.Empty(t, status.GameMode)
+}
+
+func TestParseBedrockPong_Invalid(t *testing.T) {
+	valid := buildTestPong("MCPE;Server;594;1.20.10;3;10;")
+
+	wrongID := append([]byte{}, valid...)
+	wrongID[0] = 0x00
+
+	badMagic := append([]byte{}, valid...)
+	badMagic[25] = 0x00
+
+	tests := map[string][]byte{
+		"too short":      valid[:10],
+		"wrong id":       wrongID,
+		"bad magic":      badMagic,
+		"truncated":      valid[:len(valid)-5],
+		"few fields":     buildTestPong("MCPE;Server;594"),
+		"bad player num": buildTestPong("MCPE;Server;594;1.20.10;many;10;"),
+	}
+
+	for name, packet := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseBedrockPong(packet)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestClient_GetBedrockStatus(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	go func() {
+		buf := make([]byte, 64)
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil || n == 0 || buf[0] != raknetUnconnectedPing {
+			return
+		}
+		_, _ = conn.WriteTo(buildTestPong("MCPE;Local;594;1.20.10;1;5;1;world;Creative;1;"), addr)
+	}()
+
+	client := NewClient(2 * time.Second)
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+
+	status, err := client.GetBedrockStatus(context.Background(), "127.0.0.1", port)
+	require.NoError(t, err)
+
+	assert.True(t, status.Online)
+	assert.Equal(t, "Local", status.Description)
+	assert.Equal(t, "Creative", status.GameMode)
+}
@@ -0,0 +1,138 @@
+package minecraft
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// failureCacheTTL bounds how long a failed query is reused, so that a server coming back
+// or a transient error clearing up is noticed quickly.
+const failureCacheTTL = 5 * time.Second
+
+// statusCache keeps recent status results and coalesces concurrent queries of the same server,
+// so chats watching one server share a single ping.
+type statusCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu       sync.Mutex
+	entries  map[string]cacheEntry
+	inflight map[string]*statusCall
+}
+
+type cacheEntry struct {
+	status *ServerStatus
+	err    error
+	ttl    time.Duration
+}
+
+// fresh reports whether the entry may still be served at now.
+func (e cacheEntry) fresh(now time.Time) bool {
+	return now.Sub(e.status.CheckedAt) < e.ttl
+}
+
+// statusCall is a query in progress; done is closed once status and err are set.
+type statusCall struct {
+	done   chan struct{}
+	status *ServerStatus
+	err    error
+}
+
+func newStatusCache(ttl time.Duration) *statusCache {
+	return &statusCache{
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  make(map[string]cacheEntry),
+		inflight: make(map[string]*statusCall),
+	}
+}
+
+// cacheKey builds the cache key of a server.
+func cacheKey(host string, port int) string {
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// do returns the cached result of key when it is fresh, or joins the query in progress, or runs query.
+// The query is not bound to ctx, so a caller giving up does not fail the others waiting for it.
+// Every caller gets its own copy of the status.
+func (sc *statusCache) do(
+	ctx context.Context,
+	key string,
+	query func(ctx context.Context) (*ServerStatus, error),
+) (*ServerStatus, error) {
+	sc.mu.Lock()
+	if entry, ok := sc.entries[key]; ok && entry.fresh(sc.now()) {
+		sc.mu.Unlock()
+		log.Debug().Str("key", key).Msg("minecraft status served from cache")
+		return entry.status.copy(), entry.err
+	}
+
+	call, joined := sc.inflight[key]
+	if !joined {
+		sc.purgeLocked()
+		call = &statusCall{done: make(chan struct{})}
+		sc.inflight[key] = call
+		go sc.run(context.WithoutCancel(ctx), key, call, query)
+	} else {
+		log.Debug().Str("key", key).Msg("joining minecraft status query in progress")
+	}
+	sc.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return &ServerStatus{Online: false}, ctx.Err()
+	case <-call.done:
+		return call.status.copy(), call.err
+	}
+}
+
+func (sc *statusCache) run(
+	ctx context.Context,
+	key string,
+	call *statusCall,
+	query func(ctx context.Context) (*ServerStatus, error),
+) {
+	status, err := query(ctx)
+	if status == nil {
+		status = &ServerStatus{Online: false}
+	}
+	if status.CheckedAt.IsZero() {
+		status.CheckedAt = sc.now()
+	}
+
+	sc.mu.Lock()
+	call.status, call.err = status, err
+	delete(sc.inflight, key)
+	ttl := sc.ttl
+	if err != nil {
+		ttl = min(ttl, failureCacheTTL)
+	}
+	if ttl > 0 {
+		sc.entries[key] = cacheEntry{status: status, err: err, ttl: ttl}
+	}
+	sc.mu.Unlock()
+
+	close(call.done)
+}
+
+// purgeLocked drops expired entries; sc.mu must be held.
+func (sc *statusCache) purgeLocked() {
+	now := sc.now()
+	for key, entry := range sc.entries {
+		if !entry.fresh(now) {
+			delete(sc.entries, key)
+		}
+	}
+}
+
+// copy returns a copy of the status that can be changed without affecting the original.
+// Slices are replaced rather than modified in place, so they are shared.
+func (s *ServerStatus) copy() *ServerStatus {
+	status := *s
+	return &status
+}
@@ -0,0 +1,186 @@
+package minecraft
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a settable time source for the status cache.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestCache(ttl time.Duration) (*statusCache, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	cache := newStatusCache(ttl)
+	cache.now = clock.Now
+	return cache, clock
+}
+
+func countingQuery(calls *atomic.Int32, players int) func(context.Context) (*ServerStatus, error) {
+	return func(context.Context) (*ServerStatus, error) {
+		calls.Add(1)
+		return &ServerStatus{Online: true, Players: PlayersInfo{Online: players}}, nil
+	}
+}
+
+func TestStatusCache_ReusesFreshResult(t *testing.T) {
+	cache, clock := newTestCache(30 * time.Second)
+	var calls atomic.Int32
+
+	first, err := cache.do(context.Background(), "a:1", countingQuery(&calls, 3))
+	require.NoError(t, err)
+	assert.Equal(t, clock.Now(), first.CheckedAt)
+
+	clock.Advance(29 * time.Second)
+	second, err := cache.do(context.Background(), "a:1", countingQuery(&calls, 4))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, 3, second.Players.Online)
+	assert.Equal(t, first.CheckedAt, second.CheckedAt)
+
+	// Callers get copies, so changing one does not touch the cached status
+	second.Players.Online = 100
+	third, _ := cache.do(context.Background(), "a:1", countingQuery(&calls, 4))
+	assert.Equal(t, 3, third.Players.Online)
+
+	clock.Advance(time.Second)
+	fourth, err := cache.do(context.Background(), "a:1", countingQuery(&calls, 4))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, 4, fourth.Players.Online)
+}
+
+func TestStatusCache_KeysAreSeparate(t *testing.T) {
+	cache, _ := newTestCache(time.Minute)
+	var calls atomic.Int32
+
+	_, _ = cache.do(context.Background(), "a:1", countingQuery(&calls, 1))
+	_, _ = cache.do(context.Background(), "b:1", countingQuery(&calls, 1))
+
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestStatusCache_CachesFailuresBriefly(t *testing.T) {
+	cache, clock := newTestCache(time.Minute)
+	var calls atomic.Int32
+	failing := func(context.Context) (*ServerStatus, error) {
+		calls.Add(1)
+		return &ServerStatus{Online: false}, &StatusError{Kind: ErrorKindRefused, Err: errors.New("refused")}
+	}
+
+	for i := 0; i < 2; i++ {
+		status, err := cache.do(context.Background(), "a:1", failing)
+		assert.False(t, status.Online)
+		assert.Equal(t, ErrorKindRefused, ErrorKindOf(err))
+	}
+	assert.Equal(t, int32(1), calls.Load())
+
+	clock.Advance(failureCacheTTL)
+	_, _ = cache.do(context.Background(), "a:1", failing)
+	assert.Equal(t, int32(2), calls.Load(), "failures expire long before the cache TTL")
+}
+
+func TestStatusCache_DisabledStillCoalesces(t *testing.T) {
+	cache, _ := newTestCache(0)
+	var calls atomic.Int32
+	release := make(chan struct{})
+	slow := func(context.Context) (*ServerStatus, error) {
+		calls.Add(1)
+		<-release
+		return &ServerStatus{Online: true}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status, err := cache.do(context.Background(), "a:1", slow)
+			assert.NoError(t, err)
+			assert.True(t, status.Online)
+		}()
+	}
+
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond) // let the other callers join
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+
+	// Nothing is kept without a TTL
+	_, _ = cache.do(context.Background(), "a:1", countingQuery(&calls, 0))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestStatusCache_CanceledCallerDoesNotFailOthers(t *testing.T) {
+	cache, _ := newTestCache(time.Minute)
+	release := make(chan struct{})
+	slow := func(ctx context.Context) (*ServerStatus, error) {
+		select {
+		case <-release:
+			return &ServerStatus{Online: true}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error, 1)
+	go func() {
+		_, err := cache.do(ctx, "a:1", slow)
+		canceled <- err
+	}()
+
+	waiting := make(chan *ServerStatus, 1)
+	go func() {
+		status, _ := cache.do(context.Background(), "a:1", slow)
+		waiting <- status
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-canceled, context.Canceled)
+
+	close(release)
+	assert.True(t, (<-waiting).Online)
+}
+
+func TestStatusCache_PurgesExpiredEntries(t *testing.T) {
+	cache, clock := newTestCache(time.Minute)
+	var calls atomic.Int32
+
+	_, _ = cache.do(context.Background(), "a:1", countingQuery(&calls, 0))
+	clock.Advance(2 * time.Minute)
+	_, _ = cache.do(context.Background(), "b:1", countingQuery(&calls, 0))
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	assert.NotContains(t, cache.entries, "a:1")
+	assert.Contains(t, cache.entries, "b:1")
+}
+
+func TestCacheKey(t *testing.T) {
+	assert.Equal(t, "mc.example.com:25565", cacheKey("mc.example.com", 25565))
+	assert.Equal(t, "[::1]:19132", cacheKey("::1", 19132))
+}
@@ -2,100 +2,192 @@ package minecraft
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dreamscached/minequery/v2"
 	"github.com/rs/zerolog/log"
 )
 
+// DefaultJavaPort is the default Java Edition server port.
+// Java queries given port 0 use it after following the SRV record of the host, like the game client
+// does for addresses typed without a port.
+const DefaultJavaPort = 25565
+
 // Client is a Minecraft server status client.
 type Client struct {
-	timeout time.Duration
+	timeout  time.Duration
+	resolver Resolver
+
+	// families remembers the ping format each Java server answered
+	families *serverMemo[ProtocolFamily]
+
+	// protocols remembers the protocol version each Java server reported
+	protocols *serverMemo[int]
+}
+
+// ClientOption configures optional Client settings.
+type ClientOption func(*Client)
+
+// WithResolver sets the resolver used for SRV lookups.
+func WithResolver(resolver Resolver) ClientOption {
+	return func(c *Client) {
+		c.resolver = resolver
+	}
 }
 
 // NewClient creates a new Minecraft client with the specified timeout.
-func NewClient(timeout time.Duration) *Client {
-	return &Client{
-		timeout: timeout,
+func NewClient(timeout time.Duration, opts ...ClientOption) *Client {
+	c := &Client{
+		timeout:   timeout,
+		resolver:  net.DefaultResolver,
+		families:  newServerMemo[ProtocolFamily](),
+		protocols: newServerMemo[int](),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // GetStatus queries the Minecraft server and returns its status.
+// When the server cannot be queried, an offline status is returned together with a *StatusError.
 func (c *Client) GetStatus(ctx context.Context, host string, port int) (*ServerStatus, error) {
-	log.Debug().Str("host", host).Int("port", port).Dur("timeout", c.timeout).Msg("starting minecraft server query")
+	return c.GetStatusWithProtocol(ctx, host, port, AutoProtocolVersion)
+}
 
+// GetStatusWithProtocol is GetStatus advertising the given protocol version in the handshake.
+// With AutoProtocolVersion, the protocol the server reported last time is advertised.
+func (c *Client) GetStatusWithProtocol(ctx context.Context, host string, port, protocol int) (*ServerStatus, error) {
+	status, err := c.getStatus(ctx, host, port, protocol)
+	status.CheckedAt = time.Now()
+	return status, err
+}
+
+func (c *Client) getStatus(ctx context.Context, host string, port, protocol int) (*ServerStatus, error) {
+	log.Debug().
+		Str("host", host).
+		Int("port", port).
+		Int("protocol", protocol).
+		Dur("timeout", c.timeout).
+		Msg("starting minecraft server query")
+
+	// SRV records are resolved here instead of by minequery so the resolver can be injected
+	// and the resolved target reported back.
+	srvStart := time.Now()
+	target, resolved := c.resolveSRV(ctx, host, port)
+	srvTime := time.Since(srvStart)
+
+	// minequery is only used for the legacy ping formats
 	pinger := minequery.NewPinger(
 		minequery.WithTimeout(c.timeout),
-		minequery.WithProtocolVersion17(minequery.Ping17ProtocolVersion119),
+		minequery.WithPreferSRVRecord(false),
 	)
 
-	// Create a channel for the result
+	status, err := c.pingFamilies(ctx, pinger, target.Host, target.Port, protocol)
+	if err != nil {
+		if ctx.Err() != nil {
+			log.Warn().Str("host", host).Int("port", port).Msg("minecraft query canceled")
+			return &ServerStatus{Online: false}, ctx.Err()
+		}
+		log.Warn().Err(err).Str("host", host).Int("port", port).Msg("minecraft server ping failed")
+		return &ServerStatus{Online: false}, err
+	}
+
+	if resolved {
+		status.ResolvedHost = target.Host
+		status.ResolvedPort = target.Port
+		status.DNSTime += srvTime
+	}
+	log.Debug().
+		Str("host", host).
+		Int("port", port).
+		Bool("online", status.Online).
+		Str("version", status.Version).
+		Str("family", string(status.Family)).
+		Dur("latency", status.Latency).
+		Int("players", status.Players.Online).
+		Msg("minecraft server query successful")
+	return status, nil
+}
+
+// runPing runs a blocking minequery ping, giving up when the context is done.
+func runPing(ctx context.Context, ping func() (*ServerStatus, error)) (*ServerStatus, error) {
 	type result struct {
-		status *minequery.Status17
+		status *ServerStatus
 		err    error
 	}
 	resultCh := make(chan result, 1)
 
 	go func() {
-		status, err := pinger.Ping17(host, port)
+		status, err := ping()
 		resultCh <- result{status: status, err: err}
 	}()
 
 	select {
 	case <-ctx.Done():
-		log.Warn().Str("host", host).Int("port", port).Msg("minecraft query canceled")
-		return &ServerStatus{Online: false}, ctx.Err()
+		return nil, ctx.Err()
 	case res := <-resultCh:
-		if res.err != nil {
-			log.Warn().Err(res.err).Str("host", host).Int("port", port).Msg("minecraft server ping failed")
-			return &ServerStatus{Online: false}, nil
-		}
-
-		status := c.convertStatus(res.status)
-		log.Debug().
-			Str("host", host).
-			Int("port", port).
-			Bool("online", status.Online).
-			Str("version", status.Version).
-			Int("players", status.Players.Online).
-			Msg("minecraft server query successful")
-		return status, nil
+		return res.status, res.err
 	}
 }
 
-// convertStatus converts minequery status to our internal status format.
-func (c *Client) convertStatus(status *minequery.Status17) *ServerStatus {
+// convertStatus converts a status response to our internal status format.
+func (c *Client) convertStatus(status *slpResponse) *ServerStatus {
 	if status == nil {
 		return &ServerStatus{Online: false}
 	}
 
+	motd := ParseChatComponent(status.Description)
 	serverStatus := &ServerStatus{
 		Online:      true,
-		Version:     status.VersionName,
-		Protocol:    status.ProtocolVersion,
-		Description: status.Description.String(),
+		Family:      ProtocolModern,
+		Version:     status.Version.Name,
+		Protocol:    status.Version.Protocol,
+		Description: motd.String(),
+		MOTD:        motd,
 		Players: PlayersInfo{
-			Online: status.OnlinePlayers,
-			Max:    status.MaxPlayers,
-			Sample: make([]Player, 0, len(status.SamplePlayers)),
+			Online: status.Players.Online,
+			Max:    status.Players.Max,
+			Sample: make([]Player, 0, len(status.Players.Sample)),
 		},
 	}
 
-	for _, p := range status.SamplePlayers {
+	for _, p := range status.Players.Sample {
 		serverStatus.Players.Sample = append(serverStatus.Players.Sample, Player{
-			Name: p.Nickname,
-			UUID: p.UUID.String(),
+			Name: p.Name,
+			UUID: p.ID,
 		})
 	}
 
+	favicon, err := decodeFavicon(status.Favicon)
+	if err != nil {
+		log.Debug().Err(err).Msg("ignoring server favicon")
+	}
+	serverStatus.Favicon = favicon
+
+	mods, err := parseMods(status.ModInfo, status.ForgeData)
+	if err != nil {
+		log.Debug().Err(err).Msg("ignoring server mod list")
+	}
+	serverStatus.Mods = mods
+
 	return serverStatus
 }
 
 // Ping checks if the server is reachable.
+// An unreachable server is reported as false with a nil error.
 func (c *Client) Ping(ctx context.Context, host string, port int) (bool, error) {
 	log.Debug().Str("host", host).Int("port", port).Msg("pinging minecraft server")
 	status, err := c.GetStatus(ctx, host, port)
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		log.Debug().Err(err).Str("host", host).Int("port", port).Msg("ping result: unreachable")
+		return false, nil
+	}
 	if err != nil {
 		log.Warn().Err(err).Str("host", host).Int("port", port).Msg("ping failed")
 		return false, err
@@ -105,45 +197,48 @@ func (c *Client) Ping(ctx context.Context, host string, port int) (bool, error)
 	return status.Online, nil
 }
 
-// FormatAddress formats host and port into a connection string.
+// FormatAddress formats host and port into a connection string, bracketing IPv6 literals when a port follows.
+// Port 0 stands for the default Java port.
 func FormatAddress(host string, port int) string {
-	if port == 25565 {
+	if port == 0 || port == DefaultJavaPort {
 		return host
 	}
-	return fmt.Sprintf("%s:%d", host, port)
+	return net.JoinHostPort(host, strconv.Itoa(port))
 }
 
-// ParseAddress parses a connection string into host and port.
+// ParseAddress parses a connection string into host and port, using the Java Edition default port.
 func ParseAddress(address string) (host string, port int, err error) {
-	log.Debug().Str("address", address).Msg("parsing minecraft address")
+	host, port, _, err = ParseAddressWithDefault(address, DefaultJavaPort)
+	return host, port, err
+}
 
-	port = 25565 // Default port
+// ParseAddressWithDefault parses a connection string into host and port,
+// using defaultPort when the address has no port; explicit reports whether it had one.
+// IPv6 literals are accepted bare or bracketed, as in [2001:db8::1]:25565,
+// and international host names are converted to punycode.
+// Invalid addresses are reported as an *AddressError.
+func ParseAddressWithDefault(address string, defaultPort int) (host string, port int, explicit bool, err error) {
+	log.Debug().Str("address", address).Msg("parsing minecraft address")
 
-	// Check if port is specified
-	var portStr string
-	for i := len(address) - 1; i >= 0; i-- {
-		if address[i] == ':' {
-			host = address[:i]
-			portStr = address[i+1:]
-			break
-		}
+	address = strings.TrimSpace(address)
+	host, portStr, hasPort, err := splitHostPort(address)
+	if err != nil {
+		return "", 0, false, &AddressError{Address: address, Err: err}
 	}
 
-	if host == "" {
-		host = address
-		log.Debug().Str("host", host).Int("port", port).Msg("address parsed (default port)")
-		return host, port, nil
+	port = defaultPort
+	if hasPort {
+		if port, err = parsePort(portStr); err != nil {
+			log.Debug().Str("address", address).Str("port_str", portStr).Msg("invalid port")
+			return "", 0, false, &AddressError{Address: address, Err: err}
+		}
 	}
 
-	// Parse port
-	if portStr != "" {
-		_, err = fmt.Sscanf(portStr, "%d", &port)
-		if err != nil {
-			log.Error().Err(err).Str("address", address).Str("port_str", portStr).Msg("failed to parse port")
-			return "", 0, fmt.Errorf("invalid port: %s", portStr)
-		}
+	if host, err = normalizeHost(host); err != nil {
+		log.Debug().Str("address", address).Msg("invalid host")
+		return "", 0, false, &AddressError{Address: address, Err: err}
 	}
 
-	log.Debug().Str("host", host).Int("port", port).Msg("address parsed")
-	return host, port, nil
+	log.Debug().Str("host", host).Int("port", port).Bool("explicit", hasPort).Msg("address parsed")
+	return host, port, hasPort, nil
 }
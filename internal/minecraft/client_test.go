@@ -1,9 +1,14 @@
 package minecraft
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ykhdr/mss-bot/internal/minecraft/mctest"
 )
 
 func TestParseAddress_HostOnly(t *testing.T) {
@@ -95,3 +100,85 @@ func TestServerStatus_Fields(t *testing.T) {
 	assert.Len(t, status.Players.Sample, 2)
 	assert.Equal(t, "Player1", status.Players.Sample[0].Name)
 }
+
+func TestClient_GetStatus_FakeServer(t *testing.T) {
+	server := mctest.NewServer(mctest.WithStatus(mctest.Status{
+		Version:    "Paper 1.20.4",
+		Protocol:   765,
+		MOTD:       "§aHello",
+		MaxPlayers: 20,
+		Players:    []string{"Steve", "Alex"},
+	}))
+	defer server.Close()
+
+	status, err := NewClient(2*time.Second).GetStatus(context.Background(), server.Host, server.Port)
+	require.NoError(t, err)
+
+	assert.True(t, status.Online)
+	assert.Equal(t, ProtocolModern, status.Family)
+	assert.Equal(t, "Paper 1.20.4", status.Version)
+	assert.Equal(t, "Hello", status.Description)
+	assert.Equal(t, 2, status.Players.Online)
+	require.Len(t, status.Players.Sample, 2)
+	assert.Equal(t, mctest.OfflineUUID("Steve"), status.Players.Sample[0].UUID)
+	assert.False(t, status.CheckedAt.IsZero())
+}
+
+func TestClient_GetStatus_FakeServerFailures(t *testing.T) {
+	tests := []struct {
+		failure mctest.Failure
+		kind    ErrorKind
+	}{
+		{mctest.FailHang, ErrorKindTimeout},
+		{mctest.FailClose, ErrorKindReset},
+		{mctest.FailGarbage, ErrorKindProtocol},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			server := mctest.NewServer()
+			defer server.Close()
+			server.SetResponse(mctest.Response{Failure: tt.failure})
+
+			status, err := NewClient(200*time.Millisecond).GetStatus(context.Background(), server.Host, server.Port)
+			require.Error(t, err)
+			assert.False(t, status.Online)
+			assert.Equal(t, tt.kind, ErrorKindOf(err))
+		})
+	}
+}
+
+func TestClient_GetStatus_FakeServerRecovers(t *testing.T) {
+	server := mctest.NewServer()
+	defer server.Close()
+	server.Script(mctest.Response{Failure: mctest.FailClose})
+
+	client := NewClient(time.Second)
+	provider := NewRetryingProvider(JavaClient{Client: client}, RetryPolicy{Attempts: 2, Backoff: time.Millisecond})
+
+	status, err := provider.Status(context.Background(), StatusRequest{Host: server.Host, Port: server.Port})
+	require.NoError(t, err)
+	assert.True(t, status.Online)
+}
+
+func TestClient_GetStatus_FakeLegacyServer(t *testing.T) {
+	server := mctest.NewServer(mctest.WithLegacy(), mctest.WithStatus(mctest.Status{
+		Version:    "1.6.4",
+		Protocol:   78,
+		MOTD:       "Old times",
+		MaxPlayers: 10,
+		Players:    []string{"Notch"},
+	}))
+	defer server.Close()
+
+	status, err := NewClient(2*time.Second).GetStatus(context.Background(), server.Host, server.Port)
+	require.NoError(t, err)
+
+	assert.True(t, status.Online)
+	assert.Equal(t, ProtocolLegacy16, status.Family)
+	assert.Equal(t, "1.6.4", status.Version)
+	assert.Equal(t, "Old times", status.Description)
+	assert.Equal(t, 1, status.Players.Online)
+	assert.Equal(t, 10, status.Players.Max)
+	assert.Empty(t, server.Handshakes(), "modern handshakes are dropped")
+}
@@ -0,0 +1,87 @@
+package minecraft
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"syscall"
+)
+
+// ErrorKind classifies why a server could not be queried
+type ErrorKind string
+
+const (
+	// ErrorKindDNS means the host name could not be resolved
+	ErrorKindDNS ErrorKind = "dns"
+	// ErrorKindRefused means nothing accepts connections on the port
+	ErrorKindRefused ErrorKind = "refused"
+	// ErrorKindUnreachable means there is no network route to the host
+	ErrorKindUnreachable ErrorKind = "unreachable"
+	// ErrorKindTimeout means the server did not answer in time
+	ErrorKindTimeout ErrorKind = "timeout"
+	// ErrorKindReset means the connection was reset or closed mid-exchange, typically by a TLS terminator or proxy
+	ErrorKindReset ErrorKind = "reset"
+	// ErrorKindProtocol means the server answered with something that is not a valid status response
+	ErrorKindProtocol ErrorKind = "protocol"
+	// ErrorKindUnknown is any other failure
+	ErrorKindUnknown ErrorKind = "unknown"
+)
+
+// StatusError is returned when a server status query fails
+type StatusError struct {
+	Kind ErrorKind
+	Err  error
+}
+
+func (e *StatusError) Error() string {
+	return string(e.Kind) + ": " + e.Err.Error()
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorKindOf returns the kind of a status query error.
+// Errors that are not a StatusError are classified by their cause.
+func ErrorKindOf(err error) ErrorKind {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Kind
+	}
+	return classifyError(err)
+}
+
+// newStatusError wraps a query failure, classifying it by the given cause.
+func newStatusError(cause, err error) *StatusError {
+	return &StatusError{Kind: classifyError(cause), Err: err}
+}
+
+// classifyError maps a network or parse error to an ErrorKind.
+func classifyError(err error) ErrorKind {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var opErr *net.OpError
+
+	switch {
+	case err == nil, errors.Is(err, context.Canceled):
+		return ErrorKindUnknown
+	case errors.As(err, &dnsErr):
+		return ErrorKindDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ErrorKindRefused
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return ErrorKindUnreachable
+	case errors.Is(err, os.ErrDeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorKindTimeout
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE),
+		errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ErrorKindReset
+	case errors.As(err, &opErr):
+		return ErrorKindUnknown
+	default:
+		// Anything that is not a network error comes from parsing the response
+		return ErrorKindProtocol
+	}
+}
@@ -0,0 +1,63 @@
+package minecraft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		kind ErrorKind
+	}{
+		"dns": {
+			&net.DNSError{Err: "no such host", Name: "mc.example.com", IsNotFound: true},
+			ErrorKindDNS,
+		},
+		"refused": {
+			&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)},
+			ErrorKindRefused,
+		},
+		"unreachable": {
+			&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.EHOSTUNREACH)},
+			ErrorKindUnreachable,
+		},
+		"read timeout": {
+			&net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded},
+			ErrorKindTimeout,
+		},
+		"context deadline": {context.DeadlineExceeded, ErrorKindTimeout},
+		"reset": {
+			&net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)},
+			ErrorKindReset,
+		},
+		"eof":         {fmt.Errorf("could not read response packet: %w", io.EOF), ErrorKindReset},
+		"parse":       {errors.New("could not parse status from response packet: invalid character"), ErrorKindProtocol},
+		"other op":    {&net.OpError{Op: "write", Net: "tcp", Err: errors.New("boom")}, ErrorKindUnknown},
+		"canceled":    {context.Canceled, ErrorKindUnknown},
+		"nil is none": {nil, ErrorKindUnknown},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.kind, classifyError(tt.err))
+		})
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	cause := &net.DNSError{Err: "no such host", Name: "mc.example.com"}
+	err := fmt.Errorf("query failed: %w", newStatusError(cause, cause))
+
+	assert.Equal(t, ErrorKindDNS, ErrorKindOf(err))
+	assert.ErrorIs(t, err, cause)
+	assert.Contains(t, err.Error(), "dns: ")
+}
@@ -0,0 +1,158 @@
+package minecraft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/dreamscached/minequery/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// familyOrder is the order ping formats are tried in, newest first.
+var familyOrder = []ProtocolFamily{ProtocolModern, ProtocolLegacy16, ProtocolLegacy14, ProtocolBeta18}
+
+// pingFamilies pings a Java server with every ping format until one is answered,
+// starting with the format the server answered last time. Failures are returned as *StatusError.
+func (c *Client) pingFamilies(
+	ctx context.Context,
+	pinger *minequery.Pinger,
+	host string,
+	port int,
+	protocol int,
+) (*ServerStatus, error) {
+	key := net.JoinHostPort(host, strconv.Itoa(port))
+	remembered, _ := c.rememberedFamily(key)
+
+	var errs []error
+	for _, family := range c.familyOrder(key) {
+		status, err := c.pingFamily(ctx, pinger, family, host, port, protocol)
+		if err == nil {
+			if family != remembered {
+				log.Info().Str("address", key).Str("family", string(family)).Msg("detected minecraft ping format")
+				c.rememberFamily(key, family)
+			}
+			return status, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		log.Debug().Err(err).Str("address", key).Str("family", string(family)).Msg("minecraft ping format failed")
+		errs = append(errs, fmt.Errorf("%s ping: %w", family, err))
+
+		if endsFallback(err) {
+			break
+		}
+	}
+
+	// The first format tried is the one the server is expected to answer, so its failure
+	// tells the most about what went wrong.
+	return nil, newStatusError(errs[0], errors.Join(errs...))
+}
+
+// pingFamily pings a Java server with a single ping format.
+// Formats before 1.6 do not report the version, so the format itself is used as one.
+// The protocol version is only advertised by the modern format.
+func (c *Client) pingFamily(
+	ctx context.Context,
+	pinger *minequery.Pinger,
+	family ProtocolFamily,
+	host string,
+	port int,
+	protocol int,
+) (*ServerStatus, error) {
+	switch family {
+	case ProtocolLegacy16:
+		return runPing(ctx, func() (*ServerStatus, error) {
+			status, err := pinger.Ping16(host, port)
+			if err != nil {
+				return nil, err
+			}
+			return legacyStatus(family, status.ServerVersion, status.ProtocolVersion, status.MOTD,
+				status.OnlinePlayers, status.MaxPlayers), nil
+		})
+	case ProtocolLegacy14:
+		return runPing(ctx, func() (*ServerStatus, error) {
+			status, err := pinger.Ping14(host, port)
+			if err != nil {
+				return nil, err
+			}
+			return legacyStatus(family, string(family), 0, status.MOTD, status.OnlinePlayers, status.MaxPlayers), nil
+		})
+	case ProtocolBeta18:
+		return runPing(ctx, func() (*ServerStatus, error) {
+			status, err := pinger.PingBeta18(host, port)
+			if err != nil {
+				return nil, err
+			}
+			return legacyStatus(family, string(family), 0, status.MOTD, status.OnlinePlayers, status.MaxPlayers), nil
+		})
+	default:
+		return c.pingSLP(ctx, host, port, protocol)
+	}
+}
+
+// legacyStatus builds a status from a legacy ping response, which carries no player sample.
+func legacyStatus(family ProtocolFamily, version string, protocol int, motd string, online, maxPlayers int) *ServerStatus {
+	text := ParseLegacyText(motd)
+	return &ServerStatus{
+		Online:      true,
+		Family:      family,
+		Version:     version,
+		Protocol:    protocol,
+		Description: text.String(),
+		MOTD:        text,
+		Players: PlayersInfo{
+			Online: online,
+			Max:    maxPlayers,
+		},
+	}
+}
+
+// familyOrder returns the ping formats to try for an address, the remembered one first.
+func (c *Client) familyOrder(key string) []ProtocolFamily {
+	remembered, ok := c.rememberedFamily(key)
+	if !ok {
+		return familyOrder
+	}
+
+	order := make([]ProtocolFamily, 0, len(familyOrder))
+	order = append(order, remembered)
+	for _, family := range familyOrder {
+		if family != remembered {
+			order = append(order, family)
+		}
+	}
+	return order
+}
+
+func (c *Client) rememberedFamily(key string) (ProtocolFamily, bool) {
+	return c.families.get(key)
+}
+
+func (c *Client) rememberFamily(key string, family ProtocolFamily) {
+	c.families.set(key, family)
+}
+
+// isDialError reports whether the connection could not be established at all.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// endsFallback reports whether a failed ping rules out the remaining formats. Older formats cannot help
+// when the address does not resolve, nothing accepts connections or the server does not answer at all;
+// trying them would only multiply the time spent waiting on a dead server.
+func endsFallback(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) || isDialError(err) || isTimeout(err)
+}
+
+// isTimeout reports whether a network operation timed out.
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,169 @@
+package minecraft
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildTestKick builds the FF kick packet legacy servers answer pings with.
+func buildTestKick(message string) []byte {
+	chars := utf16.Encode([]rune(message))
+
+	packet := new(bytes.Buffer)
+	packet.WriteByte(0xff)
+	_ = binary.Write(packet, binary.BigEndian, uint16(len(chars)))
+	_ = binary.Write(packet, binary.BigEndian, chars)
+	return packet.Bytes()
+}
+
+// startLegacyServer runs a TCP server that drops modern handshakes and answers
+// legacy pings with kick, counting accepted connections.
+func startLegacyServer(t *testing.T, kick string) (port int, connections *atomic.Int32) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	connections = new(atomic.Int32)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			connections.Add(1)
+
+			go func(conn net.Conn) {
+				defer conn.Close()
+				buf := make([]byte, 256)
+				n, err := conn.Read(buf)
+				if err != nil || n == 0 || buf[0] != 0xfe {
+					return
+				}
+				_, _ = conn.Write(buildTestKick(kick))
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port, connections
+}
+
+// startSilentServer runs a TCP server that accepts connections and never answers, counting them.
+func startSilentServer(t *testing.T) (port int, connections *atomic.Int32) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	connections = new(atomic.Int32)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			connections.Add(1)
+
+			go func(conn net.Conn) {
+				defer conn.Close()
+				_, _ = io.Copy(io.Discard, conn)
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port, connections
+}
+
+func TestClient_GetStatus_Legacy16Fallback(t *testing.T) {
+	port, connections := startLegacyServer(t, "§1\x0061\x001.5.2\x00Old Modpack\x003\x0020")
+	client := NewClient(2 * time.Second)
+
+	status, err := client.GetStatus(context.Background(), "127.0.0.1", port)
+	require.NoError(t, err)
+
+	assert.True(t, status.Online)
+	assert.Equal(t, ProtocolLegacy16, status.Family)
+	assert.Equal(t, "1.5.2", status.Version)
+	assert.Equal(t, 61, status.Protocol)
+	assert.Equal(t, "Old Modpack", status.Description)
+	assert.Equal(t, 3, status.Players.Online)
+	assert.Equal(t, 20, status.Players.Max)
+	assert.Equal(t, int32(2), connections.Load(), "modern handshake, then 1.6 ping")
+
+	// The detected format is tried first on the next poll
+	_, err = client.GetStatus(context.Background(), "127.0.0.1", port)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), connections.Load())
+}
+
+func TestClient_GetStatus_Legacy14Fallback(t *testing.T) {
+	port, _ := startLegacyServer(t, "Beta Server§1§10")
+	client := NewClient(2 * time.Second)
+
+	status, err := client.GetStatus(context.Background(), "127.0.0.1", port)
+	require.NoError(t, err)
+
+	assert.True(t, status.Online)
+	assert.Equal(t, ProtocolLegacy14, status.Family)
+	assert.Equal(t, string(ProtocolLegacy14), status.Version)
+	assert.Equal(t, "Beta Server", status.Description)
+	assert.Equal(t, 10, status.Players.Max)
+}
+
+func TestClient_GetStatus_NothingListening(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	client := NewClient(2 * time.Second)
+
+	status, err := client.GetStatus(context.Background(), "127.0.0.1", port)
+	assert.False(t, status.Online)
+	assert.Equal(t, ErrorKindRefused, ErrorKindOf(err))
+}
+
+func TestClient_GetStatus_SilentServer(t *testing.T) {
+	port, connections := startSilentServer(t)
+	client := NewClient(200 * time.Millisecond)
+
+	started := time.Now()
+	status, err := client.GetStatus(context.Background(), "127.0.0.1", port)
+	assert.False(t, status.Online)
+	assert.Equal(t, ErrorKindTimeout, ErrorKindOf(err))
+	assert.Equal(t, int32(1), connections.Load(), "legacy formats are not tried after a timeout")
+	assert.Less(t, time.Since(started), time.Second)
+}
+
+func TestEndsFallback(t *testing.T) {
+	assert.True(t, endsFallback(&net.DNSError{Err: "no such host", Name: "mc.example.invalid", IsNotFound: true}))
+	assert.True(t, endsFallback(&net.OpError{Op: "dial", Err: errors.New("connection refused")}))
+	assert.True(t, endsFallback(&net.OpError{Op: "read", Err: os.ErrDeadlineExceeded}))
+	assert.False(t, endsFallback(&net.OpError{Op: "read", Err: io.EOF}), "a dropped handshake may be an old server")
+}
+
+func TestClient_FamilyOrder(t *testing.T) {
+	client := NewClient(time.Second)
+
+	assert.Equal(t, familyOrder, client.familyOrder("mc.example.com:25565"))
+
+	client.rememberFamily("mc.example.com:25565", ProtocolLegacy14)
+	assert.Equal(t,
+		[]ProtocolFamily{ProtocolLegacy14, ProtocolModern, ProtocolLegacy16, ProtocolBeta18},
+		client.familyOrder("mc.example.com:25565"),
+	)
+}
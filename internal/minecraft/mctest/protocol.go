@@ -0,0 +1,143 @@
+package mctest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	// packetHandshake, packetStatus and packetPing are the IDs of the status exchange packets.
+	packetHandshake = 0x00
+	packetStatus    = 0x00
+	packetPing      = 0x01
+	// maxPacketSize bounds the packets the server reads.
+	maxPacketSize = 1 << 16
+
+	// legacyPingPacket starts every pre-1.7 ping.
+	legacyPingPacket = 0xfe
+	// legacyKickPacket carries the answer to a legacy ping.
+	legacyKickPacket = 0xff
+	// legacyPingWait is how long the server waits for the rest of a legacy ping;
+	// Beta 1.8 clients send nothing after the first byte.
+	legacyPingWait = 50 * time.Millisecond
+)
+
+// readPacket reads a length-prefixed packet and returns its body, starting with the packet ID.
+func readPacket(reader *bufio.Reader) ([]byte, error) {
+	length, err := readVarInt(reader)
+	if err != nil {
+		return nil, err
+	}
+	if length <= 0 || length > maxPacketSize {
+		return nil, fmt.Errorf("invalid packet length: %d", length)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// framePacket prefixes a packet body with its length.
+func framePacket(body []byte) []byte {
+	packet := new(bytes.Buffer)
+	writeVarInt(packet, int32(len(body)))
+	packet.Write(body)
+	return packet.Bytes()
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	writeVarInt(buf, int32(len(s)))
+	buf.WriteString(s)
+}
+
+func writeVarInt(buf *bytes.Buffer, value int32) {
+	v := uint32(value)
+	for {
+		if v&^0x7f == 0 {
+			buf.WriteByte(byte(v))
+			return
+		}
+		buf.WriteByte(byte(v&0x7f) | 0x80)
+		v >>= 7
+	}
+}
+
+func readVarInt(reader io.ByteReader) (int32, error) {
+	var value uint32
+	for i := 0; i < 5; i++ {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		value |= uint32(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			return int32(value), nil
+		}
+	}
+	return 0, fmt.Errorf("VarInt is too big")
+}
+
+// handleLegacyPing answers a pre-1.7 ping with a kick packet. Pings of 1.4 and later
+// are followed by 0x01 and get the §1 format; a lone 0xFE is a Beta 1.8 ping.
+func (s *Server) handleLegacyPing(conn net.Conn, reader *bufio.Reader) {
+	if _, err := reader.ReadByte(); err != nil {
+		return
+	}
+
+	// Read whatever else the client sends, so closing the connection does not reset it
+	modern := false
+	_ = conn.SetReadDeadline(time.Now().Add(legacyPingWait))
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			break
+		}
+		modern = modern || b == 0x01
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	response, status := s.nextResponse()
+	if !s.sleep(response.Delay) {
+		return
+	}
+	switch response.Failure {
+	case FailHang:
+		<-s.closed
+		return
+	case FailClose:
+		return
+	case FailGarbage:
+		_, _ = conn.Write([]byte{legacyKickPacket, 0x00})
+		return
+	}
+
+	online, maxPlayers := strconv.Itoa(len(status.Players)), strconv.Itoa(status.MaxPlayers)
+	var message string
+	if modern {
+		message = strings.Join([]string{"§1", strconv.Itoa(status.Protocol), status.Version, status.MOTD, online, maxPlayers}, "\x00")
+	} else {
+		message = strings.Join([]string{status.MOTD, online, maxPlayers}, "§")
+	}
+	_, _ = conn.Write(legacyKick(message))
+}
+
+// legacyKick builds a kick packet carrying message as UTF-16BE.
+func legacyKick(message string) []byte {
+	chars := utf16.Encode([]rune(message))
+
+	packet := new(bytes.Buffer)
+	packet.WriteByte(legacyKickPacket)
+	_ = binary.Write(packet, binary.BigEndian, uint16(len(chars)))
+	_ = binary.Write(packet, binary.BigEndian, chars)
+	return packet.Bytes()
+}
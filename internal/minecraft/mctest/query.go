@@ -0,0 +1,99 @@
+package mctest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+)
+
+const (
+	queryTypeHandshake = 0x09
+	queryTypeStat      = 0x00
+	// queryToken is the challenge token handed out to every client.
+	queryToken = 9513307
+)
+
+// startQuery answers GameSpy4 queries, on the status port when it is free for UDP.
+func (s *Server) startQuery() {
+	conn, err := net.ListenPacket("udp", net.JoinHostPort(s.Host, strconv.Itoa(s.Port)))
+	if err != nil {
+		conn, err = net.ListenPacket("udp", net.JoinHostPort(s.Host, "0"))
+	}
+	if err != nil {
+		panic(fmt.Sprintf("mctest: failed to listen for queries: %v", err))
+	}
+	s.queryConn = conn
+	s.QueryPort = conn.LocalAddr().(*net.UDPAddr).Port
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if reply := s.queryReply(buf[:n]); reply != nil {
+				_, _ = conn.WriteTo(reply, addr)
+			}
+		}
+	}()
+}
+
+// queryReply answers a single query packet, or returns nil to ignore it.
+func (s *Server) queryReply(packet []byte) []byte {
+	if len(packet) < 7 || packet[0] != 0xfe || packet[1] != 0xfd {
+		return nil
+	}
+	sessionID := packet[3:7]
+
+	reply := new(bytes.Buffer)
+	reply.WriteByte(packet[2])
+	reply.Write(sessionID)
+
+	switch packet[2] {
+	case queryTypeHandshake:
+		reply.WriteString(strconv.Itoa(queryToken) + "\x00")
+	case queryTypeStat:
+		if len(packet) < 11 || int32(binary.BigEndian.Uint32(packet[7:11])) != queryToken {
+			return nil
+		}
+		s.mu.Lock()
+		status := s.status
+		s.mu.Unlock()
+		writeFullStat(reply, status, s.Host, s.Port)
+	default:
+		return nil
+	}
+	return reply.Bytes()
+}
+
+// writeFullStat writes the key-value and player sections of a full stat response.
+func writeFullStat(buf *bytes.Buffer, status Status, host string, port int) {
+	buf.WriteString("splitnum\x00\x80\x00")
+	fields := [][2]string{
+		{"hostname", status.MOTD},
+		{"gametype", "SMP"},
+		{"game_id", "MINECRAFT"},
+		{"version", status.Version},
+		{"plugins", status.Plugins},
+		{"map", status.Map},
+		{"numplayers", strconv.Itoa(len(status.Players))},
+		{"maxplayers", strconv.Itoa(status.MaxPlayers)},
+		{"hostport", strconv.Itoa(port)},
+		{"hostip", host},
+	}
+	for _, field := range fields {
+		buf.WriteString(field[0] + "\x00" + field[1] + "\x00")
+	}
+	buf.WriteByte(0x00)
+
+	buf.WriteString("\x01player_\x00\x00")
+	for _, name := range status.Players {
+		buf.WriteString(name + "\x00")
+	}
+	buf.WriteByte(0x00)
+}
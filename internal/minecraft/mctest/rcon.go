@@ -0,0 +1,120 @@
+package mctest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+)
+
+const (
+	rconTypeResponse = 0
+	rconTypeCommand  = 2
+	rconTypeLogin    = 3
+	// rconAuthFailed is the request ID of the answer to a wrong password.
+	rconAuthFailed = -1
+	// rconMaxResponse is the largest body the server puts in one response packet.
+	rconMaxResponse = 4096
+	// rconMaxRequest bounds the packets the server reads.
+	rconMaxRequest = 1460
+)
+
+// startRCON runs the remote console on a random localhost port.
+func (s *Server) startRCON() {
+	listener, err := net.Listen("tcp", net.JoinHostPort(s.Host, "0"))
+	if err != nil {
+		panic(fmt.Sprintf("mctest: failed to listen for RCON: %v", err))
+	}
+	s.rconListener = listener
+	s.RCONPort = listener.Addr().(*net.TCPAddr).Port
+	s.serve(listener, s.handleRCONConn)
+}
+
+// RCONAddr returns the host:port of the remote console.
+func (s *Server) RCONAddr() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.RCONPort))
+}
+
+// handleRCONConn serves a console session like vanilla servers do: commands are only run after a successful login.
+func (s *Server) handleRCONConn(conn net.Conn) {
+	authenticated := false
+	for {
+		id, packetType, body, err := readRCONPacket(conn)
+		if err != nil {
+			return
+		}
+
+		switch {
+		case packetType == rconTypeLogin:
+			authenticated = body == s.rconPassword
+			replyID := id
+			if !authenticated {
+				replyID = rconAuthFailed
+			}
+			if err := writeRCONPacket(conn, replyID, rconTypeCommand, ""); err != nil {
+				return
+			}
+		case packetType == rconTypeCommand && authenticated:
+			output := ""
+			if s.rconHandler != nil {
+				output = s.rconHandler(body)
+			}
+			// Long outputs are split over several packets like vanilla servers do
+			for {
+				chunk := output
+				if len(chunk) > rconMaxResponse {
+					chunk = chunk[:rconMaxResponse]
+				}
+				if err := writeRCONPacket(conn, id, rconTypeResponse, chunk); err != nil {
+					return
+				}
+				output = output[len(chunk):]
+				if output == "" {
+					break
+				}
+			}
+		case !authenticated:
+			if err := writeRCONPacket(conn, rconAuthFailed, rconTypeCommand, ""); err != nil {
+				return
+			}
+		default:
+			// Vanilla servers answer requests of other types instead of dropping them
+			if err := writeRCONPacket(conn, id, rconTypeResponse, fmt.Sprintf("Unknown request %x", packetType)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readRCONPacket reads a packet: its little-endian length, request ID and type,
+// then the body terminated by two zero bytes.
+func readRCONPacket(reader io.Reader) (int32, int32, string, error) {
+	var length int32
+	if err := binary.Read(reader, binary.LittleEndian, &length); err != nil {
+		return 0, 0, "", err
+	}
+	if length < 10 || length > rconMaxRequest {
+		return 0, 0, "", fmt.Errorf("invalid packet length: %d", length)
+	}
+
+	packet := make([]byte, length)
+	if _, err := io.ReadFull(reader, packet); err != nil {
+		return 0, 0, "", err
+	}
+	id := int32(binary.LittleEndian.Uint32(packet[0:4]))
+	packetType := int32(binary.LittleEndian.Uint32(packet[4:8]))
+	return id, packetType, string(bytes.TrimRight(packet[8:], "\x00")), nil
+}
+
+func writeRCONPacket(writer io.Writer, id, packetType int32, body string) error {
+	packet := new(bytes.Buffer)
+	_ = binary.Write(packet, binary.LittleEndian, int32(len(body)+10))
+	_ = binary.Write(packet, binary.LittleEndian, id)
+	_ = binary.Write(packet, binary.LittleEndian, packetType)
+	packet.WriteString(body)
+	packet.Write([]byte{0x00, 0x00})
+	_, err := writer.Write(packet.Bytes())
+	return err
+}
@@ -0,0 +1,437 @@
+// Package mctest provides an in-process fake Minecraft server for tests and demos.
+//
+// The server answers the 1.7+ server list ping on a local TCP port and, when enabled,
+// legacy pings, GameSpy4 full stat queries and RCON. Responses can be scripted per ping,
+// delayed or replaced with failures, so clients can be tested end-to-end without a network.
+package mctest
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Failure makes the server misbehave instead of answering a status ping
+type Failure int
+
+const (
+	// FailNone answers normally
+	FailNone Failure = iota
+	// FailHang accepts the connection but never answers, so clients time out
+	FailHang
+	// FailClose closes the connection without answering, like a crashing proxy
+	FailClose
+	// FailGarbage answers with a packet that is not a status response
+	FailGarbage
+	// FailNoPong answers the status request but not the latency ping
+	FailNoPong
+)
+
+// Status is what the fake server reports about itself
+type Status struct {
+	Version    string // e.g. "1.20.4"
+	Protocol   int
+	MOTD       string // may contain legacy § codes
+	MaxPlayers int
+	Players    []string // online players; their UUIDs are derived from the names like in offline mode
+	Favicon    []byte   // PNG image
+
+	// Full stat query fields
+	Map     string
+	Plugins string // e.g. "Paper on Bukkit 1.20.4: LuckPerms 5.4.102"
+}
+
+// DefaultStatus is reported by servers created without WithStatus
+var DefaultStatus = Status{
+	Version:    "1.20.4",
+	Protocol:   765,
+	MOTD:       "A Minecraft Server",
+	MaxPlayers: 20,
+	Map:        "world",
+}
+
+// Response scripts the answer to a single status ping
+type Response struct {
+	Status    *Status       // nil reports the server's status
+	RawJSON   string        // sent as is instead of the status when set
+	Delay     time.Duration // before the status response
+	PongDelay time.Duration // before the latency pong
+	Failure   Failure
+}
+
+// Handshake is a handshake received by the server
+type Handshake struct {
+	Protocol int
+	Host     string
+	Port     int
+}
+
+// Server is a fake Minecraft server listening on localhost
+type Server struct {
+	// Host and Port are the address status pings are answered on
+	Host string
+	Port int
+	// QueryPort is the UDP port full stat queries are answered on, zero unless WithQuery is given
+	QueryPort int
+	// RCONPort is the TCP port of the remote console, zero unless WithRCON is given
+	RCONPort int
+
+	mu         sync.Mutex
+	status     Status
+	fallback   Response
+	script     []Response
+	handshakes []Handshake
+	pings      int
+
+	legacy       bool
+	query        bool
+	rconEnabled  bool
+	rconPassword string
+	rconHandler  func(command string) string
+
+	listener     net.Listener
+	queryConn    net.PacketConn
+	rconListener net.Listener
+	closed       chan struct{}
+	wg           sync.WaitGroup
+
+	// conns are the open TCP connections, closed together with the server
+	connsMu sync.Mutex
+	conns   map[net.Conn]struct{}
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithStatus sets the status the server reports
+func WithStatus(status Status) Option {
+	return func(s *Server) {
+		s.status = status
+	}
+}
+
+// WithLegacy makes the server behave like a pre-1.7 one: modern handshakes are dropped and
+// only legacy pings are answered, in the 1.4–1.6 or Beta 1.8 format. Other servers answer legacy pings too.
+func WithLegacy() Option {
+	return func(s *Server) {
+		s.legacy = true
+	}
+}
+
+// WithQuery makes the server answer GameSpy4 full stat queries over UDP
+func WithQuery() Option {
+	return func(s *Server) {
+		s.query = true
+	}
+}
+
+// WithRCON starts a remote console accepting password and answering commands with handler
+func WithRCON(password string, handler func(command string) string) Option {
+	return func(s *Server) {
+		s.rconEnabled = true
+		s.rconPassword = password
+		s.rconHandler = handler
+	}
+}
+
+// NewServer starts a fake server on a random localhost port.
+// Like httptest.NewServer, it panics when the server cannot be started.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		status: DefaultStatus,
+		closed: make(chan struct{}),
+		conns:  make(map[net.Conn]struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("mctest: failed to listen: %v", err))
+	}
+	s.listener = listener
+	s.Host = "127.0.0.1"
+	s.Port = listener.Addr().(*net.TCPAddr).Port
+	s.serve(listener, s.handleStatusConn)
+
+	if s.query {
+		s.startQuery()
+	}
+	if s.rconEnabled {
+		s.startRCON()
+	}
+	return s
+}
+
+// Addr returns the host:port the server answers status pings on.
+func (s *Server) Addr() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
+
+// SetStatus changes the status the server reports.
+func (s *Server) SetStatus(status Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+// SetResponse changes how pings are answered once the scripted responses run out.
+func (s *Server) SetResponse(response Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fallback = response
+}
+
+// Script queues responses for the next status pings, one per ping.
+func (s *Server) Script(responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.script = append(s.script, responses...)
+}
+
+// Handshakes returns the handshakes received so far.
+func (s *Server) Handshakes() []Handshake {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Handshake(nil), s.handshakes...)
+}
+
+// Pings returns the number of status requests received so far, legacy ones included.
+func (s *Server) Pings() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pings
+}
+
+// Close stops the server and closes open connections, including hanging ones.
+func (s *Server) Close() {
+	select {
+	case <-s.closed:
+		return
+	default:
+	}
+	close(s.closed)
+
+	s.listener.Close()
+	if s.queryConn != nil {
+		s.queryConn.Close()
+	}
+	if s.rconListener != nil {
+		s.rconListener.Close()
+	}
+
+	s.connsMu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.connsMu.Unlock()
+
+	s.wg.Wait()
+}
+
+// serve accepts connections on listener until the server is closed.
+func (s *Server) serve(listener net.Listener, handle func(net.Conn)) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			s.connsMu.Lock()
+			s.conns[conn] = struct{}{}
+			s.connsMu.Unlock()
+
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				defer func() {
+					s.connsMu.Lock()
+					delete(s.conns, conn)
+					s.connsMu.Unlock()
+					conn.Close()
+				}()
+				handle(conn)
+			}()
+		}
+	}()
+}
+
+// nextResponse takes the next scripted response and counts the ping.
+func (s *Server) nextResponse() (Response, Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pings++
+	response := s.fallback
+	if len(s.script) > 0 {
+		response = s.script[0]
+		s.script = s.script[1:]
+	}
+
+	status := s.status
+	if response.Status != nil {
+		status = *response.Status
+	}
+	return response, status
+}
+
+// sleep waits for d, returning false when the server is closed first.
+func (s *Server) sleep(d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-s.closed:
+		return false
+	}
+}
+
+func (s *Server) handleStatusConn(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	first, err := reader.Peek(1)
+	if err != nil {
+		return
+	}
+	if first[0] == legacyPingPacket {
+		s.handleLegacyPing(conn, reader)
+		return
+	}
+	if s.legacy {
+		return
+	}
+
+	handshake, err := readPacket(reader)
+	if err != nil || len(handshake) == 0 || handshake[0] != packetHandshake {
+		return
+	}
+	if hs, err := parseHandshake(handshake[1:]); err == nil {
+		s.mu.Lock()
+		s.handshakes = append(s.handshakes, hs)
+		s.mu.Unlock()
+	}
+
+	request, err := readPacket(reader)
+	if err != nil || len(request) == 0 || request[0] != packetStatus {
+		return
+	}
+
+	response, status := s.nextResponse()
+	if !s.sleep(response.Delay) {
+		return
+	}
+
+	switch response.Failure {
+	case FailHang:
+		<-s.closed
+		return
+	case FailClose:
+		return
+	case FailGarbage:
+		_, _ = conn.Write([]byte{0x05, 0x7f, 'o', 'o', 'p', 's'})
+		return
+	}
+
+	body := response.RawJSON
+	if body == "" {
+		body = statusJSON(status)
+	}
+	payload := new(bytes.Buffer)
+	payload.WriteByte(packetStatus)
+	writeString(payload, body)
+	if _, err := conn.Write(framePacket(payload.Bytes())); err != nil || response.Failure == FailNoPong {
+		return
+	}
+
+	ping, err := readPacket(reader)
+	if err != nil || len(ping) == 0 || ping[0] != packetPing {
+		return
+	}
+	if !s.sleep(response.PongDelay) {
+		return
+	}
+	_, _ = conn.Write(framePacket(ping))
+}
+
+// parseHandshake parses the handshake payload after the packet ID.
+func parseHandshake(payload []byte) (Handshake, error) {
+	reader := bytes.NewReader(payload)
+	protocol, err := readVarInt(reader)
+	if err != nil {
+		return Handshake{}, err
+	}
+	length, err := readVarInt(reader)
+	if err != nil || length < 0 || int(length) > reader.Len() {
+		return Handshake{}, fmt.Errorf("invalid host length")
+	}
+	host := make([]byte, length)
+	if _, err := io.ReadFull(reader, host); err != nil {
+		return Handshake{}, err
+	}
+	var port [2]byte
+	if _, err := io.ReadFull(reader, port[:]); err != nil {
+		return Handshake{}, err
+	}
+	return Handshake{Protocol: int(protocol), Host: string(host), Port: int(port[0])<<8 | int(port[1])}, nil
+}
+
+// statusJSON builds the status response document.
+func statusJSON(status Status) string {
+	type player struct {
+		Name string `json:"name"`
+		ID   string `json:"id"`
+	}
+	document := struct {
+		Version struct {
+			Name     string `json:"name"`
+			Protocol int    `json:"protocol"`
+		} `json:"version"`
+		Players struct {
+			Max    int      `json:"max"`
+			Online int      `json:"online"`
+			Sample []player `json:"sample,omitempty"`
+		} `json:"players"`
+		Description struct {
+			Text string `json:"text"`
+		} `json:"description"`
+		Favicon string `json:"favicon,omitempty"`
+	}{}
+
+	document.Version.Name = status.Version
+	document.Version.Protocol = status.Protocol
+	document.Players.Max = status.MaxPlayers
+	document.Players.Online = len(status.Players)
+	for _, name := range status.Players {
+		document.Players.Sample = append(document.Players.Sample, player{Name: name, ID: OfflineUUID(name)})
+	}
+	document.Description.Text = status.MOTD
+	if len(status.Favicon) > 0 {
+		document.Favicon = "data:image/png;base64," + base64.StdEncoding.EncodeToString(status.Favicon)
+	}
+
+	data, _ := json.Marshal(document)
+	return string(data)
+}
+
+// OfflineUUID returns the UUID an offline-mode server gives a player name.
+func OfflineUUID(name string) string {
+	sum := md5.Sum([]byte("OfflinePlayer:" + name))
+	sum[6] = sum[6]&0x0f | 0x30 // version 3
+	sum[8] = sum[8]&0x3f | 0x80 // IETF variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
@@ -0,0 +1,155 @@
+package mctest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statusExchange sends a handshake and a status request and returns the status JSON.
+func statusExchange(t *testing.T, s *Server, protocol int32) string {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", s.Addr())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	handshake := new(bytes.Buffer)
+	handshake.WriteByte(packetHandshake)
+	writeVarInt(handshake, protocol)
+	writeString(handshake, s.Host)
+	_ = binary.Write(handshake, binary.BigEndian, uint16(s.Port))
+	writeVarInt(handshake, 1)
+	_, err = conn.Write(append(framePacket(handshake.Bytes()), framePacket([]byte{packetStatus})...))
+	require.NoError(t, err)
+
+	response, err := readPacket(bufio.NewReader(conn))
+	require.NoError(t, err)
+	require.Equal(t, byte(packetStatus), response[0])
+
+	body := bytes.NewReader(response[1:])
+	length, err := readVarInt(body)
+	require.NoError(t, err)
+	document := make([]byte, length)
+	_, err = io.ReadFull(body, document)
+	require.NoError(t, err)
+	return string(document)
+}
+
+func TestServer_Status(t *testing.T) {
+	s := NewServer(WithStatus(Status{Version: "1.8.9", Protocol: 47, MOTD: "Hi", MaxPlayers: 5, Players: []string{"Steve"}}))
+	defer s.Close()
+
+	document := statusExchange(t, s, 47)
+	assert.JSONEq(t, `{
+		"version": {"name": "1.8.9", "protocol": 47},
+		"players": {"max": 5, "online": 1, "sample": [{"name": "Steve", "id": "`+OfflineUUID("Steve")+`"}]},
+		"description": {"text": "Hi"}
+	}`, document)
+
+	assert.Equal(t, []Handshake{{Protocol: 47, Host: "127.0.0.1", Port: s.Port}}, s.Handshakes())
+	assert.Equal(t, 1, s.Pings())
+}
+
+func TestServer_Script(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.Script(Response{RawJSON: `{"first":true}`})
+	assert.Equal(t, `{"first":true}`, statusExchange(t, s, 765))
+	assert.Contains(t, statusExchange(t, s, 765), DefaultStatus.MOTD, "the script runs out")
+}
+
+func TestServer_CloseReleasesHangingConnections(t *testing.T) {
+	s := NewServer()
+	s.SetResponse(Response{Failure: FailHang})
+
+	conn, err := net.Dial("tcp", s.Addr())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write(append(framePacket([]byte{packetHandshake, 0x00}), framePacket([]byte{packetStatus})...))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return s.Pings() == 1 }, time.Second, 5*time.Millisecond)
+
+	s.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, err = conn.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestServer_LegacyPing(t *testing.T) {
+	s := NewServer(WithStatus(Status{Version: "1.6.4", Protocol: 78, MOTD: "Old", MaxPlayers: 10}))
+	defer s.Close()
+
+	ping := func(request []byte) string {
+		conn, err := net.Dial("tcp", s.Addr())
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = conn.Write(request)
+		require.NoError(t, err)
+
+		reply, err := io.ReadAll(conn)
+		require.NoError(t, err)
+		require.Equal(t, byte(legacyKickPacket), reply[0])
+		return string(reply[3:])
+	}
+
+	assert.Equal(t, string(legacyKick("§1\x0078\x001.6.4\x00Old\x000\x0010")[3:]), ping([]byte{0xfe, 0x01}))
+	assert.Equal(t, string(legacyKick("Old§0§10")[3:]), ping([]byte{0xfe}))
+	assert.Equal(t, 2, s.Pings())
+}
+
+func TestServer_Query(t *testing.T) {
+	s := NewServer(WithQuery(), WithStatus(Status{Version: "1.20.4", MOTD: "Q", MaxPlayers: 20, Players: []string{"Alex"}, Map: "world"}))
+	defer s.Close()
+
+	reply := s.queryReply([]byte{0xfe, 0xfd, queryTypeHandshake, 0, 0, 0, 1})
+	assert.Equal(t, "\x09\x00\x00\x00\x019513307\x00", string(reply))
+
+	request := []byte{0xfe, 0xfd, queryTypeStat, 0, 0, 0, 1}
+	request = binary.BigEndian.AppendUint32(request, queryToken)
+	reply = s.queryReply(append(request, 0, 0, 0, 0))
+	require.NotNil(t, reply)
+	assert.Contains(t, string(reply), "numplayers\x001\x00")
+	assert.True(t, bytes.HasSuffix(reply, []byte("\x01player_\x00\x00Alex\x00\x00")))
+
+	assert.Nil(t, s.queryReply(append(request[:7:7], 0, 0, 0, 2)), "wrong challenge token")
+	assert.NotZero(t, s.QueryPort)
+}
+
+func TestServer_RCON(t *testing.T) {
+	s := NewServer(WithRCON("secret", func(command string) string { return "ran " + command }))
+	defer s.Close()
+
+	conn, err := net.Dial("tcp", s.RCONAddr())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, writeRCONPacket(conn, 7, rconTypeLogin, "wrong"))
+	id, _, _, err := readRCONPacket(conn)
+	require.NoError(t, err)
+	assert.Equal(t, int32(rconAuthFailed), id)
+
+	require.NoError(t, writeRCONPacket(conn, 8, rconTypeLogin, "secret"))
+	id, _, _, err = readRCONPacket(conn)
+	require.NoError(t, err)
+	assert.Equal(t, int32(8), id)
+
+	require.NoError(t, writeRCONPacket(conn, 9, rconTypeCommand, "list"))
+	id, packetType, body, err := readRCONPacket(conn)
+	require.NoError(t, err)
+	assert.Equal(t, int32(9), id)
+	assert.Equal(t, int32(rconTypeResponse), packetType)
+	assert.Equal(t, "ran list", body)
+}
+
+func TestOfflineUUID(t *testing.T) {
+	assert.Equal(t, "b50ad385-829d-3141-a216-7e7d7539ba7f", OfflineUUID("Notch"))
+}
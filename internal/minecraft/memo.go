@@ -0,0 +1,83 @@
+package minecraft
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// memoTTL is how long a server not queried since is remembered. Servers watched by chats are
+	// queried far more often, while addresses checked once are forgotten.
+	memoTTL = time.Hour
+	// memoMaxEntries bounds the servers remembered at once; the least recently queried ones are forgotten first.
+	memoMaxEntries = 4096
+)
+
+// serverMemo remembers something learned about each server, keyed by host:port
+type serverMemo[V any] struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]memoEntry[V]
+}
+
+type memoEntry[V any] struct {
+	value  V
+	usedAt time.Time
+}
+
+func newServerMemo[V any]() *serverMemo[V] {
+	return &serverMemo[V]{
+		now:     time.Now,
+		entries: make(map[string]memoEntry[V]),
+	}
+}
+
+// get returns the value remembered for a server and keeps it for another memoTTL.
+func (m *serverMemo[V]) get(key string) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	now := m.now()
+	if !ok || now.Sub(entry.usedAt) >= memoTTL {
+		delete(m.entries, key)
+		var zero V
+		return zero, false
+	}
+
+	entry.usedAt = now
+	m.entries[key] = entry
+	return entry.value, true
+}
+
+// set remembers a value for a server, forgetting expired servers and, when full, the least recently queried one.
+func (m *serverMemo[V]) set(key string, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if _, ok := m.entries[key]; !ok {
+		m.purgeLocked(now)
+	}
+	m.entries[key] = memoEntry[V]{value: value, usedAt: now}
+}
+
+// purgeLocked makes room for a new server; m.mu must be held.
+func (m *serverMemo[V]) purgeLocked(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range m.entries {
+		if now.Sub(entry.usedAt) >= memoTTL {
+			delete(m.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.usedAt.Before(oldest) {
+			oldestKey, oldest = key, entry.usedAt
+		}
+	}
+
+	if len(m.entries) >= memoMaxEntries {
+		delete(m.entries, oldestKey)
+	}
+}
@@ -0,0 +1,51 @@
+package minecraft
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestMemo() (*serverMemo[int], *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	memo := newServerMemo[int]()
+	memo.now = clock.Now
+	return memo, clock
+}
+
+func TestServerMemo_ForgetsUnusedServers(t *testing.T) {
+	memo, clock := newTestMemo()
+	memo.set("watched:25565", 1)
+	memo.set("once:25565", 2)
+
+	clock.Advance(memoTTL - time.Minute)
+	_, ok := memo.get("watched:25565")
+	assert.True(t, ok)
+
+	clock.Advance(time.Minute)
+	value, ok := memo.get("watched:25565")
+	assert.True(t, ok, "queried servers are kept")
+	assert.Equal(t, 1, value)
+	_, ok = memo.get("once:25565")
+	assert.False(t, ok, "servers not queried for memoTTL are forgotten")
+}
+
+func TestServerMemo_Bounded(t *testing.T) {
+	memo, clock := newTestMemo()
+	for i := range memoMaxEntries {
+		memo.set(fmt.Sprintf("server%d:25565", i), i)
+		clock.Advance(time.Millisecond)
+	}
+	memo.get("server0:25565")
+
+	memo.set("new:25565", 1)
+	assert.Len(t, memo.entries, memoMaxEntries)
+	_, ok := memo.get("server1:25565")
+	assert.False(t, ok, "the least recently queried server is forgotten")
+	_, ok = memo.get("server0:25565")
+	assert.True(t, ok)
+	_, ok = memo.get("new:25565")
+	assert.True(t, ok)
+}
@@ -0,0 +1,232 @@
+package minecraft
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ModLoader is the mod loader a modded Java Edition server runs
+type ModLoader string
+
+const (
+	ModLoaderForge    ModLoader = "Forge"
+	ModLoaderNeoForge ModLoader = "NeoForge"
+	ModLoaderFabric   ModLoader = "Fabric"
+)
+
+// serverOnlyMarker starts the version Forge reports for mods that clients do not need.
+const serverOnlyMarker = "OHNOES"
+
+// ModInfo lists the mods a modded server advertises in its status response
+type ModInfo struct {
+	Loader    ModLoader
+	Mods      []Mod
+	Truncated bool // the server left out part of the list to keep the response small
+}
+
+// Mod is a mod installed on a server
+type Mod struct {
+	Name    string // mod ID
+	Version string // empty for server-side only mods
+}
+
+// slpModInfo is the mod list of Forge 1.7–1.12 (FML).
+type slpModInfo struct {
+	Type    string `json:"type"`
+	ModList []struct {
+		ModID   string `json:"modid"`
+		Version string `json:"version"`
+	} `json:"modList"`
+}
+
+// slpForgeData is the mod list of Forge and NeoForge 1.13+. Since 1.18.1 the mods
+// are packed into the binary d string instead of the mods array.
+type slpForgeData struct {
+	Mods []struct {
+		ModID     string `json:"modId"`
+		ModMarker string `json:"modmarker"`
+	} `json:"mods"`
+	D         string `json:"d"`
+	Truncated bool   `json:"truncated"`
+}
+
+// parseMods extracts the mod list from the modinfo or forgeData field of a status response.
+// It returns nil for servers that advertise no mods.
+func parseMods(modInfo, forgeData json.RawMessage) (*ModInfo, error) {
+	if len(forgeData) > 0 && string(forgeData) != "null" {
+		var data slpForgeData
+		if err := json.Unmarshal(forgeData, &data); err != nil {
+			return nil, fmt.Errorf("could not parse forgeData: %w", err)
+		}
+
+		info := &ModInfo{Loader: ModLoaderForge, Truncated: data.Truncated}
+		if data.D != "" {
+			mods, truncated, err := decodeForgeData(data.D)
+			if err != nil {
+				return nil, err
+			}
+			info.Mods = mods
+			info.Truncated = info.Truncated || truncated
+		} else {
+			for _, m := range data.Mods {
+				info.Mods = append(info.Mods, newMod(m.ModID, m.ModMarker))
+			}
+		}
+		for _, m := range info.Mods {
+			if m.Name == "neoforge" {
+				info.Loader = ModLoaderNeoForge
+			}
+		}
+		return info, nil
+	}
+
+	if len(modInfo) > 0 && string(modInfo) != "null" {
+		var data slpModInfo
+		if err := json.Unmarshal(modInfo, &data); err != nil {
+			return nil, fmt.Errorf("could not parse modinfo: %w", err)
+		}
+
+		info := &ModInfo{Loader: modInfoLoader(data.Type)}
+		for _, m := range data.ModList {
+			info.Mods = append(info.Mods, newMod(m.ModID, m.Version))
+		}
+		return info, nil
+	}
+
+	return nil, nil
+}
+
+// modInfoLoader maps the modinfo type to a loader; FML is Forge, unknown types are kept as is.
+func modInfoLoader(modType string) ModLoader {
+	switch strings.ToLower(modType) {
+	case "", "fml":
+		return ModLoaderForge
+	case "neoforge":
+		return ModLoaderNeoForge
+	case "fabric":
+		return ModLoaderFabric
+	default:
+		return ModLoader(modType)
+	}
+}
+
+func newMod(name, version string) Mod {
+	if strings.HasPrefix(version, serverOnlyMarker) {
+		version = ""
+	}
+	return Mod{Name: name, Version: version}
+}
+
+// decodeForgeData decodes the d string of Forge 1.18.1+: a binary mod list packed 15 bits per char,
+// after two chars holding the byte length.
+func decodeForgeData(d string) (mods []Mod, truncated bool, err error) {
+	data, err := unpackForgeData(d)
+	if err != nil {
+		return nil, false, err
+	}
+	reader := bytes.NewReader(data)
+
+	flag, err := reader.ReadByte()
+	if err != nil {
+		return nil, false, fmt.Errorf("could not read truncated flag: %w", err)
+	}
+	truncated = flag != 0
+
+	var count uint16
+	if err := binary.Read(reader, binary.BigEndian, &count); err != nil {
+		return nil, false, fmt.Errorf("could not read mod count: %w", err)
+	}
+
+	mods = make([]Mod, 0, count)
+	for i := 0; i < int(count); i++ {
+		channelsAndFlag, err := readVarInt(reader)
+		if err != nil {
+			return nil, false, fmt.Errorf("could not read mod %d: %w", i, err)
+		}
+		channels := int(uint32(channelsAndFlag) >> 1)
+		serverOnly := channelsAndFlag&1 != 0
+
+		mod := Mod{}
+		if mod.Name, err = readForgeString(reader); err != nil {
+			return nil, false, fmt.Errorf("could not read mod %d: %w", i, err)
+		}
+		if !serverOnly {
+			if mod.Version, err = readForgeString(reader); err != nil {
+				return nil, false, fmt.Errorf("could not read mod %s version: %w", mod.Name, err)
+			}
+		}
+
+		// Network channels: name, version and whether clients need the channel
+		for j := 0; j < channels; j++ {
+			for k := 0; k < 2; k++ {
+				if _, err := readForgeString(reader); err != nil {
+					return nil, false, fmt.Errorf("could not read mod %s channels: %w", mod.Name, err)
+				}
+			}
+			if _, err := reader.ReadByte(); err != nil {
+				return nil, false, fmt.Errorf("could not read mod %s channels: %w", mod.Name, err)
+			}
+		}
+
+		mods = append(mods, mod)
+	}
+
+	return mods, truncated, nil
+}
+
+// unpackForgeData turns the 15-bit chars of the d string back into bytes.
+func unpackForgeData(d string) ([]byte, error) {
+	chars := []rune(d)
+	if len(chars) < 2 {
+		return nil, fmt.Errorf("forgeData is too short")
+	}
+
+	size := int(chars[0]&0x7fff) | int(chars[1]&0x7fff)<<15
+	if size > slpMaxPacketSize {
+		return nil, fmt.Errorf("invalid forgeData size: %d", size)
+	}
+
+	data := make([]byte, 0, size)
+	var buffer uint32
+	bits := 0
+	for _, c := range chars[2:] {
+		for bits >= 8 && len(data) < size {
+			data = append(data, byte(buffer))
+			buffer >>= 8
+			bits -= 8
+		}
+		buffer |= uint32(c&0x7fff) << bits
+		bits += 15
+	}
+	for bits > 0 && len(data) < size {
+		data = append(data, byte(buffer))
+		buffer >>= 8
+		bits -= 8
+	}
+
+	if len(data) < size {
+		return nil, fmt.Errorf("forgeData is truncated: %d of %d bytes", len(data), size)
+	}
+	return data, nil
+}
+
+// readForgeString reads a VarInt length-prefixed UTF-8 string.
+func readForgeString(reader *bytes.Reader) (string, error) {
+	length, err := readVarInt(reader)
+	if err != nil {
+		return "", err
+	}
+	if length < 0 || int(length) > reader.Len() {
+		return "", fmt.Errorf("invalid string length: %d", length)
+	}
+
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(reader, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,126 @@
+package minecraft
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// packForgeData packs bytes 15 bits per char, the way Forge builds the forgeData d string.
+func packForgeData(data []byte) string {
+	var sb strings.Builder
+	sb.WriteRune(rune(len(data) & 0x7fff))
+	sb.WriteRune(rune(len(data) >> 15 & 0x7fff))
+
+	var buffer uint32
+	bits := 0
+	for _, b := range data {
+		if bits >= 15 {
+			sb.WriteRune(rune(buffer & 0x7fff))
+			buffer >>= 15
+			bits -= 15
+		}
+		buffer |= uint32(b) << bits
+		bits += 8
+	}
+	if bits > 0 {
+		sb.WriteRune(rune(buffer & 0x7fff))
+	}
+	return sb.String()
+}
+
+// buildForgeData serializes a mod list like Forge 1.18.1+; mods without a version are server-side only.
+func buildForgeData(truncated bool, mods []Mod) []byte {
+	buf := new(bytes.Buffer)
+	if truncated {
+		buf.WriteByte(1)
+	} else {
+		buf.WriteByte(0)
+	}
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(mods)))
+	for _, mod := range mods {
+		flag := int32(1 << 1) // one channel
+		if mod.Version == "" {
+			flag |= 1
+		}
+		writeVarInt(buf, flag)
+		writeString(buf, mod.Name)
+		if mod.Version != "" {
+			writeString(buf, mod.Version)
+		}
+		writeString(buf, "main")
+		writeString(buf, "1")
+		buf.WriteByte(1)
+	}
+	writeVarInt(buf, 0) // channels of no mod
+	return buf.Bytes()
+}
+
+func TestParseMods_Vanilla(t *testing.T) {
+	mods, err := parseMods(nil, nil)
+
+	require.NoError(t, err)
+	assert.Nil(t, mods)
+}
+
+func TestParseMods_ModInfo(t *testing.T) {
+	raw := `{"type": "FML", "modList": [{"modid": "mcp", "version": "9.42"}, {"modid": "jei", "version": "4.16.1.302"}]}`
+
+	mods, err := parseMods(json.RawMessage(raw), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, &ModInfo{
+		Loader: ModLoaderForge,
+		Mods:   []Mod{{Name: "mcp", Version: "9.42"}, {Name: "jei", Version: "4.16.1.302"}},
+	}, mods)
+}
+
+func TestParseMods_ForgeDataList(t *testing.T) {
+	raw := `{
+		"channels": [],
+		"mods": [{"modId": "forge", "modmarker": "36.2.39"}, {"modId": "spark", "modmarker": "OHNOES😱😱😱"}],
+		"fmlNetworkVersion": 2
+	}`
+
+	mods, err := parseMods(nil, json.RawMessage(raw))
+	require.NoError(t, err)
+
+	assert.Equal(t, ModLoaderForge, mods.Loader)
+	assert.Equal(t, []Mod{{Name: "forge", Version: "36.2.39"}, {Name: "spark"}}, mods.Mods)
+}
+
+func TestParseMods_ForgeDataPacked(t *testing.T) {
+	want := []Mod{
+		{Name: "minecraft", Version: "1.20.1"},
+		{Name: "neoforge", Version: "47.1.106"},
+		{Name: "create", Version: "0.5.1.f"},
+		{Name: "spark"},
+	}
+	d, err := json.Marshal(packForgeData(buildForgeData(true, want)))
+	require.NoError(t, err)
+
+	mods, err := parseMods(nil, json.RawMessage(`{"fmlNetworkVersion": 3, "truncated": false, "d": `+string(d)+`}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, ModLoaderNeoForge, mods.Loader)
+	assert.True(t, mods.Truncated)
+	assert.Equal(t, want, mods.Mods)
+}
+
+func TestDecodeForgeData_Truncated(t *testing.T) {
+	chars := []rune(packForgeData(buildForgeData(false, []Mod{{Name: "create", Version: "0.5.1.f"}})))
+
+	_, _, err := decodeForgeData(string(chars[:len(chars)-3]))
+	assert.Error(t, err)
+}
+
+func TestModInfoLoader(t *testing.T) {
+	assert.Equal(t, ModLoaderForge, modInfoLoader("FML"))
+	assert.Equal(t, ModLoaderFabric, modInfoLoader("fabric"))
+	assert.Equal(t, ModLoader("Quilt"), modInfoLoader("Quilt"))
+}
@@ -0,0 +1,286 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+// legacyCodePrefix starts a legacy formatting code such as §a or §l.
+const legacyCodePrefix = '§'
+
+// namedColors maps chat component color names to their RGB values.
+var namedColors = map[string]string{
+	"black":        "#000000",
+	"dark_blue":    "#0000AA",
+	"dark_green":   "#00AA00",
+	"dark_aqua":    "#00AAAA",
+	"dark_red":     "#AA0000",
+	"dark_purple":  "#AA00AA",
+	"gold":         "#FFAA00",
+	"gray":         "#AAAAAA",
+	"dark_gray":    "#555555",
+	"blue":         "#5555FF",
+	"green":        "#55FF55",
+	"aqua":         "#55FFFF",
+	"red":          "#FF5555",
+	"light_purple": "#FF55FF",
+	"yellow":       "#FFFF55",
+	"white":        "#FFFFFF",
+}
+
+// legacyColors maps legacy color codes §0–§f to their RGB values.
+var legacyColors = map[rune]string{
+	'0': "#000000", '1': "#0000AA", '2': "#00AA00", '3': "#00AAAA",
+	'4': "#AA0000", '5': "#AA00AA", '6': "#FFAA00", '7': "#AAAAAA",
+	'8': "#555555", '9': "#5555FF", 'a': "#55FF55", 'b': "#55FFFF",
+	'c': "#FF5555", 'd': "#FF55FF", 'e': "#FFFF55", 'f': "#FFFFFF",
+}
+
+// TextStyle is the formatting of a piece of chat text
+type TextStyle struct {
+	Color         string // "#RRGGBB", empty for the default color
+	Bold          bool
+	Italic        bool
+	Underlined    bool
+	Strikethrough bool
+	Obfuscated    bool
+}
+
+// TextSpan is a run of chat text in a single style
+type TextSpan struct {
+	Text  string
+	Style TextStyle
+}
+
+// ChatText is formatted chat text such as a server MOTD
+type ChatText []TextSpan
+
+// String returns the text without formatting.
+func (t ChatText) String() string {
+	var sb strings.Builder
+	for _, span := range t {
+		sb.WriteString(span.Text)
+	}
+	return sb.String()
+}
+
+// Lines splits the text at line breaks.
+func (t ChatText) Lines() []ChatText {
+	lines := []ChatText{nil}
+	for _, span := range t {
+		parts := strings.Split(span.Text, "\n")
+		for i, part := range parts {
+			if i > 0 {
+				lines = append(lines, nil)
+			}
+			if part != "" {
+				lines[len(lines)-1] = append(lines[len(lines)-1], TextSpan{Text: part, Style: span.Style})
+			}
+		}
+	}
+	return lines
+}
+
+// TrimSpace removes leading and trailing whitespace, which servers use to center their MOTD.
+func (t ChatText) TrimSpace() ChatText {
+	trimmed := append(ChatText(nil), t...)
+	for len(trimmed) > 0 {
+		trimmed[0].Text = strings.TrimLeftFunc(trimmed[0].Text, unicode.IsSpace)
+		if trimmed[0].Text != "" {
+			break
+		}
+		trimmed = trimmed[1:]
+	}
+	for len(trimmed) > 0 {
+		last := len(trimmed) - 1
+		trimmed[last].Text = strings.TrimRightFunc(trimmed[last].Text, unicode.IsSpace)
+		if trimmed[last].Text != "" {
+			break
+		}
+		trimmed = trimmed[:last]
+	}
+	return trimmed
+}
+
+// ParseLegacyText parses text with legacy § formatting codes, including the §x§R§R§G§G§B§B hex colors
+// of BungeeCord. Unknown codes are dropped.
+func ParseLegacyText(s string) ChatText {
+	var parser chatParser
+	parser.legacy(s, TextStyle{})
+	return parser.text
+}
+
+// ParseChatComponent parses a JSON chat component: a string, an object with text, style and extra
+// children, or an array whose first element is the parent of the rest. Strings may contain legacy codes.
+// Invalid JSON yields empty text.
+func ParseChatComponent(raw json.RawMessage) ChatText {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var component any
+	if err := json.Unmarshal(raw, &component); err != nil {
+		return nil
+	}
+
+	var parser chatParser
+	parser.component(component, TextStyle{})
+	return parser.text
+}
+
+// chatParser accumulates spans, merging neighbours of the same style.
+type chatParser struct {
+	text ChatText
+}
+
+func (p *chatParser) add(text string, style TextStyle) {
+	if text == "" {
+		return
+	}
+	if last := len(p.text) - 1; last >= 0 && p.text[last].Style == style {
+		p.text[last].Text += text
+		return
+	}
+	p.text = append(p.text, TextSpan{Text: text, Style: style})
+}
+
+func (p *chatParser) component(component any, style TextStyle) {
+	switch c := component.(type) {
+	case string:
+		p.legacy(c, style)
+	case []any:
+		if len(c) == 0 {
+			return
+		}
+		// The first element is the parent: the others inherit its style
+		parent, ok := c[0].(map[string]any)
+		if !ok {
+			for _, child := range c {
+				p.component(child, style)
+			}
+			return
+		}
+		extra, _ := parent["extra"].([]any)
+		merged := make(map[string]any, len(parent))
+		for k, v := range parent {
+			merged[k] = v
+		}
+		merged["extra"] = append(append([]any(nil), extra...), c[1:]...)
+		p.component(merged, style)
+	case map[string]any:
+		style = applyComponentStyle(c, style)
+		if text, ok := c["text"]; ok {
+			p.component(text, style)
+		} else if translate, ok := c["translate"].(string); ok {
+			p.legacy(translate, style)
+		}
+		if extra, ok := c["extra"].([]any); ok {
+			for _, child := range extra {
+				p.component(child, style)
+			}
+		}
+	}
+}
+
+// legacy parses a string with § codes on top of the style inherited from its component.
+func (p *chatParser) legacy(s string, base TextStyle) {
+	style := base
+	runes := []rune(s)
+
+	var sb strings.Builder
+	flush := func() {
+		p.add(sb.String(), style)
+		sb.Reset()
+	}
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != legacyCodePrefix || i+1 == len(runes) {
+			sb.WriteRune(runes[i])
+			continue
+		}
+
+		code := unicode.ToLower(runes[i+1])
+		i++
+		flush()
+
+		if color, ok := legacyColors[code]; ok {
+			// Colors reset the formatting, like in the game
+			style = TextStyle{Color: color}
+			continue
+		}
+
+		switch code {
+		case 'x':
+			if color, ok := parseLegacyHex(runes[i+1:]); ok {
+				style = TextStyle{Color: color}
+				i += 12
+			}
+		case 'k':
+			style.Obfuscated = true
+		case 'l':
+			style.Bold = true
+		case 'm':
+			style.Strikethrough = true
+		case 'n':
+			style.Underlined = true
+		case 'o':
+			style.Italic = true
+		case 'r':
+			style = base
+		}
+	}
+	flush()
+}
+
+// parseLegacyHex parses the six §-prefixed digits following §x.
+func parseLegacyHex(runes []rune) (string, bool) {
+	if len(runes) < 12 {
+		return "", false
+	}
+
+	color := []rune{'#'}
+	for i := 0; i < 12; i += 2 {
+		digit := unicode.ToUpper(runes[i+1])
+		if runes[i] != legacyCodePrefix || !strings.ContainsRune("0123456789ABCDEF", digit) {
+			return "", false
+		}
+		color = append(color, digit)
+	}
+	return string(color), true
+}
+
+// applyComponentStyle returns the inherited style overridden by the fields a component sets.
+func applyComponentStyle(c map[string]any, style TextStyle) TextStyle {
+	if color, ok := c["color"].(string); ok {
+		style.Color = parseColor(color)
+	}
+	for field, target := range map[string]*bool{
+		"bold":          &style.Bold,
+		"italic":        &style.Italic,
+		"underlined":    &style.Underlined,
+		"strikethrough": &style.Strikethrough,
+		"obfuscated":    &style.Obfuscated,
+	} {
+		if v, ok := c[field].(bool); ok {
+			*target = v
+		}
+	}
+	return style
+}
+
+// parseColor converts a named or #RRGGBB color; unknown colors fall back to the default one.
+func parseColor(color string) string {
+	if hex, ok := namedColors[color]; ok {
+		return hex
+	}
+	if len(color) == 7 && color[0] == '#' {
+		for _, r := range color[1:] {
+			if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+				return ""
+			}
+		}
+		return strings.ToUpper(color)
+	}
+	return ""
+}
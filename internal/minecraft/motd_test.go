@@ -0,0 +1,102 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChatComponent_PlainText(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"string", `"A Minecraft Server"`, "A Minecraft Server"},
+		{"object", `{"text": "Hello"}`, "Hello"},
+		{"extra", `{"text": "", "extra": ["a", {"text": "b", "extra": [{"text": "c"}]}]}`, "abc"},
+		{"array", `[{"text": "x"}, "y"]`, "xy"},
+		{"translate", `{"translate": "menu.server"}`, "menu.server"},
+		{"legacy codes", `"§aGreen §lbold"`, "Green bold"},
+		{"empty", ``, ""},
+		{"invalid", `{`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseChatComponent(json.RawMessage(tt.raw)).String())
+		})
+	}
+}
+
+func TestParseChatComponent_Styles(t *testing.T) {
+	raw := `{
+		"text": "",
+		"color": "gold",
+		"bold": true,
+		"extra": [
+			{"text": "Hyp"},
+			{"text": "ixel", "bold": false, "color": "#ff00AA"},
+			{"text": " §mold§r new", "italic": true}
+		]
+	}`
+
+	text := ParseChatComponent(json.RawMessage(raw))
+
+	assert.Equal(t, ChatText{
+		{Text: "Hyp", Style: TextStyle{Color: "#FFAA00", Bold: true}},
+		{Text: "ixel", Style: TextStyle{Color: "#FF00AA"}},
+		{Text: " ", Style: TextStyle{Color: "#FFAA00", Bold: true, Italic: true}},
+		{Text: "old", Style: TextStyle{Color: "#FFAA00", Bold: true, Italic: true, Strikethrough: true}},
+		{Text: " new", Style: TextStyle{Color: "#FFAA00", Bold: true, Italic: true}},
+	}, text)
+}
+
+func TestParseChatComponent_ArrayInheritsFirstElement(t *testing.T) {
+	text := ParseChatComponent(json.RawMessage(`[{"text": "a", "underlined": true}, {"text": "b"}]`))
+
+	assert.Equal(t, ChatText{{Text: "ab", Style: TextStyle{Underlined: true}}}, text)
+}
+
+func TestParseLegacyText(t *testing.T) {
+	text := ParseLegacyText("§6§lGold §r§7plain §ktrick§Ox")
+
+	assert.Equal(t, ChatText{
+		{Text: "Gold ", Style: TextStyle{Color: "#FFAA00", Bold: true}},
+		{Text: "plain ", Style: TextStyle{Color: "#AAAAAA"}},
+		{Text: "trick", Style: TextStyle{Color: "#AAAAAA", Obfuscated: true}},
+		{Text: "x", Style: TextStyle{Color: "#AAAAAA", Obfuscated: true, Italic: true}},
+	}, text)
+}
+
+func TestParseLegacyText_ColorResetsFormatting(t *testing.T) {
+	text := ParseLegacyText("§lbold§cred")
+
+	assert.Equal(t, ChatText{
+		{Text: "bold", Style: TextStyle{Bold: true}},
+		{Text: "red", Style: TextStyle{Color: "#FF5555"}},
+	}, text)
+}
+
+func TestParseLegacyText_HexColor(t *testing.T) {
+	text := ParseLegacyText("§x§f§f§0§0§a§aPink §x§zstays pink")
+
+	// Malformed hex colors and unknown codes are dropped without changing the style
+	assert.Equal(t, ChatText{{Text: "Pink stays pink", Style: TextStyle{Color: "#FF00AA"}}}, text)
+}
+
+func TestParseLegacyText_TrailingPrefix(t *testing.T) {
+	assert.Equal(t, "50§", ParseLegacyText("50§").String())
+}
+
+func TestChatText_LinesAndTrimSpace(t *testing.T) {
+	text := ParseLegacyText("   §aFirst line  \n  §lSecond§r   ")
+
+	lines := text.Lines()
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "First line", lines[0].TrimSpace().String())
+	assert.Equal(t, ChatText{{Text: "Second", Style: TextStyle{Color: "#55FF55", Bold: true}}}, lines[1].TrimSpace())
+
+	assert.Empty(t, ParseLegacyText("   ").TrimSpace())
+}
@@ -0,0 +1,172 @@
+package minecraft
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// StatusProvider queries the status of a server.
+// When the server cannot be queried, an offline status is returned together with the error.
+type StatusProvider interface {
+	Status(ctx context.Context, req StatusRequest) (*ServerStatus, error)
+}
+
+// StatusRequest describes the server a status is requested from
+type StatusRequest struct {
+	Host string
+	Port int
+	// ProtocolVersion is advertised in the Java Edition handshake; AutoProtocolVersion uses
+	// the protocol the server reported last time
+	ProtocolVersion int
+}
+
+// key identifies the request in caches; a server may answer differently per protocol version.
+func (r StatusRequest) key() string {
+	key := cacheKey(r.Host, r.Port)
+	if r.ProtocolVersion != AutoProtocolVersion {
+		key += "/" + strconv.Itoa(r.ProtocolVersion)
+	}
+	return key
+}
+
+// FullStatQuerier requests the GameSpy4 full stat of Java Edition servers
+type FullStatQuerier interface {
+	QueryFull(ctx context.Context, host string, port int) (*QueryStatus, error)
+}
+
+// JavaClient provides the status of Java Edition servers through the server list ping
+type JavaClient struct {
+	*Client
+}
+
+// Status implements StatusProvider.
+func (c JavaClient) Status(ctx context.Context, req StatusRequest) (*ServerStatus, error) {
+	return c.GetStatusWithProtocol(ctx, req.Host, req.Port, req.ProtocolVersion)
+}
+
+// BedrockClient provides the status of Bedrock Edition servers through the RakNet unconnected ping
+type BedrockClient struct {
+	*Client
+}
+
+// Status implements StatusProvider. Bedrock servers have no handshake, so the protocol version is unused.
+func (c BedrockClient) Status(ctx context.Context, req StatusRequest) (*ServerStatus, error) {
+	return c.GetBedrockStatus(ctx, req.Host, req.Port)
+}
+
+// CachedProvider reuses recent results of another provider and coalesces concurrent queries
+// of the same server, so chats watching one server share a single ping.
+type CachedProvider struct {
+	next  StatusProvider
+	cache *statusCache
+}
+
+// NewCachedProvider wraps next with a cache keeping results for ttl; zero disables caching,
+// but concurrent queries are still coalesced.
+func NewCachedProvider(next StatusProvider, ttl time.Duration) *CachedProvider {
+	return &CachedProvider{next: next, cache: newStatusCache(ttl)}
+}
+
+// Status implements StatusProvider. Every caller gets its own copy of the status.
+func (p *CachedProvider) Status(ctx context.Context, req StatusRequest) (*ServerStatus, error) {
+	return p.cache.do(ctx, req.key(), func(ctx context.Context) (*ServerStatus, error) {
+		return p.next.Status(ctx, req)
+	})
+}
+
+// RetryPolicy controls how status queries failed with a transient error are retried
+type RetryPolicy struct {
+	Attempts   int           // queries made in total; one or less disables retries
+	Backoff    time.Duration // delay before the first retry, doubled before every next one
+	MaxBackoff time.Duration // upper bound of the delay; zero leaves it unbounded
+	Deadline   time.Duration // time all attempts together may take; zero leaves it to the caller's context
+}
+
+// delay returns the randomized delay before the given retry, counting from one.
+// Equal jitter is used: half of the backoff is kept, the other half is random,
+// so servers that failed together are not retried in lockstep.
+func (p RetryPolicy) delay(retry int, jitter func(time.Duration) time.Duration) time.Duration {
+	backoff := p.Backoff
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || backoff < p.MaxBackoff); i++ {
+		backoff *= 2
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return backoff/2 + jitter(backoff-backoff/2)
+}
+
+// RetryingProvider repeats queries of another provider that failed with a transient error.
+type RetryingProvider struct {
+	next   StatusProvider
+	policy RetryPolicy
+	jitter func(time.Duration) time.Duration // random duration in [0, d]
+}
+
+// NewRetryingProvider wraps next so that timeouts and reset connections are retried
+// with exponential backoff as the policy allows.
+func NewRetryingProvider(next StatusProvider, policy RetryPolicy) *RetryingProvider {
+	return &RetryingProvider{
+		next:   next,
+		policy: policy,
+		jitter: func(d time.Duration) time.Duration { return rand.N(d + 1) },
+	}
+}
+
+// Status implements StatusProvider. A retry is only made when its delay ends before the deadline.
+func (p *RetryingProvider) Status(ctx context.Context, req StatusRequest) (*ServerStatus, error) {
+	if p.policy.Deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.policy.Deadline)
+		defer cancel()
+	}
+
+	status, err := p.next.Status(ctx, req)
+	for retry := 1; retry < p.policy.Attempts && ctx.Err() == nil && isTransient(err); retry++ {
+		delay := p.policy.delay(retry, p.jitter)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			log.Debug().Err(err).Str("host", req.Host).Int("port", req.Port).Msg("no time left to retry minecraft server query")
+			break
+		}
+
+		log.Debug().
+			Err(err).
+			Str("host", req.Host).
+			Int("port", req.Port).
+			Int("retry", retry).
+			Dur("delay", delay).
+			Msg("retrying minecraft server query")
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return status, err
+		case <-timer.C:
+		}
+
+		status, err = p.next.Status(ctx, req)
+	}
+	return status, err
+}
+
+// isTransient reports whether a query failed in a way a second attempt may not.
+func isTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	switch ErrorKindOf(err) {
+	case ErrorKindTimeout, ErrorKindReset:
+		return true
+	default:
+		return false
+	}
+}
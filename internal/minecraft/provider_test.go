@@ -0,0 +1,162 @@
+package minecraft
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedProvider fails with the given errors in turn and then reports the server online.
+type scriptedProvider struct {
+	errs  []error
+	calls atomic.Int32
+}
+
+func (p *scriptedProvider) Status(ctx context.Context, req StatusRequest) (*ServerStatus, error) {
+	call := int(p.calls.Add(1)) - 1
+	if call < len(p.errs) {
+		return &ServerStatus{Online: false}, p.errs[call]
+	}
+	return &ServerStatus{Online: true}, nil
+}
+
+var testRequest = StatusRequest{Host: "127.0.0.1", Port: 25565}
+
+func timeoutError() error {
+	return &StatusError{Kind: ErrorKindTimeout, Err: os.ErrDeadlineExceeded}
+}
+
+func TestRetryingProvider_RetriesTransientErrors(t *testing.T) {
+	next := &scriptedProvider{errs: []error{timeoutError(), &StatusError{Kind: ErrorKindReset, Err: errors.New("reset")}}}
+	provider := NewRetryingProvider(next, RetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+
+	status, err := provider.Status(context.Background(), testRequest)
+	require.NoError(t, err)
+	assert.True(t, status.Online)
+	assert.Equal(t, int32(3), next.calls.Load())
+}
+
+func TestRetryingProvider_GivesUpAfterAttempts(t *testing.T) {
+	next := &scriptedProvider{errs: []error{timeoutError(), timeoutError(), timeoutError()}}
+	provider := NewRetryingProvider(next, RetryPolicy{Attempts: 2, Backoff: time.Millisecond})
+
+	status, err := provider.Status(context.Background(), testRequest)
+	assert.Equal(t, ErrorKindTimeout, ErrorKindOf(err))
+	assert.False(t, status.Online)
+	assert.Equal(t, int32(2), next.calls.Load())
+}
+
+func TestRetryingProvider_DoesNotRetryPermanentErrors(t *testing.T) {
+	for _, err := range []error{
+		&StatusError{Kind: ErrorKindRefused, Err: errors.New("refused")},
+		&StatusError{Kind: ErrorKindDNS, Err: errors.New("no such host")},
+		context.DeadlineExceeded,
+	} {
+		next := &scriptedProvider{errs: []error{err}}
+		provider := NewRetryingProvider(next, RetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+
+		_, got := provider.Status(context.Background(), testRequest)
+		assert.ErrorIs(t, got, err)
+		assert.Equal(t, int32(1), next.calls.Load(), "error %v", err)
+	}
+}
+
+func TestRetryingProvider_StopsWhenContextDone(t *testing.T) {
+	next := &scriptedProvider{errs: []error{timeoutError()}}
+	provider := NewRetryingProvider(next, RetryPolicy{Attempts: 3, Backoff: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := provider.Status(ctx, testRequest)
+	assert.Equal(t, ErrorKindTimeout, ErrorKindOf(err))
+	assert.Equal(t, int32(1), next.calls.Load())
+}
+
+func TestRetryingProvider_KeepsWithinDeadline(t *testing.T) {
+	next := &scriptedProvider{errs: []error{timeoutError(), timeoutError(), timeoutError()}}
+	provider := NewRetryingProvider(next, RetryPolicy{Attempts: 5, Backoff: 20 * time.Millisecond, Deadline: 50 * time.Millisecond})
+	provider.jitter = func(d time.Duration) time.Duration { return d }
+
+	start := time.Now()
+	_, err := provider.Status(context.Background(), testRequest)
+	assert.Equal(t, ErrorKindTimeout, ErrorKindOf(err))
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+	// Retries after 20ms and 40ms would end past the deadline, so only the first one is made
+	assert.Equal(t, int32(2), next.calls.Load())
+}
+
+func TestRetryingProvider_RetriesClientTimeouts(t *testing.T) {
+	port, connections := startSilentServer(t)
+
+	// The default policy scaled down: the deadline leaves every attempt its timeout and the longest backoff
+	timeout, maxBackoff := 200*time.Millisecond, 200*time.Millisecond
+	provider := NewRetryingProvider(JavaClient{Client: NewClient(timeout)}, RetryPolicy{
+		Attempts:   3,
+		Backoff:    20 * time.Millisecond,
+		MaxBackoff: maxBackoff,
+		Deadline:   3 * (timeout + maxBackoff),
+	})
+
+	status, err := provider.Status(context.Background(), StatusRequest{Host: "127.0.0.1", Port: port})
+	assert.False(t, status.Online)
+	assert.Equal(t, ErrorKindTimeout, ErrorKindOf(err))
+	assert.Equal(t, int32(3), connections.Load(), "every attempt is made")
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	none := func(time.Duration) time.Duration { return 0 }
+	full := func(d time.Duration) time.Duration { return d }
+
+	assert.Equal(t, 50*time.Millisecond, policy.delay(1, none))
+	assert.Equal(t, 100*time.Millisecond, policy.delay(1, full))
+	assert.Equal(t, 200*time.Millisecond, policy.delay(2, full))
+	assert.Equal(t, 400*time.Millisecond, policy.delay(3, full))
+	assert.Equal(t, time.Second, policy.delay(5, full))
+	assert.Equal(t, 500*time.Millisecond, policy.delay(40, none))
+
+	assert.Equal(t, time.Duration(0), RetryPolicy{}.delay(1, full))
+}
+
+func TestCachedProvider_ReusesResults(t *testing.T) {
+	next := &scriptedProvider{}
+	provider := NewCachedProvider(next, time.Minute)
+
+	first, err := provider.Status(context.Background(), testRequest)
+	require.NoError(t, err)
+	second, err := provider.Status(context.Background(), testRequest)
+	require.NoError(t, err)
+	assert.Equal(t, first.CheckedAt, second.CheckedAt)
+	assert.Equal(t, int32(1), next.calls.Load())
+
+	_, _ = provider.Status(context.Background(), StatusRequest{Host: "127.0.0.1", Port: 25566})
+	assert.Equal(t, int32(2), next.calls.Load())
+
+	// An explicit protocol version may get a different answer
+	_, _ = provider.Status(context.Background(), StatusRequest{Host: "127.0.0.1", Port: 25565, ProtocolVersion: 47})
+	assert.Equal(t, int32(3), next.calls.Load())
+}
+
+func TestClientProviders(t *testing.T) {
+	port := startModernServer(t, `{"version":{"name":"1.21","protocol":767},"players":{"max":20,"online":1}}`, true, 0)
+	client := NewClient(2 * time.Second)
+
+	var provider StatusProvider = JavaClient{Client: client}
+	status, err := provider.Status(context.Background(), StatusRequest{Host: "127.0.0.1", Port: port})
+	require.NoError(t, err)
+	assert.True(t, status.Online)
+	assert.False(t, status.CheckedAt.IsZero())
+
+	provider = BedrockClient{Client: client}
+	status, err = provider.Status(context.Background(), StatusRequest{Host: "127.0.0.1", Port: port})
+	assert.Error(t, err)
+	assert.False(t, status.Online)
+	assert.False(t, status.CheckedAt.IsZero())
+}
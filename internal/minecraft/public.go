@@ -0,0 +1,77 @@
+package minecraft
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// ErrPrivateAddress is returned, wrapped in an *AddressError, for addresses that lead into a private network
+var ErrPrivateAddress = errors.New("address is not public")
+
+// nonPublicRanges are IPv4 ranges that are global unicast by the address format but not reachable from the internet
+var nonPublicRanges = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+}
+
+// ResolvePublic resolves an address typed by a user the way a query would, following the SRV record
+// of Java servers given without a port when srv is set, and returns the target with its host replaced
+// by an IP address. Port 0 stands for DefaultJavaPort.
+// Addresses resolving to loopback, private, link-local or other non-public IPs are refused with ErrPrivateAddress,
+// so that users cannot make the bot probe its own network. Querying the returned IP keeps DNS from
+// pointing the query elsewhere after the check.
+func ResolvePublic(ctx context.Context, resolver Resolver, host string, port int, srv bool) (Target, error) {
+	target := Target{Host: host, Port: port}
+	if port == 0 {
+		target.Port = DefaultJavaPort
+	}
+	if srv && port == 0 && net.ParseIP(host) == nil {
+		_, records, err := resolver.LookupSRV(ctx, "minecraft", "tcp", host)
+		if err == nil && len(records) > 0 && strings.TrimSuffix(records[0].Target, ".") != "" {
+			target = Target{Host: strings.TrimSuffix(records[0].Target, "."), Port: int(records[0].Port)}
+		}
+	}
+
+	addrs := []string{target.Host}
+	if net.ParseIP(target.Host) == nil {
+		var err error
+		addrs, err = resolver.LookupHost(ctx, target.Host)
+		if err != nil {
+			return Target{}, newStatusError(err, err)
+		}
+	}
+
+	var public netip.Addr
+	for _, addr := range addrs {
+		ip, err := netip.ParseAddr(addr)
+		if err != nil || !isPublic(ip) {
+			return Target{}, &AddressError{Address: FormatAddress(host, port), Err: ErrPrivateAddress}
+		}
+		if !public.IsValid() {
+			public = ip
+		}
+	}
+	if !public.IsValid() {
+		err := &net.DNSError{Err: "no addresses", Name: target.Host, IsNotFound: true}
+		return Target{}, newStatusError(err, err)
+	}
+
+	return Target{Host: public.String(), Port: target.Port}, nil
+}
+
+// isPublic reports whether an IP address is reachable from the internet
+func isPublic(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicRanges {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
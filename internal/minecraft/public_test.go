@@ -0,0 +1,79 @@
+package minecraft
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hostsResolver resolves host names from a fixed table
+type hostsResolver struct {
+	srv   map[string]*net.SRV
+	hosts map[string][]string
+}
+
+func (r *hostsResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	if record, ok := r.srv[name]; ok {
+		return "", []*net.SRV{record}, nil
+	}
+	return "", nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r *hostsResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if addrs, ok := r.hosts[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestResolvePublic(t *testing.T) {
+	resolver := &hostsResolver{
+		srv: map[string]*net.SRV{
+			"play.example.net":   {Target: "mc1.example.net.", Port: 25570},
+			"sneaky.example.net": {Target: "internal.example.net.", Port: 25565},
+		},
+		hosts: map[string][]string{
+			"mc1.example.net":      {"203.0.113.7"},
+			"public.example.net":   {"2001:db8::1", "198.51.100.2"},
+			"internal.example.net": {"10.0.0.5"},
+			"mixed.example.net":    {"198.51.100.2", "127.0.0.1"},
+		},
+	}
+	ctx := context.Background()
+
+	target, err := ResolvePublic(ctx, resolver, "play.example.net", 0, true)
+	require.NoError(t, err)
+	assert.Equal(t, Target{Host: "203.0.113.7", Port: 25570}, target, "SRV targets are followed")
+
+	_, err = ResolvePublic(ctx, resolver, "play.example.net", DefaultJavaPort, true)
+	assert.Equal(t, ErrorKindDNS, ErrorKindOf(err), "explicit ports are not redirected")
+
+	target, err = ResolvePublic(ctx, resolver, "public.example.net", 19132, false)
+	require.NoError(t, err)
+	assert.Equal(t, Target{Host: "2001:db8::1", Port: 19132}, target)
+
+	target, err = ResolvePublic(ctx, resolver, "8.8.8.8", DefaultJavaPort, true)
+	require.NoError(t, err)
+	assert.Equal(t, Target{Host: "8.8.8.8", Port: DefaultJavaPort}, target)
+
+	for _, host := range []string{"127.0.0.1", "::1", "192.168.1.10", "169.254.169.254", "internal.example.net",
+		"sneaky.example.net", "mixed.example.net", "0.0.0.0", "100.64.1.1", "::ffff:10.0.0.1"} {
+		_, err := ResolvePublic(ctx, resolver, host, 0, true)
+		assert.ErrorIs(t, err, ErrPrivateAddress, host)
+	}
+
+	_, err = ResolvePublic(ctx, resolver, "missing.example.net", DefaultJavaPort, true)
+	assert.Equal(t, ErrorKindDNS, ErrorKindOf(err))
+}
+
+func TestIsPublic(t *testing.T) {
+	assert.True(t, isPublic(netip.MustParseAddr("1.1.1.1")))
+	assert.False(t, isPublic(netip.MustParseAddr("172.16.0.1")))
+	assert.False(t, isPublic(netip.MustParseAddr("fe80::1")))
+	assert.False(t, isPublic(netip.MustParseAddr("fd00::1")))
+	assert.False(t, isPublic(netip.MustParseAddr("224.0.0.1")))
+}
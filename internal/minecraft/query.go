@@ -0,0 +1,299 @@
+package minecraft
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// queryTypeHandshake is the GameSpy4 packet type of a challenge token request.
+	queryTypeHandshake byte = 0x09
+	// queryTypeStat is the GameSpy4 packet type of a stat request.
+	queryTypeStat byte = 0x00
+	// querySessionIDMask keeps only the bits Minecraft servers echo back in the session ID.
+	querySessionIDMask = 0x0f0f0f0f
+	// queryMaxPacketSize is large enough for a full stat response of a crowded server.
+	queryMaxPacketSize = 64 * 1024
+)
+
+var (
+	// queryMagic prefixes every GameSpy4 request.
+	queryMagic = []byte{0xfe, 0xfd}
+	// queryKVPadding precedes the key-value section of a full stat response.
+	queryKVPadding = []byte("splitnum\x00\x80\x00")
+	// queryPlayersPadding precedes the player section of a full stat response.
+	queryPlayersPadding = []byte("\x01player_\x00\x00")
+)
+
+// QueryStatus is a GameSpy4 full stat response of a Java Edition server with enable-query=true.
+type QueryStatus struct {
+	MOTD          string
+	GameType      string
+	Version       string
+	Software      string // e.g. "Paper on Bukkit 1.20.4-R0.1-SNAPSHOT", empty for vanilla
+	Plugins       []Plugin
+	Map           string
+	OnlinePlayers int
+	MaxPlayers    int
+	Players       []string
+}
+
+// Plugin is a server plugin reported by the full stat query.
+type Plugin struct {
+	Name    string
+	Version string
+}
+
+// QueryFull requests the full stat of a server over the GameSpy4 query protocol.
+// Unlike GetStatus, failures are returned so callers can fall back to the status ping.
+func (c *Client) QueryFull(ctx context.Context, host string, port int) (*QueryStatus, error) {
+	log.Debug().Str("host", host).Int("port", port).Dur("timeout", c.timeout).Msg("starting minecraft full stat query")
+
+	status, err := c.queryFull(ctx, host, port)
+	if err != nil {
+		log.Warn().Err(err).Str("host", host).Int("port", port).Msg("minecraft full stat query failed")
+		return nil, err
+	}
+
+	log.Debug().
+		Str("host", host).
+		Int("port", port).
+		Str("map", status.Map).
+		Int("players", len(status.Players)).
+		Msg("minecraft full stat query successful")
+	return status, nil
+}
+
+func (c *Client) queryFull(ctx context.Context, host string, port int) (*QueryStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, fmt.Errorf("could not dial: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, fmt.Errorf("could not set deadline: %w", err)
+		}
+	}
+
+	sessionID := rand.Int31() & querySessionIDMask //nolint:gosec // session IDs need no crypto randomness
+	buf := make([]byte, queryMaxPacketSize)
+
+	if _, err := conn.Write(buildQueryHandshake(sessionID)); err != nil {
+		return nil, fmt.Errorf("could not write handshake packet: %w", err)
+	}
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, fmt.Errorf("could not read handshake response: %w", err)
+	}
+	token, err := parseQueryHandshake(buf[:n], sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.Write(buildFullStatRequest(sessionID, token)); err != nil {
+		return nil, fmt.Errorf("could not write full stat request: %w", err)
+	}
+	n, err = conn.Read(buf)
+	if err != nil {
+		return nil, fmt.Errorf("could not read full stat response: %w", err)
+	}
+
+	return parseFullStat(buf[:n], sessionID)
+}
+
+// buildQueryHandshake builds a challenge token request.
+func buildQueryHandshake(sessionID int32) []byte {
+	packet := bytes.NewBuffer(make([]byte, 0, len(queryMagic)+1+4))
+	packet.Write(queryMagic)
+	packet.WriteByte(queryTypeHandshake)
+	_ = binary.Write(packet, binary.BigEndian, sessionID)
+	return packet.Bytes()
+}
+
+// buildFullStatRequest builds a stat request; the four trailing zero bytes ask for the full stat.
+func buildFullStatRequest(sessionID, token int32) []byte {
+	packet := bytes.NewBuffer(make([]byte, 0, len(queryMagic)+1+4+4+4))
+	packet.Write(queryMagic)
+	packet.WriteByte(queryTypeStat)
+	_ = binary.Write(packet, binary.BigEndian, sessionID)
+	_ = binary.Write(packet, binary.BigEndian, token)
+	packet.Write([]byte{0x00, 0x00, 0x00, 0x00})
+	return packet.Bytes()
+}
+
+// checkQueryHeader validates the type and session ID every response starts with
+// and returns the payload.
+func checkQueryHeader(packet []byte, packetType byte, sessionID int32) ([]byte, error) {
+	if len(packet) < 5 {
+		return nil, fmt.Errorf("query response too short: %d bytes", len(packet))
+	}
+	if packet[0] != packetType {
+		return nil, fmt.Errorf("unexpected query packet type: 0x%02x", packet[0])
+	}
+	if got := int32(binary.BigEndian.Uint32(packet[1:5])); got != sessionID {
+		return nil, fmt.Errorf("session ID mismatch: want %d, got %d", sessionID, got)
+	}
+	return packet[5:], nil
+}
+
+// parseQueryHandshake extracts the challenge token, which servers send as a decimal string.
+func parseQueryHandshake(packet []byte, sessionID int32) (int32, error) {
+	payload, err := checkQueryHeader(packet, queryTypeHandshake, sessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	tokenStr, _, err := readCString(payload)
+	if err != nil {
+		return 0, err
+	}
+	token, err := strconv.ParseInt(tokenStr, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid challenge token %q: %w", tokenStr, err)
+	}
+	return int32(token), nil
+}
+
+// parseFullStat parses a full stat response: a padded key-value section
+// followed by a padded list of player names, both terminated by an empty string.
+func parseFullStat(packet []byte, sessionID int32) (*QueryStatus, error) {
+	payload, err := checkQueryHeader(packet, queryTypeStat, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(payload, queryKVPadding) {
+		return nil, fmt.Errorf("invalid key-value section padding")
+	}
+	payload = payload[len(queryKVPadding):]
+
+	fields := make(map[string]string)
+	for {
+		var key, value string
+		key, payload, err = readCString(payload)
+		if err != nil {
+			return nil, err
+		}
+		if key == "" {
+			break
+		}
+		value, payload, err = readCString(payload)
+		if err != nil {
+			return nil, err
+		}
+		fields[key] = value
+	}
+
+	// The player section is missing in responses of some proxies.
+	var players []string
+	if bytes.HasPrefix(payload, queryPlayersPadding) {
+		payload = payload[len(queryPlayersPadding):]
+		for len(payload) > 0 {
+			var name string
+			name, payload, err = readCString(payload)
+			if err != nil {
+				return nil, err
+			}
+			if name == "" {
+				break
+			}
+			players = append(players, name)
+		}
+	}
+
+	online, err := strconv.Atoi(fields["numplayers"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid online player count %q: %w", fields["numplayers"], err)
+	}
+	maxPlayers, err := strconv.Atoi(fields["maxplayers"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid max player count %q: %w", fields["maxplayers"], err)
+	}
+
+	software, plugins := parsePlugins(fields["plugins"])
+	return &QueryStatus{
+		MOTD:          fields["hostname"],
+		GameType:      fields["gametype"],
+		Version:       fields["version"],
+		Software:      software,
+		Plugins:       plugins,
+		Map:           fields["map"],
+		OnlinePlayers: online,
+		MaxPlayers:    maxPlayers,
+		Players:       players,
+	}, nil
+}
+
+// parsePlugins parses the plugins field, formatted as "Software: Plugin1 1.0; Plugin2 2.3".
+// Vanilla servers leave it empty.
+func parsePlugins(field string) (string, []Plugin) {
+	software, list, found := strings.Cut(field, ":")
+	software = strings.TrimSpace(software)
+	if !found {
+		return software, nil
+	}
+
+	var plugins []Plugin
+	for _, entry := range strings.Split(list, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, version, _ := strings.Cut(entry, " ")
+		plugins = append(plugins, Plugin{Name: name, Version: strings.TrimSpace(version)})
+	}
+	return software, plugins
+}
+
+// readCString reads a NUL-terminated string and returns it with the remaining data.
+func readCString(data []byte) (string, []byte, error) {
+	i := bytes.IndexByte(data, 0x00)
+	if i < 0 {
+		return "", nil, fmt.Errorf("unterminated string in query response")
+	}
+	return string(data[:i]), data[i+1:], nil
+}
+
+// ApplyQuery merges a full stat query response into the status.
+// The query player list replaces the ping sample, which servers cap at a dozen players;
+// UUIDs from the sample are kept where names match.
+func (s *ServerStatus) ApplyQuery(q *QueryStatus) {
+	s.Query = q
+
+	if !s.Online {
+		s.Online = true
+		s.Version = q.Version
+		s.MOTD = ParseLegacyText(q.MOTD)
+		s.Description = s.MOTD.String()
+	}
+
+	uuids := make(map[string]string, len(s.Players.Sample))
+	for _, p := range s.Players.Sample {
+		uuids[p.Name] = p.UUID
+	}
+
+	sample := make([]Player, 0, len(q.Players))
+	for _, name := range q.Players {
+		sample = append(sample, Player{Name: name, UUID: uuids[name]})
+	}
+
+	s.Players = PlayersInfo{
+		Online: q.OnlinePlayers,
+		Max:    q.MaxPlayers,
+		Sample: sample,
+	}
+}
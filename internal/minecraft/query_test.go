@@ -0,0 +1,229 @@
+package minecraft
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ykhdr/mss-bot/internal/minecraft/mctest"
+)
+
+func buildTestFullStat(sessionID int32, fields [][2]string, players []string) []byte {
+	packet := new(bytes.Buffer)
+	packet.WriteByte(queryTypeStat)
+	_ = binary.Write(packet, binary.BigEndian, sessionID)
+	packet.Write(queryKVPadding)
+	for _, kv := range fields {
+		packet.WriteString(kv[0] + "\x00" + kv[1] + "\x00")
+	}
+	packet.WriteByte(0x00)
+	packet.Write(queryPlayersPadding)
+	for _, name := range players {
+		packet.WriteString(name + "\x00")
+	}
+	packet.WriteByte(0x00)
+	return packet.Bytes()
+}
+
+var testStatFields = [][2]string{
+	{"hostname", "A Minecraft Server"},
+	{"gametype", "SMP"},
+	{"game_id", "MINECRAFT"},
+	{"version", "1.20.4"},
+	{"plugins", "Paper on Bukkit 1.20.4: LuckPerms 5.4.102; EssentialsX 2.20.1"},
+	{"map", "world"},
+	{"numplayers", "2"},
+	{"maxplayers", "20"},
+	{"hostport", "25565"},
+	{"hostip", "127.0.0.1"},
+}
+
+func TestBuildQueryRequests(t *testing.T) {
+	handshake := buildQueryHandshake(0x01020304)
+	assert.Equal(t, []byte{0xfe, 0xfd, 0x09, 0x01, 0x02, 0x03, 0x04}, handshake)
+
+	request := buildFullStatRequest(0x01020304, 9513307)
+	require.Len(t, request, 15)
+	assert.Equal(t, queryTypeStat, request[2])
+	assert.Equal(t, int32(9513307), int32(binary.BigEndian.Uint32(request[7:11])))
+	assert.Equal(t, []byte{0, 0, 0, 0}, request[11:])
+}
+
+func TestParseQueryHandshake(t *testing.T) {
+	packet := append([]byte{queryTypeHandshake, 0x00, 0x00, 0x00, 0x01}, []byte("9513307\x00")...)
+
+	token, err := parseQueryHandshake(packet, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int32(9513307), token)
+
+	_, err = parseQueryHandshake(packet, 2)
+	assert.Error(t, err, "session ID mismatch")
+
+	_, err = parseQueryHandshake(append([]byte{queryTypeHandshake, 0, 0, 0, 1}, []byte("abc\x00")...), 1)
+	assert.Error(t, err, "non-numeric token")
+}
+
+func TestParseFullStat(t *testing.T) {
+	status, err := parseFullStat(buildTestFullStat(7, testStatFields, []string{"Steve", "Alex"}), 7)
+	require.NoError(t, err)
+
+	assert.Equal(t, "A Minecraft Server", status.MOTD)
+	assert.Equal(t, "1.20.4", status.Version)
+	assert.Equal(t, "world", status.Map)
+	assert.Equal(t, "Paper on Bukkit 1.20.4", status.Software)
+	assert.Equal(t, []Plugin{{"LuckPerms", "5.4.102"}, {"EssentialsX", "2.20.1"}}, status.Plugins)
+	assert.Equal(t, 2, status.OnlinePlayers)
+	assert.Equal(t, 20, status.MaxPlayers)
+	assert.Equal(t, []string{"Steve", "Alex"}, status.Players)
+}
+
+func TestParseFullStat_Invalid(t *testing.T) {
+	valid := buildTestFullStat(7, testStatFields, nil)
+
+	badPadding := append([]byte{}, valid...)
+	badPadding[5] = 'x'
+
+	tests := map[string][]byte{
+		"too short":    valid[:3],
+		"wrong type":   append([]byte{queryTypeHandshake}, valid[1:]...),
+		"bad padding":  badPadding,
+		"unterminated": valid[:len(valid)-40],
+		"no counts":    buildTestFullStat(7, [][2]string{{"hostname", "x"}}, nil),
+	}
+
+	for name, packet := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseFullStat(packet, 7)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParsePlugins(t *testing.T) {
+	software, plugins := parsePlugins("")
+	assert.Empty(t, software)
+	assert.Empty(t, plugins)
+
+	software, plugins = parsePlugins("CraftBukkit on Bukkit 1.2.5-R4.0")
+	assert.Equal(t, "CraftBukkit on Bukkit 1.2.5-R4.0", software)
+	assert.Empty(t, plugins)
+
+	software, plugins = parsePlugins("Spigot: WorldEdit 7.2; Vault")
+	assert.Equal(t, "Spigot", software)
+	assert.Equal(t, []Plugin{{"WorldEdit", "7.2"}, {"Vault", ""}}, plugins)
+}
+
+func TestServerStatus_ApplyQuery(t *testing.T) {
+	status := &ServerStatus{
+		Online:  true,
+		Version: "Paper 1.20.4",
+		Players: PlayersInfo{
+			Online: 3,
+			Max:    20,
+			Sample: []Player{{Name: "Steve", UUID: "uuid-steve"}},
+		},
+	}
+
+	status.ApplyQuery(&QueryStatus{
+		Version:       "1.20.4",
+		Map:           "world",
+		OnlinePlayers: 3,
+		MaxPlayers:    20,
+		Players:       []string{"Steve", "Alex", "Notch"},
+	})
+
+	assert.Equal(t, "Paper 1.20.4", status.Version, "ping version is kept")
+	assert.Equal(t, "world", status.Query.Map)
+	require.Len(t, status.Players.Sample, 3)
+	assert.Equal(t, Player{Name: "Steve", UUID: "uuid-steve"}, status.Players.Sample[0])
+	assert.Equal(t, Player{Name: "Alex"}, status.Players.Sample[1])
+}
+
+func TestServerStatus_ApplyQuery_PingFailed(t *testing.T) {
+	status := &ServerStatus{Online: false}
+
+	status.ApplyQuery(&QueryStatus{MOTD: "Hello", Version: "1.20.4", OnlinePlayers: 1, MaxPlayers: 10, Players: []string{"Steve"}})
+
+	assert.True(t, status.Online)
+	assert.Equal(t, "1.20.4", status.Version)
+	assert.Equal(t, "Hello", status.Description)
+	assert.Equal(t, 1, status.Players.Online)
+}
+
+func TestClient_QueryFull(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil || n < 7 || !bytes.Equal(buf[:2], queryMagic) {
+				return
+			}
+			sessionID := int32(binary.BigEndian.Uint32(buf[3:7]))
+
+			var response []byte
+			switch buf[2] {
+			case queryTypeHandshake:
+				response = append([]byte{queryTypeHandshake}, buf[3:7]...)
+				response = append(response, []byte("12345\x00")...)
+			case queryTypeStat:
+				if int32(binary.BigEndian.Uint32(buf[7:11])) != 12345 {
+					return
+				}
+				response = buildTestFullStat(sessionID, testStatFields, []string{"Steve", "Alex"})
+			}
+			_, _ = conn.WriteTo(response, addr)
+		}
+	}()
+
+	client := NewClient(2 * time.Second)
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+
+	status, err := client.QueryFull(context.Background(), "127.0.0.1", port)
+	require.NoError(t, err)
+
+	assert.Equal(t, "world", status.Map)
+	assert.Equal(t, []string{"Steve", "Alex"}, status.Players)
+}
+
+func TestClient_QueryFull_Disabled(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+	conn.Close()
+
+	client := NewClient(200 * time.Millisecond)
+
+	_, err = client.QueryFull(context.Background(), "127.0.0.1", port)
+	assert.Error(t, err)
+}
+
+func TestClient_QueryFull_FakeServer(t *testing.T) {
+	server := mctest.NewServer(mctest.WithQuery(), mctest.WithStatus(mctest.Status{
+		Version:    "1.20.4",
+		MOTD:       "A Minecraft Server",
+		MaxPlayers: 20,
+		Players:    []string{"Steve", "Alex"},
+		Map:        "world",
+		Plugins:    "Paper on Bukkit 1.20.4: LuckPerms 5.4.102",
+	}))
+	defer server.Close()
+
+	status, err := NewClient(2*time.Second).QueryFull(context.Background(), server.Host, server.QueryPort)
+	require.NoError(t, err)
+
+	assert.Equal(t, "world", status.Map)
+	assert.Equal(t, "Paper on Bukkit 1.20.4", status.Software)
+	assert.Equal(t, []Plugin{{"LuckPerms", "5.4.102"}}, status.Plugins)
+	assert.Equal(t, []string{"Steve", "Alex"}, status.Players)
+	assert.Equal(t, 20, status.MaxPlayers)
+}
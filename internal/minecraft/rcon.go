@@ -0,0 +1,145 @@
+package minecraft
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultRCONPort is the default rcon.port of Java Edition servers.
+const DefaultRCONPort = 25575
+
+const (
+	// rconTypeResponse is the packet type of command output.
+	rconTypeResponse int32 = 0
+	// rconTypeCommand is the packet type of a command, and of the answer to a login.
+	rconTypeCommand int32 = 2
+	// rconTypeLogin is the packet type of a login with the password.
+	rconTypeLogin int32 = 3
+	// rconAuthFailedID is the request ID servers answer a wrong password with.
+	rconAuthFailedID int32 = -1
+	// rconMaxPacketSize is the largest packet servers send: 4096 bytes of output and the header.
+	rconMaxPacketSize = 4096 + 14
+	// rconMaxCommandLength is the longest command servers accept.
+	rconMaxCommandLength = 1446
+)
+
+// ErrRCONAuth is returned when the server rejects the RCON password.
+var ErrRCONAuth = errors.New("rcon authentication failed")
+
+// ExecRCON logs in to the remote console of a Java Edition server and runs a command, returning its output.
+// Output longer than one packet is collected in full.
+func (c *Client) ExecRCON(ctx context.Context, host string, port int, password, command string) (string, error) {
+	log.Debug().Str("host", host).Int("port", port).Msg("running rcon command")
+
+	if len(command) > rconMaxCommandLength {
+		return "", fmt.Errorf("command is too long: %d bytes, at most %d allowed", len(command), rconMaxCommandLength)
+	}
+
+	output, err := c.execRCON(ctx, host, port, password, command)
+	if err != nil {
+		log.Warn().Err(err).Str("host", host).Int("port", port).Msg("rcon command failed")
+		return "", err
+	}
+
+	log.Debug().Str("host", host).Int("port", port).Int("output_length", len(output)).Msg("rcon command successful")
+	return output, nil
+}
+
+func (c *Client) execRCON(ctx context.Context, host string, port int, password, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return "", fmt.Errorf("could not dial: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return "", fmt.Errorf("could not set deadline: %w", err)
+		}
+	}
+	reader := bufio.NewReader(conn)
+
+	if err := writeRCONPacket(conn, 1, rconTypeLogin, password); err != nil {
+		return "", fmt.Errorf("could not write login packet: %w", err)
+	}
+	id, _, _, err := readRCONPacket(reader)
+	if err != nil {
+		return "", fmt.Errorf("could not read login response: %w", err)
+	}
+	if id == rconAuthFailedID {
+		return "", ErrRCONAuth
+	}
+
+	// Servers split long output over several packets without marking the last one,
+	// so the command is followed by a request of an unknown type: its answer comes after the whole output.
+	if err := writeRCONPacket(conn, 2, rconTypeCommand, command); err != nil {
+		return "", fmt.Errorf("could not write command packet: %w", err)
+	}
+	if err := writeRCONPacket(conn, 3, rconTypeResponse, ""); err != nil {
+		return "", fmt.Errorf("could not write end marker packet: %w", err)
+	}
+
+	var output strings.Builder
+	for {
+		id, packetType, body, err := readRCONPacket(reader)
+		if err != nil {
+			return "", fmt.Errorf("could not read command response: %w", err)
+		}
+		if id == 3 {
+			return output.String(), nil
+		}
+		if id != 2 || packetType != rconTypeResponse {
+			return "", fmt.Errorf("unexpected rcon packet: id %d, type %d", id, packetType)
+		}
+		output.WriteString(body)
+	}
+}
+
+// writeRCONPacket writes a packet: its little-endian length, request ID and type,
+// then the body terminated by two zero bytes.
+func writeRCONPacket(w io.Writer, id, packetType int32, body string) error {
+	packet := bytes.NewBuffer(make([]byte, 0, len(body)+14))
+	_ = binary.Write(packet, binary.LittleEndian, int32(len(body)+10))
+	_ = binary.Write(packet, binary.LittleEndian, id)
+	_ = binary.Write(packet, binary.LittleEndian, packetType)
+	packet.WriteString(body)
+	packet.Write([]byte{0x00, 0x00})
+
+	_, err := w.Write(packet.Bytes())
+	return err
+}
+
+// readRCONPacket reads a packet and returns its request ID, type and body.
+func readRCONPacket(r io.Reader) (int32, int32, string, error) {
+	var length int32
+	if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+		return 0, 0, "", err
+	}
+	if length < 10 || length > rconMaxPacketSize {
+		return 0, 0, "", fmt.Errorf("invalid rcon packet length: %d", length)
+	}
+
+	packet := make([]byte, length)
+	if _, err := io.ReadFull(r, packet); err != nil {
+		return 0, 0, "", err
+	}
+
+	id := int32(binary.LittleEndian.Uint32(packet[0:4]))
+	packetType := int32(binary.LittleEndian.Uint32(packet[4:8]))
+	body := bytes.TrimRight(packet[8:], "\x00")
+	return id, packetType, string(body), nil
+}
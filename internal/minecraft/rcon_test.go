@@ -0,0 +1,63 @@
+package minecraft
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ykhdr/mss-bot/internal/minecraft/mctest"
+)
+
+func TestClient_ExecRCON(t *testing.T) {
+	server := mctest.NewServer(mctest.WithRCON("secret", func(command string) string {
+		return "There are 0 of a max of 20 players online: " + command
+	}))
+	defer server.Close()
+
+	output, err := NewClient(2*time.Second).ExecRCON(context.Background(), server.Host, server.RCONPort, "secret", "list")
+	require.NoError(t, err)
+	assert.Equal(t, "There are 0 of a max of 20 players online: list", output)
+}
+
+func TestClient_ExecRCON_LongOutput(t *testing.T) {
+	long := strings.Repeat("a", 4096) + strings.Repeat("b", 100)
+	server := mctest.NewServer(mctest.WithRCON("secret", func(string) string { return long }))
+	defer server.Close()
+
+	output, err := NewClient(2*time.Second).ExecRCON(context.Background(), server.Host, server.RCONPort, "secret", "help")
+	require.NoError(t, err)
+	assert.Equal(t, long, output)
+}
+
+func TestClient_ExecRCON_WrongPassword(t *testing.T) {
+	server := mctest.NewServer(mctest.WithRCON("secret", func(string) string { return "" }))
+	defer server.Close()
+
+	_, err := NewClient(2*time.Second).ExecRCON(context.Background(), server.Host, server.RCONPort, "guess", "stop")
+	assert.ErrorIs(t, err, ErrRCONAuth)
+}
+
+func TestClient_ExecRCON_CommandTooLong(t *testing.T) {
+	_, err := NewClient(time.Second).ExecRCON(context.Background(), "127.0.0.1", 1, "secret", strings.Repeat("x", 2000))
+	assert.ErrorContains(t, err, "too long")
+}
+
+func TestRCONPacket_RoundTrip(t *testing.T) {
+	buf := new(bytes.Buffer)
+	require.NoError(t, writeRCONPacket(buf, 7, rconTypeCommand, "say hi"))
+	assert.Equal(t, 4+4+4+len("say hi")+2, buf.Len())
+
+	id, packetType, body, err := readRCONPacket(buf)
+	require.NoError(t, err)
+	assert.Equal(t, int32(7), id)
+	assert.Equal(t, rconTypeCommand, packetType)
+	assert.Equal(t, "say hi", body)
+
+	_, _, _, err = readRCONPacket(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0x7f}))
+	assert.Error(t, err)
+}
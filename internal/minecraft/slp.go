@@ -0,0 +1,323 @@
+package minecraft
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// slpHandshakePacket, slpStatusPacket and slpPingPacket are the serverbound packet IDs
+	// of the status exchange; responses reuse the same IDs.
+	slpHandshakePacket int32 = 0x00
+	slpStatusPacket    int32 = 0x00
+	slpPingPacket      int32 = 0x01
+	// slpNextStateStatus asks the server to switch to the status state after the handshake.
+	slpNextStateStatus int32 = 1
+	// slpProtocolVersion is sent in the handshake until the server's own protocol is known;
+	// servers answer status requests of any version, though proxies may answer differently.
+	slpProtocolVersion = 759 // 1.19
+	// slpMaxPacketSize bounds the status response, which may carry a base64 favicon.
+	slpMaxPacketSize = 2 << 20
+)
+
+// slpResponse is the JSON document of a status response.
+type slpResponse struct {
+	Version struct {
+		Name     string `json:"name"`
+		Protocol int    `json:"protocol"`
+	} `json:"version"`
+	Players struct {
+		Max    int `json:"max"`
+		Online int `json:"online"`
+		Sample []struct {
+			Name string `json:"name"`
+			ID   string `json:"id"`
+		} `json:"sample"`
+	} `json:"players"`
+	Description json.RawMessage `json:"description"`
+	Favicon     string          `json:"favicon"`
+	ModInfo     json.RawMessage `json:"modinfo"`
+	ForgeData   json.RawMessage `json:"forgeData"`
+}
+
+// AutoProtocolVersion advertises the protocol version the server reported in its last status response
+const AutoProtocolVersion = 0
+
+// pingSLP runs the 1.7+ server list ping: handshake, status request and the ping/pong
+// exchange used to measure latency. DNS and connect times are measured on the way.
+func (c *Client) pingSLP(ctx context.Context, host string, port, protocol int) (*ServerStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	addrs, err := c.lookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	dnsTime := time.Since(start)
+
+	start = time.Now()
+	conn, err := dialAny(ctx, addrs, port)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	connectTime := time.Since(start)
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, fmt.Errorf("could not set deadline: %w", err)
+		}
+	}
+	reader := bufio.NewReader(conn)
+
+	request := new(bytes.Buffer)
+	key := net.JoinHostPort(host, strconv.Itoa(port))
+	writePacket(request, slpHandshakePacket, buildHandshake(int32(c.handshakeProtocol(key, protocol)), host, port))
+	writePacket(request, slpStatusPacket, nil)
+	if _, err := conn.Write(request.Bytes()); err != nil {
+		return nil, fmt.Errorf("could not write status request: %w", err)
+	}
+
+	payload, err := readPacket(reader, slpStatusPacket)
+	if err != nil {
+		return nil, fmt.Errorf("could not read status response: %w", err)
+	}
+	response, err := parseStatusResponse(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if protocol == AutoProtocolVersion {
+		c.rememberProtocol(key, response.Version.Protocol)
+	}
+
+	status := c.convertStatus(response)
+	status.DNSTime = dnsTime
+	status.ConnectTime = connectTime
+
+	// Some proxies close the connection right after the status response,
+	// so a failed ping/pong only leaves the latency unknown.
+	if latency, err := pingPong(conn, reader, time.Now()); err == nil {
+		status.Latency = latency
+	}
+
+	return status, nil
+}
+
+// handshakeProtocol returns the protocol version to advertise to a server: the configured one,
+// or in auto mode the one the server reported, falling back to slpProtocolVersion.
+func (c *Client) handshakeProtocol(key string, protocol int) int {
+	if protocol != AutoProtocolVersion {
+		return protocol
+	}
+
+	if remembered, ok := c.protocols.get(key); ok {
+		return remembered
+	}
+	return slpProtocolVersion
+}
+
+// rememberProtocol stores the protocol version a server reported. Proxies answering
+// with a placeholder such as -1 are not remembered.
+func (c *Client) rememberProtocol(key string, protocol int) {
+	if protocol <= 0 {
+		return
+	}
+
+	if remembered, ok := c.protocols.get(key); !ok || remembered != protocol {
+		log.Debug().Str("address", key).Int("protocol", protocol).Msg("detected minecraft protocol version")
+	}
+	c.protocols.set(key, protocol)
+}
+
+// lookupHost resolves host to IP addresses; IP literals are returned as is.
+func (c *Client) lookupHost(ctx context.Context, host string) ([]string, error) {
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+	if c.resolver == nil {
+		return net.DefaultResolver.LookupHost(ctx, host)
+	}
+	return c.resolver.LookupHost(ctx, host)
+}
+
+// dialAny connects to the first address that accepts a TCP connection.
+func dialAny(ctx context.Context, addrs []string, port int) (net.Conn, error) {
+	var dialer net.Dialer
+	var errs []error
+	for _, addr := range addrs {
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(addr, strconv.Itoa(port)))
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no addresses to dial")
+	}
+	return nil, errors.Join(errs...)
+}
+
+// pingPong sends a ping packet with the current time as payload and measures
+// how long the matching pong takes.
+func pingPong(conn net.Conn, reader *bufio.Reader, now time.Time) (time.Duration, error) {
+	payload := make([]byte, 8)
+	binary.BigEndian.PutUint64(payload, uint64(now.UnixMilli()))
+
+	request := new(bytes.Buffer)
+	writePacket(request, slpPingPacket, payload)
+
+	start := time.Now()
+	if _, err := conn.Write(request.Bytes()); err != nil {
+		return 0, fmt.Errorf("could not write ping packet: %w", err)
+	}
+	pong, err := readPacket(reader, slpPingPacket)
+	if err != nil {
+		return 0, fmt.Errorf("could not read pong packet: %w", err)
+	}
+	latency := time.Since(start)
+
+	if !bytes.Equal(pong, payload) {
+		return 0, fmt.Errorf("pong payload does not match ping")
+	}
+	return latency, nil
+}
+
+// buildHandshake builds the handshake packet payload.
+func buildHandshake(protocol int32, host string, port int) []byte {
+	payload := new(bytes.Buffer)
+	writeVarInt(payload, protocol)
+	writeString(payload, host)
+	_ = binary.Write(payload, binary.BigEndian, uint16(port))
+	writeVarInt(payload, slpNextStateStatus)
+	return payload.Bytes()
+}
+
+// writePacket appends a length-prefixed packet to buf.
+func writePacket(buf *bytes.Buffer, id int32, payload []byte) {
+	body := new(bytes.Buffer)
+	writeVarInt(body, id)
+	body.Write(payload)
+
+	writeVarInt(buf, int32(body.Len()))
+	buf.Write(body.Bytes())
+}
+
+// readPacket reads a length-prefixed packet, checks its ID and returns the payload.
+func readPacket(reader *bufio.Reader, wantID int32) ([]byte, error) {
+	length, err := readVarInt(reader)
+	if err != nil {
+		return nil, err
+	}
+	if length <= 0 || length > slpMaxPacketSize {
+		return nil, fmt.Errorf("invalid packet length: %d", length)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return nil, err
+	}
+
+	bodyReader := bytes.NewReader(body)
+	id, err := readVarInt(bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	if id != wantID {
+		return nil, fmt.Errorf("unexpected packet ID: 0x%02x", id)
+	}
+	return body[len(body)-bodyReader.Len():], nil
+}
+
+// parseStatusResponse decodes the JSON string carried by a status response packet.
+func parseStatusResponse(payload []byte) (*slpResponse, error) {
+	reader := bytes.NewReader(payload)
+	length, err := readVarInt(reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not read status length: %w", err)
+	}
+	if length < 0 || int(length) > reader.Len() {
+		return nil, fmt.Errorf("invalid status length: %d", length)
+	}
+
+	var response slpResponse
+	if err := json.Unmarshal(payload[len(payload)-reader.Len():][:length], &response); err != nil {
+		return nil, fmt.Errorf("could not parse status JSON: %w", err)
+	}
+	return &response, nil
+}
+
+// faviconPrefix is the data URI scheme the favicon is sent with.
+const faviconPrefix = "data:image/png;base64,"
+
+// decodeFavicon decodes the data URI of a server icon to PNG bytes.
+// Older servers wrap the base64 data in lines, so whitespace is ignored.
+func decodeFavicon(favicon string) ([]byte, error) {
+	if favicon == "" {
+		return nil, nil
+	}
+
+	data, ok := strings.CutPrefix(favicon, faviconPrefix)
+	if !ok {
+		return nil, fmt.Errorf("unsupported favicon format")
+	}
+	data = strings.Join(strings.Fields(data), "")
+
+	png, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode favicon: %w", err)
+	}
+	return png, nil
+}
+
+// writeVarInt writes a Minecraft VarInt: a 32-bit value in 7-bit groups, least significant first.
+func writeVarInt(buf *bytes.Buffer, value int32) {
+	v := uint32(value)
+	for {
+		if v&^0x7f == 0 {
+			buf.WriteByte(byte(v))
+			return
+		}
+		buf.WriteByte(byte(v&0x7f) | 0x80)
+		v >>= 7
+	}
+}
+
+// readVarInt reads a Minecraft VarInt of at most five bytes.
+func readVarInt(reader io.ByteReader) (int32, error) {
+	var value uint32
+	for i := 0; i < 5; i++ {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		value |= uint32(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			return int32(value), nil
+		}
+	}
+	return 0, fmt.Errorf("VarInt is too big")
+}
+
+// writeString writes a VarInt length-prefixed UTF-8 string.
+func writeString(buf *bytes.Buffer, s string) {
+	writeVarInt(buf, int32(len(s)))
+	buf.WriteString(s)
+}
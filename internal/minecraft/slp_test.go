@@ -0,0 +1,219 @@
+package minecraft
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startModernServer runs a TCP server that answers the 1.7+ status exchange with
+// statusJSON; the pong is delayed by pongDelay, or skipped when answerPing is false.
+func startModernServer(t *testing.T, statusJSON string, answerPing bool, pongDelay time.Duration) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+
+				if _, err := readPacket(reader, slpHandshakePacket); err != nil {
+					return
+				}
+				if _, err := readPacket(reader, slpStatusPacket); err != nil {
+					return
+				}
+
+				response := new(bytes.Buffer)
+				payload := new(bytes.Buffer)
+				writeString(payload, statusJSON)
+				writePacket(response, slpStatusPacket, payload.Bytes())
+				if _, err := conn.Write(response.Bytes()); err != nil || !answerPing {
+					return
+				}
+
+				ping, err := readPacket(reader, slpPingPacket)
+				if err != nil {
+					return
+				}
+				time.Sleep(pongDelay)
+				pong := new(bytes.Buffer)
+				writePacket(pong, slpPingPacket, ping)
+				_, _ = conn.Write(pong.Bytes())
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+const testStatusJSON = `{
+	"version": {"name": "Paper 1.20.4", "protocol": 765},
+	"players": {"max": 20, "online": 2, "sample": [{"name": "Steve", "id": "069a79f4-44e9-4726-a5be-fca90e38aaf5"}]},
+	"description": {"text": "Hello ", "extra": [{"text": "world", "bold": true}]},
+	"favicon": "data:image/png;base64,iVBORw0KGgo="
+}`
+
+func TestClient_GetStatus_Modern(t *testing.T) {
+	port := startModernServer(t, testStatusJSON, true, 20*time.Millisecond)
+	client := NewClient(2 * time.Second)
+
+	status, err := client.GetStatus(context.Background(), "127.0.0.1", port)
+	require.NoError(t, err)
+
+	assert.True(t, status.Online)
+	assert.Equal(t, ProtocolModern, status.Family)
+	assert.Equal(t, "Paper 1.20.4", status.Version)
+	assert.Equal(t, 765, status.Protocol)
+	assert.Equal(t, "Hello world", status.Description)
+	assert.Equal(t, 2, status.Players.Online)
+	assert.Equal(t, 20, status.Players.Max)
+	require.Len(t, status.Players.Sample, 1)
+	assert.Equal(t, "Steve", status.Players.Sample[0].Name)
+	assert.Equal(t, "069a79f4-44e9-4726-a5be-fca90e38aaf5", status.Players.Sample[0].UUID)
+	assert.Equal(t, []byte("\x89PNG\r\n\x1a\n"), status.Favicon)
+
+	assert.GreaterOrEqual(t, status.Latency, 20*time.Millisecond)
+	assert.Less(t, status.Latency, time.Second)
+	assert.Less(t, status.DNSTime, time.Millisecond, "IP literals are not resolved")
+	assert.Positive(t, status.ConnectTime)
+}
+
+func TestClient_GetStatus_ModernWithoutPong(t *testing.T) {
+	port := startModernServer(t, testStatusJSON, false, 0)
+	client := NewClient(2 * time.Second)
+
+	status, err := client.GetStatus(context.Background(), "127.0.0.1", port)
+	require.NoError(t, err)
+
+	assert.True(t, status.Online)
+	assert.Zero(t, status.Latency)
+}
+
+func TestClient_GetStatus_ModernInvalidJSON(t *testing.T) {
+	port := startModernServer(t, "not json", false, 0)
+	client := NewClient(2 * time.Second)
+
+	_, err := client.pingSLP(context.Background(), "127.0.0.1", port, AutoProtocolVersion)
+	require.Error(t, err)
+	assert.Equal(t, ErrorKindProtocol, ErrorKindOf(err))
+}
+
+// startHandshakeRecorder runs a TCP server answering status requests with a server on the given protocol
+// and sending the protocol version of every handshake to the returned channel.
+func startHandshakeRecorder(t *testing.T, protocol int) (int, <-chan int32) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	handshakes := make(chan int32, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+
+				handshake, err := readPacket(reader, slpHandshakePacket)
+				if err != nil {
+					return
+				}
+				version, err := readVarInt(bytes.NewReader(handshake))
+				if err != nil {
+					return
+				}
+				handshakes <- version
+				if _, err := readPacket(reader, slpStatusPacket); err != nil {
+					return
+				}
+
+				response := new(bytes.Buffer)
+				payload := new(bytes.Buffer)
+				writeString(payload, fmt.Sprintf(`{"version":{"name":"1.8.9","protocol":%d},"players":{"max":1,"online":0}}`, protocol))
+				writePacket(response, slpStatusPacket, payload.Bytes())
+				_, _ = conn.Write(response.Bytes())
+			}()
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port, handshakes
+}
+
+func TestClient_GetStatus_AutoProtocolVersion(t *testing.T) {
+	port, handshakes := startHandshakeRecorder(t, 47)
+	client := NewClient(2 * time.Second)
+
+	for _, want := range []int32{slpProtocolVersion, 47} {
+		_, err := client.GetStatus(context.Background(), "127.0.0.1", port)
+		require.NoError(t, err)
+		assert.Equal(t, want, <-handshakes)
+	}
+}
+
+func TestClient_GetStatusWithProtocol(t *testing.T) {
+	port, handshakes := startHandshakeRecorder(t, 47)
+	client := NewClient(2 * time.Second)
+
+	_, err := client.GetStatusWithProtocol(context.Background(), "127.0.0.1", port, 340)
+	require.NoError(t, err)
+	assert.Equal(t, int32(340), <-handshakes)
+
+	// An explicit version is not learned from, so auto mode still starts with the default
+	_, err = client.GetStatus(context.Background(), "127.0.0.1", port)
+	require.NoError(t, err)
+	assert.Equal(t, int32(slpProtocolVersion), <-handshakes)
+}
+
+func TestVarInt_RoundTrip(t *testing.T) {
+	for _, value := range []int32{0, 1, 127, 128, 255, 25565, 2097151, 2147483647, -1} {
+		buf := new(bytes.Buffer)
+		writeVarInt(buf, value)
+
+		got, err := readVarInt(buf)
+		require.NoError(t, err)
+		assert.Equal(t, value, got)
+	}
+}
+
+func TestReadVarInt_TooBig(t *testing.T) {
+	_, err := readVarInt(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0x01}))
+	assert.Error(t, err)
+}
+
+func TestDecodeFavicon(t *testing.T) {
+	png, err := decodeFavicon("data:image/png;base64,iVBO\nRw0K\nGgo=")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("\x89PNG\r\n\x1a\n"), png)
+
+	png, err = decodeFavicon("")
+	require.NoError(t, err)
+	assert.Nil(t, png)
+
+	_, err = decodeFavicon("data:image/jpeg;base64,AAAA")
+	assert.Error(t, err)
+
+	_, err = decodeFavicon("data:image/png;base64,not base64!")
+	assert.Error(t, err)
+}
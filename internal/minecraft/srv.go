@@ -0,0 +1,60 @@
+package minecraft
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Resolver looks up DNS records. *net.Resolver implements it.
+type Resolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (cname string, addrs []*net.SRV, err error)
+	LookupHost(ctx context.Context, host string) (addrs []string, err error)
+}
+
+// Target is the host and port a query is actually sent to.
+type Target struct {
+	Host string
+	Port int
+}
+
+// resolveSRV looks up the _minecraft._tcp SRV record of host, like the game client does.
+// Lookups are only made for hostnames given without a port, that is with port 0; on any failure
+// the host and DefaultJavaPort are used and resolved is false. Explicit ports are used as they are.
+func (c *Client) resolveSRV(ctx context.Context, host string, port int) (target Target, resolved bool) {
+	if port != 0 {
+		return Target{Host: host, Port: port}, false
+	}
+
+	target = Target{Host: host, Port: DefaultJavaPort}
+	if net.ParseIP(host) != nil || c.resolver == nil {
+		return target, false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	_, records, err := c.resolver.LookupSRV(ctx, "minecraft", "tcp", host)
+	if err != nil || len(records) == 0 {
+		log.Debug().Err(err).Str("host", host).Msg("no minecraft SRV record")
+		return target, false
+	}
+
+	record := records[0]
+	target = Target{
+		Host: strings.TrimSuffix(record.Target, "."),
+		Port: int(record.Port),
+	}
+	if target.Host == "" {
+		return Target{Host: host, Port: DefaultJavaPort}, false
+	}
+
+	log.Debug().
+		Str("host", host).
+		Str("target_host", target.Host).
+		Int("target_port", target.Port).
+		Msg("resolved minecraft SRV record")
+	return target, true
+}
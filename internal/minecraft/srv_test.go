@@ -0,0 +1,79 @@
+package minecraft
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeResolver returns fixed SRV records and counts lookups
+type fakeResolver struct {
+	records []*net.SRV
+	err     error
+	lookups int
+}
+
+func (f *fakeResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	f.lookups++
+	return "", f.records, f.err
+}
+
+func (f *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestResolveSRV_Found(t *testing.T) {
+	resolver := &fakeResolver{records: []*net.SRV{{Target: "mc1.example.net.", Port: 25570}}}
+	client := NewClient(time.Second, WithResolver(resolver))
+
+	target, resolved := client.resolveSRV(context.Background(), "play.example.net", 0)
+
+	assert.True(t, resolved)
+	assert.Equal(t, Target{Host: "mc1.example.net", Port: 25570}, target)
+}
+
+func TestResolveSRV_ExplicitPortSkipsLookup(t *testing.T) {
+	resolver := &fakeResolver{records: []*net.SRV{{Target: "mc1.example.net.", Port: 25570}}}
+	client := NewClient(time.Second, WithResolver(resolver))
+
+	target, resolved := client.resolveSRV(context.Background(), "play.example.net", 25566)
+
+	assert.False(t, resolved)
+	assert.Equal(t, Target{Host: "play.example.net", Port: 25566}, target)
+	assert.Zero(t, resolver.lookups)
+}
+
+func TestResolveSRV_ExplicitDefaultPortSkipsLookup(t *testing.T) {
+	resolver := &fakeResolver{records: []*net.SRV{{Target: "mc1.example.net.", Port: 25570}}}
+	client := NewClient(time.Second, WithResolver(resolver))
+
+	target, resolved := client.resolveSRV(context.Background(), "play.example.net", DefaultJavaPort)
+
+	assert.False(t, resolved, "host:25565 is not redirected")
+	assert.Equal(t, Target{Host: "play.example.net", Port: DefaultJavaPort}, target)
+	assert.Zero(t, resolver.lookups)
+}
+
+func TestResolveSRV_IPAddressSkipsLookup(t *testing.T) {
+	resolver := &fakeResolver{}
+	client := NewClient(time.Second, WithResolver(resolver))
+
+	_, resolved := client.resolveSRV(context.Background(), "192.168.1.100", 0)
+
+	assert.False(t, resolved)
+	assert.Zero(t, resolver.lookups)
+}
+
+func TestResolveSRV_LookupFailureFallsBack(t *testing.T) {
+	resolver := &fakeResolver{err: errors.New("no such host")}
+	client := NewClient(time.Second, WithResolver(resolver))
+
+	target, resolved := client.resolveSRV(context.Background(), "play.example.net", 0)
+
+	assert.False(t, resolved)
+	assert.Equal(t, Target{Host: "play.example.net", Port: DefaultJavaPort}, target)
+}
@@ -1,12 +1,59 @@
 package minecraft
 
+import "time"
+
+// ProtocolFamily is the server list ping format a Java Edition server answered
+type ProtocolFamily string
+
+const (
+	// ProtocolModern is the 1.7+ handshake/status ping
+	ProtocolModern ProtocolFamily = "1.7+"
+	// ProtocolLegacy16 is the 1.6 ping (FE 01 FA plugin message)
+	ProtocolLegacy16 ProtocolFamily = "1.6"
+	// ProtocolLegacy14 is the 1.4–1.5 ping (FE 01)
+	ProtocolLegacy14 ProtocolFamily = "1.4–1.5"
+	// ProtocolBeta18 is the Beta 1.8–1.3 ping (FE)
+	ProtocolBeta18 ProtocolFamily = "Beta 1.8–1.3"
+)
+
 // ServerStatus represents the status of a Minecraft server
 type ServerStatus struct {
 	Online      bool
 	Version     string
 	Protocol    int
 	Players     PlayersInfo
-	Description string
+	Description string // MOTD without formatting
+	MOTD        ChatText
+
+	// PNG server icon, nil when the server has none
+	Favicon []byte
+
+	// Mods advertised by modded servers, nil for vanilla ones
+	Mods *ModInfo
+
+	// Ping format the Java Edition server answered
+	Family ProtocolFamily
+
+	// Round-trip time of the ping/pong exchange with the DNS lookup and TCP connect
+	// times measured on the way; zero when unknown
+	Latency     time.Duration
+	DNSTime     time.Duration
+	ConnectTime time.Duration
+
+	// When the server was actually queried; cached results keep the original time
+	CheckedAt time.Time
+
+	// Target the status was queried from when the host has a _minecraft._tcp SRV record
+	ResolvedHost string
+	ResolvedPort int
+
+	// Bedrock Edition only
+	Edition   string // MCPE or MCEE (Education Edition)
+	LevelName string
+	GameMode  string
+
+	// Java Edition full stat, set when the server answers GameSpy4 queries
+	Query *QueryStatus
 }
 
 // PlayersInfo contains player count and list information
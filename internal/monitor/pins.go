@@ -0,0 +1,144 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ykhdr/mss-bot/internal/service"
+	"github.com/ykhdr/mss-bot/internal/storage"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+// ErrMessageGone is returned by a MessageEditor for messages that were deleted or can no longer be edited
+var ErrMessageGone = errors.New("message is gone")
+
+// PinSource provides the pinned status cards to update
+type PinSource interface {
+	ListPins(ctx context.Context) ([]*models.PinnedMessage, error)
+	Unpin(ctx context.Context, pin *models.PinnedMessage) error
+}
+
+// StatusSource provides the status of servers registered in chats
+type StatusSource interface {
+	GetServerStatus(ctx context.Context, chatID, serverID int64) (*service.ServerStatusResult, error)
+}
+
+// MessageEditor replaces the text of bot messages with MarkdownV2 formatted text
+type MessageEditor interface {
+	EditMessage(ctx context.Context, chatID int64, messageID int, text string) error
+}
+
+// PinUpdater periodically edits pinned status cards in place with the latest server status.
+// Cards that were deleted, or that the bot can no longer edit, are forgotten.
+type PinUpdater struct {
+	pins     PinSource
+	status   StatusSource
+	settings SettingsSource
+	editor   MessageEditor
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewPinUpdater creates a new pinned status card updater
+func NewPinUpdater(
+	pins PinSource,
+	status StatusSource,
+	settings SettingsSource,
+	editor MessageEditor,
+	interval time.Duration,
+) *PinUpdater {
+	return &PinUpdater{
+		pins:     pins,
+		status:   status,
+		settings: settings,
+		editor:   editor,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Run updates pinned status cards until the context is canceled
+func (u *PinUpdater) Run(ctx context.Context) {
+	log.Info().Dur("interval", u.interval).Msg("starting pinned status updater")
+
+	ticker := time.NewTicker(u.interval)
+	defer ticker.Stop()
+
+	u.Update(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("pinned status updater stopped")
+			return
+		case <-ticker.C:
+			u.Update(ctx)
+		}
+	}
+}
+
+// Update edits every pinned status card once
+func (u *PinUpdater) Update(ctx context.Context) {
+	pins, err := u.pins.ListPins(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list pinned status cards")
+		return
+	}
+
+	log.Debug().Int("pins", len(pins)).Msg("updating pinned status cards")
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentChecks)
+	for _, pin := range pins {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(pin *models.PinnedMessage) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			u.update(ctx, pin)
+		}(pin)
+	}
+	wg.Wait()
+}
+
+func (u *PinUpdater) update(ctx context.Context, pin *models.PinnedMessage) {
+	result, err := u.status.GetServerStatus(ctx, pin.ChatID, pin.ServerID)
+	if err != nil {
+		var notFound storage.ErrNotFound
+		if errors.As(err, &notFound) {
+			u.forget(ctx, pin, "server removed")
+			return
+		}
+		log.Error().Err(err).Int64("chat_id", pin.ChatID).Int64("server_id", pin.ServerID).Msg("failed to get pinned server status")
+		return
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	text := result.FormatPinnedStatus(chatLanguage(ctx, u.settings, pin.ChatID), u.now())
+	err = u.editor.EditMessage(ctx, pin.ChatID, pin.MessageID, text)
+	switch {
+	case errors.Is(err, ErrMessageGone):
+		u.forget(ctx, pin, err.Error())
+	case err != nil:
+		log.Error().Err(err).Int64("chat_id", pin.ChatID).Int("message_id", pin.MessageID).Msg("failed to update pinned status card")
+	}
+}
+
+// forget stops updating a pinned status card
+func (u *PinUpdater) forget(ctx context.Context, pin *models.PinnedMessage, reason string) {
+	log.Info().
+		Int64("chat_id", pin.ChatID).
+		Int64("server_id", pin.ServerID).
+		Int("message_id", pin.MessageID).
+		Str("reason", reason).
+		Msg("stopping pinned status card updates")
+
+	if err := u.pins.Unpin(ctx, pin); err != nil {
+		log.Error().Err(err).Int64("chat_id", pin.ChatID).Msg("failed to forget pinned status card")
+	}
+}
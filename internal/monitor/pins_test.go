@@ -0,0 +1,102 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ykhdr/mss-bot/internal/minecraft"
+	"github.com/ykhdr/mss-bot/internal/service"
+	"github.com/ykhdr/mss-bot/internal/storage"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+// fakePins keeps pinned status cards in memory
+type fakePins struct {
+	mu   sync.Mutex
+	pins map[int64]*models.PinnedMessage
+}
+
+func (f *fakePins) ListPins(ctx context.Context) ([]*models.PinnedMessage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var pins []*models.PinnedMessage
+	for _, pin := range f.pins {
+		pins = append(pins, pin)
+	}
+	return pins, nil
+}
+
+func (f *fakePins) Unpin(ctx context.Context, pin *models.PinnedMessage) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.pins, pin.ID)
+	return nil
+}
+
+// fakeStatus reports every known server online
+type fakeStatus struct {
+	servers map[int64]*models.Server
+}
+
+func (f *fakeStatus) GetServerStatus(ctx context.Context, chatID, serverID int64) (*service.ServerStatusResult, error) {
+	server, ok := f.servers[serverID]
+	if !ok || server.ChatID != chatID {
+		return nil, storage.ErrNotFound{ChatID: chatID, ID: serverID}
+	}
+	return &service.ServerStatusResult{Server: server, Status: &minecraft.ServerStatus{Online: true}}, nil
+}
+
+// fakeEditor records attempted edits and fails for scripted messages
+type fakeEditor struct {
+	mu    sync.Mutex
+	edits map[int]string
+	fail  map[int]error
+}
+
+func (f *fakeEditor) EditMessage(ctx context.Context, chatID int64, messageID int, text string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.edits[messageID] = text
+	return f.fail[messageID]
+}
+
+func TestPinUpdater_Update(t *testing.T) {
+	pins := &fakePins{pins: map[int64]*models.PinnedMessage{
+		1: {ID: 1, ChatID: 111, ServerID: 1, MessageID: 10},
+		2: {ID: 2, ChatID: 111, ServerID: 2, MessageID: 20},
+		3: {ID: 3, ChatID: 222, ServerID: 3, MessageID: 30},
+		4: {ID: 4, ChatID: 222, ServerID: 4, MessageID: 40},
+	}}
+	status := &fakeStatus{servers: map[int64]*models.Server{
+		1: {ID: 1, ChatID: 111, IP: "one.example.com", Port: 25565},
+		2: {ID: 2, ChatID: 111, IP: "two.example.com", Port: 25565},
+		3: {ID: 3, ChatID: 222, IP: "three.example.com", Port: 25565},
+	}}
+	editor := &fakeEditor{
+		edits: make(map[int]string),
+		fail: map[int]error{
+			20: fmt.Errorf("%w: message to edit not found", ErrMessageGone),
+			30: errors.New("too many requests"),
+		},
+	}
+
+	settings := &fakeSettings{languages: map[int64]string{222: "en"}}
+	updater := NewPinUpdater(pins, status, settings, editor, time.Minute)
+	updater.now = func() time.Time { return time.Date(2024, 3, 5, 14, 7, 0, 0, time.UTC) }
+	updater.Update(context.Background())
+
+	assert.Contains(t, editor.edits[10], "one\\.example\\.com")
+	assert.Contains(t, editor.edits[10], "Обновлено: 05\\.03\\.2024 14:07")
+	assert.Contains(t, editor.edits[30], "Updated: 05\\.03\\.2024 14:07", "cards use the language of their chat")
+
+	assert.Contains(t, pins.pins, int64(1))
+	assert.NotContains(t, pins.pins, int64(2), "deleted cards are forgotten")
+	assert.Contains(t, pins.pins, int64(3), "cards are kept on transient errors")
+	assert.NotContains(t, pins.pins, int64(4), "cards of removed servers are forgotten")
+}
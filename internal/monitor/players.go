@@ -0,0 +1,70 @@
+package monitor
+
+import (
+	"sort"
+
+	"github.com/ykhdr/mss-bot/internal/minecraft"
+)
+
+// nilUUID is used by servers for fake sample entries such as MOTD lines
+const nilUUID = "00000000-0000-0000-0000-000000000000"
+
+// playerTracker remembers which players were last seen online on a server.
+//
+// Servers usually send only a truncated (and often shuffled) sample of up to
+// 12 players, which cannot tell who joined or left, so joins and leaves are
+// only reported between two complete samples. When the sample becomes complete
+// again, it is recorded as a new baseline.
+type playerTracker struct {
+	initialized bool
+	truncated   bool
+	known       map[string]struct{}
+}
+
+// update records the latest players info and returns who joined and left since the previous update.
+// The first update only records a baseline.
+func (t *playerTracker) update(players minecraft.PlayersInfo) (joined, left []string) {
+	current := make(map[string]struct{}, len(players.Sample))
+	for _, p := range players.Sample {
+		if p.Name == "" || p.UUID == nilUUID {
+			continue
+		}
+		current[p.Name] = struct{}{}
+	}
+	truncated := len(current) < players.Online
+
+	baseline := !t.initialized || t.truncated || truncated
+	t.initialized = true
+	t.truncated = truncated
+	if truncated {
+		t.known = nil
+		return nil, nil
+	}
+	if baseline {
+		t.known = current
+		return nil, nil
+	}
+
+	for name := range current {
+		if _, ok := t.known[name]; !ok {
+			joined = append(joined, name)
+		}
+	}
+	for name := range t.known {
+		if _, ok := current[name]; !ok {
+			left = append(left, name)
+		}
+	}
+	t.known = current
+
+	sort.Strings(joined)
+	sort.Strings(left)
+	return joined, left
+}
+
+// reset forgets the known players, e.g. when the server goes offline
+func (t *playerTracker) reset() {
+	t.initialized = false
+	t.truncated = false
+	t.known = nil
+}
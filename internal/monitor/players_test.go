@@ -0,0 +1,104 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ykhdr/mss-bot/internal/minecraft"
+)
+
+func playersInfo(online int, names ...string) minecraft.PlayersInfo {
+	info := minecraft.PlayersInfo{Online: online, Max: 100}
+	for _, name := range names {
+		info.Sample = append(info.Sample, minecraft.Player{Name: name, UUID: "uuid-" + name})
+	}
+	return info
+}
+
+func TestPlayerTracker_FirstUpdateIsBaseline(t *testing.T) {
+	var tracker playerTracker
+
+	joined, left := tracker.update(playersInfo(2, "Steve", "Alex"))
+
+	assert.Empty(t, joined)
+	assert.Empty(t, left)
+}
+
+func TestPlayerTracker_JoinAndLeave(t *testing.T) {
+	var tracker playerTracker
+	tracker.update(playersInfo(2, "Steve", "Alex"))
+
+	joined, left := tracker.update(playersInfo(2, "Steve", "Notch"))
+
+	assert.Equal(t, []string{"Notch"}, joined)
+	assert.Equal(t, []string{"Alex"}, left)
+}
+
+func TestPlayerTracker_EveryoneLeft(t *testing.T) {
+	var tracker playerTracker
+	tracker.update(playersInfo(2, "Steve", "Alex"))
+
+	joined, left := tracker.update(playersInfo(0))
+
+	assert.Empty(t, joined)
+	assert.Equal(t, []string{"Alex", "Steve"}, left)
+}
+
+func TestPlayerTracker_TruncatedSampleNoLeaves(t *testing.T) {
+	var tracker playerTracker
+	tracker.update(playersInfo(50, "Steve", "Alex"))
+
+	// A different slice of a big server's player list
+	joined, left := tracker.update(playersInfo(50, "Notch", "Herobrine"))
+
+	assert.Empty(t, joined, "shuffled sample with the same online count is not a join")
+	assert.Empty(t, left, "names missing from a truncated sample are not leaves")
+}
+
+func TestPlayerTracker_TruncatedSampleNoJoins(t *testing.T) {
+	var tracker playerTracker
+	tracker.update(playersInfo(50, "Steve"))
+
+	joined, left := tracker.update(playersInfo(51, "Alex", "Notch"))
+
+	assert.Empty(t, joined, "a reshuffled sample does not show who joined, even when the count grows")
+	assert.Empty(t, left)
+}
+
+func TestPlayerTracker_CompleteAfterTruncated(t *testing.T) {
+	var tracker playerTracker
+	tracker.update(playersInfo(2, "Steve", "Alex"))
+	tracker.update(playersInfo(20, "Steve", "Notch"))
+	tracker.update(playersInfo(20, "Herobrine", "Jeb"))
+
+	joined, left := tracker.update(playersInfo(2, "Steve", "Dinnerbone"))
+	assert.Empty(t, joined, "the first complete sample is a new baseline")
+	assert.Empty(t, left, "players seen in truncated samples are not reported as leaves")
+
+	joined, left = tracker.update(playersInfo(2, "Steve", "Alex"))
+	assert.Equal(t, []string{"Alex"}, joined)
+	assert.Equal(t, []string{"Dinnerbone"}, left)
+}
+
+func TestPlayerTracker_IgnoresFakeSampleEntries(t *testing.T) {
+	var tracker playerTracker
+	tracker.update(playersInfo(0))
+
+	info := playersInfo(1, "Steve")
+	info.Sample = append(info.Sample, minecraft.Player{Name: "§aWelcome!", UUID: nilUUID})
+	joined, _ := tracker.update(info)
+
+	assert.Equal(t, []string{"Steve"}, joined)
+}
+
+func TestPlayerTracker_Reset(t *testing.T) {
+	var tracker playerTracker
+	tracker.update(playersInfo(1, "Steve"))
+
+	tracker.reset()
+	joined, left := tracker.update(playersInfo(1, "Alex"))
+
+	assert.Empty(t, joined)
+	assert.Empty(t, left)
+}
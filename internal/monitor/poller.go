@@ -0,0 +1,244 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/minecraft"
+	"github.com/ykhdr/mss-bot/internal/service"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+// maxConcurrentChecks limits how many servers are queried at the same time
+const maxConcurrentChecks = 8
+
+// ServerSource provides the servers to poll and checks their status
+type ServerSource interface {
+	ListAllServers(ctx context.Context) ([]*models.Server, error)
+	CheckServer(ctx context.Context, server *models.Server) *service.ServerStatusResult
+}
+
+// SettingsSource provides per-chat settings
+type SettingsSource interface {
+	GetSettings(ctx context.Context, chatID int64) (*models.ChatSettings, error)
+}
+
+// Notifier delivers MarkdownV2 formatted messages to chats
+type Notifier interface {
+	Notify(ctx context.Context, chatID int64, text string) error
+}
+
+// Poller periodically checks every stored server and alerts the owning chat
+// when a server goes offline or comes back online, or when players join or leave.
+type Poller struct {
+	source           ServerSource
+	settings         SettingsSource
+	notifier         Notifier
+	interval         time.Duration
+	failureThreshold int
+
+	mu     sync.Mutex
+	states map[int64]*serverState
+}
+
+// NewPoller creates a new status poller.
+// A server is reported offline only after failureThreshold consecutive failed checks.
+func NewPoller(
+	source ServerSource,
+	settings SettingsSource,
+	notifier Notifier,
+	interval time.Duration,
+	failureThreshold int,
+) *Poller {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+
+	return &Poller{
+		source:           source,
+		settings:         settings,
+		notifier:         notifier,
+		interval:         interval,
+		failureThreshold: failureThreshold,
+		states:           make(map[int64]*serverState),
+	}
+}
+
+// Run polls servers until the context is canceled
+func (p *Poller) Run(ctx context.Context) {
+	log.Info().Dur("interval", p.interval).Int("failure_threshold", p.failureThreshold).Msg("starting status poller")
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.Poll(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("status poller stopped")
+			return
+		case <-ticker.C:
+			p.Poll(ctx)
+		}
+	}
+}
+
+// Poll checks every stored server once and sends alerts for state transitions
+func (p *Poller) Poll(ctx context.Context) {
+	servers, err := p.source.ListAllServers(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list servers for polling")
+		return
+	}
+
+	log.Debug().Int("servers", len(servers)).Msg("polling servers")
+	p.forgetRemoved(servers)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentChecks)
+	for _, server := range servers {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(server *models.Server) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			p.check(ctx, server)
+		}(server)
+	}
+	wg.Wait()
+}
+
+func (p *Poller) check(ctx context.Context, server *models.Server) {
+	result := p.source.CheckServer(ctx, server)
+	if ctx.Err() != nil {
+		return
+	}
+
+	changed, joined, left := p.observe(server.ID, result.Status)
+
+	if changed {
+		log.Info().
+			Int64("chat_id", server.ChatID).
+			Int64("server_id", server.ID).
+			Bool("online", result.Status.Online).
+			Msg("server state changed")
+
+		text := result.FormatTransition(chatLanguage(ctx, p.settings, server.ChatID))
+		if err := p.notifier.Notify(ctx, server.ChatID, text); err != nil {
+			log.Error().Err(err).Int64("chat_id", server.ChatID).Msg("failed to send status alert")
+		}
+	}
+
+	if len(joined) > 0 || len(left) > 0 {
+		p.notifyPlayers(ctx, server, joined, left)
+	}
+}
+
+// notifyPlayers sends a batched join/leave message if the chat opted in
+func (p *Poller) notifyPlayers(ctx context.Context, server *models.Server, joined, left []string) {
+	settings, err := p.settings.GetSettings(ctx, server.ChatID)
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", server.ChatID).Msg("failed to get chat settings")
+		return
+	}
+	if !settings.PlayerNotifications {
+		return
+	}
+
+	log.Debug().
+		Int64("chat_id", server.ChatID).
+		Int64("server_id", server.ID).
+		Strs("joined", joined).
+		Strs("left", left).
+		Msg("players changed")
+
+	text := service.FormatPlayerChanges(i18n.Resolve(settings.Language, ""), server, joined, left)
+	if err := p.notifier.Notify(ctx, server.ChatID, text); err != nil {
+		log.Error().Err(err).Int64("chat_id", server.ChatID).Msg("failed to send player notification")
+	}
+}
+
+// chatLanguage returns the language chosen for a chat, or the default language when none was chosen
+func chatLanguage(ctx context.Context, settings SettingsSource, chatID int64) i18n.Lang {
+	chat, err := settings.GetSettings(ctx, chatID)
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Msg("failed to get chat settings")
+		return i18n.Default
+	}
+	return i18n.Resolve(chat.Language, "")
+}
+
+// observe records a check result. It reports whether the confirmed online state
+// changed and which players joined or left since the previous successful check.
+func (p *Poller) observe(serverID int64, status *minecraft.ServerStatus) (changed bool, joined, left []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state, ok := p.states[serverID]
+	if !ok {
+		state = &serverState{}
+		p.states[serverID] = state
+	}
+
+	changed = state.observe(status.Online, p.failureThreshold)
+	switch {
+	case status.Online:
+		joined, left = state.players.update(status.Players)
+	case state.known && !state.online:
+		state.players.reset()
+	}
+
+	return changed, joined, left
+}
+
+// forgetRemoved drops the state of servers that are no longer stored
+func (p *Poller) forgetRemoved(servers []*models.Server) {
+	present := make(map[int64]struct{}, len(servers))
+	for _, server := range servers {
+		present[server.ID] = struct{}{}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for id := range p.states {
+		if _, ok := present[id]; !ok {
+			delete(p.states, id)
+		}
+	}
+}
+
+// serverState is the last confirmed state of a server
+type serverState struct {
+	known    bool
+	online   bool
+	failures int
+	players  playerTracker
+}
+
+// observe records a check result and reports whether the confirmed state changed.
+// The first confirmed state is recorded silently, and a server is considered offline
+// only after threshold consecutive failures.
+func (s *serverState) observe(online bool, threshold int) bool {
+	if online {
+		s.failures = 0
+		changed := s.known && !s.online
+		s.known = true
+		s.online = true
+		return changed
+	}
+
+	s.failures++
+	if s.failures < threshold {
+		return false
+	}
+
+	changed := s.known && s.online
+	s.known = true
+	s.online = false
+	return changed
+}
@@ -0,0 +1,273 @@
+package monitor
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ykhdr/mss-bot/internal/minecraft"
+	"github.com/ykhdr/mss-bot/internal/minecraft/mctest"
+	"github.com/ykhdr/mss-bot/internal/service"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+	"github.com/ykhdr/mss-bot/internal/storage/sqlite"
+)
+
+// fakeSource returns scripted online states for its servers
+type fakeSource struct {
+	mu      sync.Mutex
+	servers []*models.Server
+	online  map[int64]bool
+	players map[int64]minecraft.PlayersInfo
+}
+
+func (f *fakeSource) ListAllServers(ctx context.Context) ([]*models.Server, error) {
+	return f.servers, nil
+}
+
+func (f *fakeSource) CheckServer(ctx context.Context, server *models.Server) *service.ServerStatusResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &service.ServerStatusResult{
+		Server: server,
+		Status: &minecraft.ServerStatus{Online: f.online[server.ID], Players: f.players[server.ID]},
+	}
+}
+
+func (f *fakeSource) setPlayers(serverID int64, names ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	info := minecraft.PlayersInfo{Online: len(names), Max: 20}
+	for _, name := range names {
+		info.Sample = append(info.Sample, minecraft.Player{Name: name, UUID: "uuid-" + name})
+	}
+	f.players[serverID] = info
+}
+
+func (f *fakeSource) set(serverID int64, online bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.online[serverID] = online
+}
+
+// fakeNotifier records sent messages
+type fakeNotifier struct {
+	mu       sync.Mutex
+	messages map[int64][]string
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, chatID int64, text string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages[chatID] = append(f.messages[chatID], text)
+	return nil
+}
+
+// fakeSettings enables player notifications and sets the language of the listed chats
+type fakeSettings struct {
+	playerNotifications map[int64]bool
+	languages           map[int64]string
+}
+
+func (f *fakeSettings) GetSettings(ctx context.Context, chatID int64) (*models.ChatSettings, error) {
+	return &models.ChatSettings{
+		ChatID:              chatID,
+		PlayerNotifications: f.playerNotifications[chatID],
+		Language:            f.languages[chatID],
+	}, nil
+}
+
+func newTestPoller(threshold int) (*Poller, *fakeSource, *fakeNotifier) {
+	source := &fakeSource{
+		servers: []*models.Server{{ID: 1, ChatID: 100, IP: "mc.example.com", Port: 25565, Name: "Test"}},
+		online:  map[int64]bool{1: true},
+		players: make(map[int64]minecraft.PlayersInfo),
+	}
+	settings := &fakeSettings{playerNotifications: map[int64]bool{100: true}}
+	notifier := &fakeNotifier{messages: make(map[int64][]string)}
+	return NewPoller(source, settings, notifier, time.Minute, threshold), source, notifier
+}
+
+func TestPoller_FirstPollIsSilent(t *testing.T) {
+	p, _, notifier := newTestPoller(1)
+
+	p.Poll(context.Background())
+
+	assert.Empty(t, notifier.messages)
+}
+
+func TestPoller_OfflineAfterThreshold(t *testing.T) {
+	p, source, notifier := newTestPoller(3)
+	ctx := context.Background()
+
+	p.Poll(ctx)
+	source.set(1, false)
+
+	p.Poll(ctx)
+	p.Poll(ctx)
+	assert.Empty(t, notifier.messages[100])
+
+	p.Poll(ctx)
+	require.Len(t, notifier.messages[100], 1)
+	assert.Contains(t, notifier.messages[100][0], "🔴")
+
+	// Staying offline does not repeat the alert
+	p.Poll(ctx)
+	assert.Len(t, notifier.messages[100], 1)
+}
+
+func TestPoller_BackOnline(t *testing.T) {
+	p, source, notifier := newTestPoller(1)
+	ctx := context.Background()
+
+	p.Poll(ctx)
+	source.set(1, false)
+	p.Poll(ctx)
+	source.set(1, true)
+	p.Poll(ctx)
+
+	require.Len(t, notifier.messages[100], 2)
+	assert.Contains(t, notifier.messages[100][1], "🟢")
+}
+
+func TestPoller_FlappingBelowThreshold(t *testing.T) {
+	p, source, notifier := newTestPoller(2)
+	ctx := context.Background()
+
+	p.Poll(ctx)
+	for i := 0; i < 3; i++ {
+		source.set(1, false)
+		p.Poll(ctx)
+		source.set(1, true)
+		p.Poll(ctx)
+	}
+
+	assert.Empty(t, notifier.messages)
+}
+
+func TestPoller_ForgetsRemovedServers(t *testing.T) {
+	p, source, _ := newTestPoller(1)
+	ctx := context.Background()
+
+	p.Poll(ctx)
+	assert.Len(t, p.states, 1)
+
+	source.servers = nil
+	p.Poll(ctx)
+	assert.Empty(t, p.states)
+}
+
+func TestPoller_PlayerChangesBatched(t *testing.T) {
+	p, source, notifier := newTestPoller(1)
+	ctx := context.Background()
+
+	source.setPlayers(1, "Steve")
+	p.Poll(ctx)
+	assert.Empty(t, notifier.messages)
+
+	source.setPlayers(1, "Alex", "Notch")
+	p.Poll(ctx)
+
+	require.Len(t, notifier.messages[100], 1)
+	assert.Contains(t, notifier.messages[100][0], "Зашли: Alex, Notch")
+	assert.Contains(t, notifier.messages[100][0], "Вышли: Steve")
+}
+
+func TestPoller_ChatLanguage(t *testing.T) {
+	p, source, notifier := newTestPoller(1)
+	p.settings = &fakeSettings{
+		playerNotifications: map[int64]bool{100: true},
+		languages:           map[int64]string{100: "en"},
+	}
+	ctx := context.Background()
+
+	source.setPlayers(1, "Steve")
+	p.Poll(ctx)
+	source.setPlayers(1, "Alex")
+	p.Poll(ctx)
+	source.set(1, false)
+	p.Poll(ctx)
+
+	require.Len(t, notifier.messages[100], 2)
+	assert.Contains(t, notifier.messages[100][0], "Joined: Alex")
+	assert.Contains(t, notifier.messages[100][1], "stopped responding")
+}
+
+func TestPoller_PlayerChangesRequireOptIn(t *testing.T) {
+	p, source, notifier := newTestPoller(1)
+	p.settings = &fakeSettings{}
+	ctx := context.Background()
+
+	source.setPlayers(1, "Steve")
+	p.Poll(ctx)
+	source.setPlayers(1, "Alex")
+	p.Poll(ctx)
+
+	assert.Empty(t, notifier.messages)
+}
+
+func TestPoller_NoLeavesWhenServerGoesOffline(t *testing.T) {
+	p, source, notifier := newTestPoller(1)
+	ctx := context.Background()
+
+	source.setPlayers(1, "Steve")
+	p.Poll(ctx)
+	source.set(1, false)
+	p.Poll(ctx)
+	source.set(1, true)
+	p.Poll(ctx)
+
+	require.Len(t, notifier.messages[100], 2)
+	assert.NotContains(t, notifier.messages[100][0], "Вышли")
+	assert.NotContains(t, notifier.messages[100][1], "Зашли")
+}
+
+func TestPoller_FakeServerGoesOffline(t *testing.T) {
+	server := mctest.NewServer(mctest.WithStatus(mctest.Status{Version: "1.20.4", Protocol: 765, MaxPlayers: 20}))
+	defer server.Close()
+
+	storage, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer storage.Close()
+
+	client := minecraft.NewClient(200 * time.Millisecond)
+	servers := service.NewServerService(storage, storage, service.StatusProviders{
+		models.EditionJava: minecraft.JavaClient{Client: client},
+	}, client)
+	ctx := context.Background()
+	require.NoError(t, servers.SetServerConfig(ctx, 100, 0, models.EditionJava, server.Host, server.Port, "Fake"))
+
+	notifier := &fakeNotifier{messages: make(map[int64][]string)}
+	p := NewPoller(servers, &fakeSettings{}, notifier, time.Minute, 2)
+
+	p.Poll(ctx)
+	server.SetResponse(mctest.Response{Failure: mctest.FailHang})
+	p.Poll(ctx)
+	assert.Empty(t, notifier.messages[100])
+
+	p.Poll(ctx)
+	require.Len(t, notifier.messages[100], 1)
+	assert.Contains(t, notifier.messages[100][0], "🔴")
+
+	server.SetResponse(mctest.Response{})
+	p.Poll(ctx)
+	require.Len(t, notifier.messages[100], 2)
+	assert.Contains(t, notifier.messages[100][1], "🟢")
+}
+
+func TestServerState_Observe(t *testing.T) {
+	var s serverState
+
+	assert.False(t, s.observe(false, 2))
+	assert.False(t, s.known)
+	assert.False(t, s.observe(false, 2))
+	assert.True(t, s.known)
+	assert.False(t, s.online)
+
+	assert.True(t, s.observe(true, 2))
+	assert.Equal(t, 0, s.failures)
+}
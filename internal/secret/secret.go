@@ -0,0 +1,78 @@
+// Package secret encrypts credentials kept in the database, such as RCON passwords.
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the length of keys in bytes; they select AES-256.
+const KeySize = 32
+
+// ErrDecrypt is returned when a value was not encrypted with the key or was tampered with.
+var ErrDecrypt = errors.New("failed to decrypt secret")
+
+// Box encrypts and decrypts values with AES-GCM.
+// Encrypted values are base64 text holding a random nonce followed by the sealed value.
+type Box struct {
+	aead cipher.AEAD
+}
+
+// NewBox creates a box from a KeySize byte key.
+func NewBox(key []byte) (*Box, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid key length: %d bytes, want %d", len(key), KeySize)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return &Box{aead: aead}, nil
+}
+
+// ParseKey decodes a base64 key, as generated by `openssl rand -base64 32`.
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("key is not valid base64: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid key length: %d bytes, want %d", len(key), KeySize)
+	}
+	return key, nil
+}
+
+// Encrypt seals a value. Encrypting the same value twice gives different results.
+func (b *Box) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := b.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value sealed by Encrypt.
+func (b *Box) Decrypt(encrypted string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil || len(sealed) < b.aead.NonceSize() {
+		return "", ErrDecrypt
+	}
+
+	nonce, ciphertext := sealed[:b.aead.NonceSize()], sealed[b.aead.NonceSize():]
+	plaintext, err := b.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,65 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBox(t *testing.T, fill byte) *Box {
+	t.Helper()
+	box, err := NewBox(bytes.Repeat([]byte{fill}, KeySize))
+	require.NoError(t, err)
+	return box
+}
+
+func TestBox_RoundTrip(t *testing.T) {
+	box := testBox(t, 1)
+
+	encrypted, err := box.Encrypt("hunter2")
+	require.NoError(t, err)
+	assert.NotContains(t, encrypted, "hunter2")
+
+	again, err := box.Encrypt("hunter2")
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, again, "nonces are random")
+
+	plaintext, err := box.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", plaintext)
+}
+
+func TestBox_DecryptRejectsForeignValues(t *testing.T) {
+	encrypted, err := testBox(t, 1).Encrypt("hunter2")
+	require.NoError(t, err)
+
+	_, err = testBox(t, 2).Decrypt(encrypted)
+	assert.ErrorIs(t, err, ErrDecrypt, "another key")
+
+	sealed, _ := base64.StdEncoding.DecodeString(encrypted)
+	sealed[len(sealed)-1] ^= 0xff
+	_, err = testBox(t, 1).Decrypt(base64.StdEncoding.EncodeToString(sealed))
+	assert.ErrorIs(t, err, ErrDecrypt, "tampered value")
+
+	for _, value := range []string{"", "not base64!", "AAAA"} {
+		_, err = testBox(t, 1).Decrypt(value)
+		assert.ErrorIs(t, err, ErrDecrypt, value)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	key, err := ParseKey(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, KeySize)))
+	require.NoError(t, err)
+	assert.Len(t, key, KeySize)
+
+	_, err = ParseKey(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.Error(t, err)
+	_, err = ParseKey("not base64!")
+	assert.Error(t, err)
+
+	_, err = NewBox([]byte("short"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/storage"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+// ChatService provides business logic for per-chat settings.
+type ChatService struct {
+	storage storage.ChatSettingsStorage
+}
+
+// NewChatService creates a new chat settings service.
+func NewChatService(storage storage.ChatSettingsStorage) *ChatService {
+	return &ChatService{
+		storage: storage,
+	}
+}
+
+// GetSettings returns the settings of a chat.
+func (s *ChatService) GetSettings(ctx context.Context, chatID int64) (*models.ChatSettings, error) {
+	log.Debug().Int64("chat_id", chatID).Msg("getting chat settings")
+	return s.storage.GetChatSettings(ctx, chatID)
+}
+
+// TogglePlayerNotifications switches player join/leave notifications for a chat.
+func (s *ChatService) TogglePlayerNotifications(ctx context.Context, chatID int64) (*models.ChatSettings, error) {
+	settings, err := s.storage.GetChatSettings(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings.PlayerNotifications = !settings.PlayerNotifications
+	log.Info().
+		Int64("chat_id", chatID).
+		Bool("player_notifications", settings.PlayerNotifications).
+		Msg("toggling player notifications")
+
+	if err := s.storage.UpsertChatSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// ToggleAllowEveryone switches whether every member of a group, not only its admins, may configure the bot.
+func (s *ChatService) ToggleAllowEveryone(ctx context.Context, chatID int64) (*models.ChatSettings, error) {
+	settings, err := s.storage.GetChatSettings(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings.AllowEveryone = !settings.AllowEveryone
+	log.Info().Int64("chat_id", chatID).Bool("allow_everyone", settings.AllowEveryone).Msg("toggling configuration access")
+
+	if err := s.storage.UpsertChatSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SetLanguage sets the language of a chat, used instead of the language of each user.
+func (s *ChatService) SetLanguage(ctx context.Context, chatID int64, lang i18n.Lang) (*models.ChatSettings, error) {
+	settings, err := s.storage.GetChatSettings(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings.Language = string(lang)
+	log.Info().Int64("chat_id", chatID).Str("language", settings.Language).Msg("setting chat language")
+
+	if err := s.storage.UpsertChatSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
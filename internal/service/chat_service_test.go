@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+// MockChatSettingsStorage is a mock implementation of storage.ChatSettingsStorage
+type MockChatSettingsStorage struct {
+	settings map[int64]*models.ChatSettings
+}
+
+func NewMockChatSettingsStorage() *MockChatSettingsStorage {
+	return &MockChatSettingsStorage{
+		settings: make(map[int64]*models.ChatSettings),
+	}
+}
+
+func (m *MockChatSettingsStorage) GetChatSettings(ctx context.Context, chatID int64) (*models.ChatSettings, error) {
+	settings, ok := m.settings[chatID]
+	if !ok {
+		return models.DefaultChatSettings(chatID), nil
+	}
+	copied := *settings
+	return &copied, nil
+}
+
+func (m *MockChatSettingsStorage) UpsertChatSettings(ctx context.Context, settings *models.ChatSettings) error {
+	copied := *settings
+	m.settings[settings.ChatID] = &copied
+	return nil
+}
+
+func TestChatService_GetSettings_Defaults(t *testing.T) {
+	service := NewChatService(NewMockChatSettingsStorage())
+
+	settings, err := service.GetSettings(context.Background(), 12345)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(12345), settings.ChatID)
+	assert.False(t, settings.PlayerNotifications)
+}
+
+func TestChatService_TogglePlayerNotifications(t *testing.T) {
+	service := NewChatService(NewMockChatSettingsStorage())
+	ctx := context.Background()
+
+	settings, err := service.TogglePlayerNotifications(ctx, 12345)
+	require.NoError(t, err)
+	assert.True(t, settings.PlayerNotifications)
+
+	settings, err = service.GetSettings(ctx, 12345)
+	require.NoError(t, err)
+	assert.True(t, settings.PlayerNotifications)
+
+	settings, err = service.TogglePlayerNotifications(ctx, 12345)
+	require.NoError(t, err)
+	assert.False(t, settings.PlayerNotifications)
+}
+
+func TestChatService_ToggleAllowEveryone(t *testing.T) {
+	service := NewChatService(NewMockChatSettingsStorage())
+	ctx := context.Background()
+
+	settings, err := service.ToggleAllowEveryone(ctx, -100123)
+	require.NoError(t, err)
+	assert.True(t, settings.AllowEveryone)
+
+	settings, err = service.GetSettings(ctx, -100123)
+	require.NoError(t, err)
+	assert.True(t, settings.AllowEveryone)
+
+	settings, err = service.ToggleAllowEveryone(ctx, -100123)
+	require.NoError(t, err)
+	assert.False(t, settings.AllowEveryone)
+}
+
+func TestChatService_SetLanguage(t *testing.T) {
+	service := NewChatService(NewMockChatSettingsStorage())
+	ctx := context.Background()
+
+	_, err := service.TogglePlayerNotifications(ctx, 12345)
+	require.NoError(t, err)
+
+	settings, err := service.SetLanguage(ctx, 12345, i18n.EN)
+	require.NoError(t, err)
+	assert.Equal(t, "en", settings.Language)
+
+	settings, err = service.GetSettings(ctx, 12345)
+	require.NoError(t, err)
+	assert.Equal(t, "en", settings.Language)
+	assert.True(t, settings.PlayerNotifications, "other settings are kept")
+}
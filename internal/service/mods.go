@@ -0,0 +1,62 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+)
+
+// modsPerPage is the number of mods on one page of the mod list
+const modsPerPage = 20
+
+// ModPages returns the number of pages of the server's mod list, zero when it advertises no mods.
+func (r *ServerStatusResult) ModPages() int {
+	if r.Status == nil || r.Status.Mods == nil || len(r.Status.Mods.Mods) == 0 {
+		return 0
+	}
+	return (len(r.Status.Mods.Mods) + modsPerPage - 1) / modsPerPage
+}
+
+// FormatMods formats a page of the server's mod list for display. Pages are numbered from zero
+// and clamped to the available ones.
+func (r *ServerStatusResult) FormatMods(lang i18n.Lang, page int) string {
+	title := lang.T("mods.title", EscapeMarkdown(ServerTitle(r.Server))) + "\n\n"
+
+	pages := r.ModPages()
+	if pages == 0 {
+		return title + lang.T("mods.none")
+	}
+	page = max(0, min(page, pages-1))
+
+	info := r.Status.Mods
+	var sb strings.Builder
+	sb.WriteString(title)
+	sb.WriteString(lang.T("mods.loader", EscapeMarkdown(string(info.Loader))) + "\n")
+	sb.WriteString(lang.N("mods.count", len(info.Mods)) + "\n\n")
+
+	end := min((page+1)*modsPerPage, len(info.Mods))
+	for _, mod := range info.Mods[page*modsPerPage : end] {
+		if mod.Version == "" {
+			fmt.Fprintf(&sb, "• %s\n", EscapeMarkdown(mod.Name))
+		} else {
+			fmt.Fprintf(&sb, "• %s `%s`\n", EscapeMarkdown(mod.Name), EscapeMarkdown(mod.Version))
+		}
+	}
+
+	if info.Truncated {
+		sb.WriteString("\n" + lang.T("mods.truncated") + "\n")
+	}
+	if pages > 1 {
+		sb.WriteString("\n" + lang.T("page", page+1, pages))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// formatModsSummary formats the mod count of a modded server as a line with a trailing newline.
+func (r *ServerStatusResult) formatModsSummary(lang i18n.Lang) string {
+	if r.ModPages() == 0 {
+		return ""
+	}
+	return lang.T("mods.summary", len(r.Status.Mods.Mods), EscapeMarkdown(string(r.Status.Mods.Loader))) + "\n"
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/minecraft"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+func modsResult(count int, truncated bool) *ServerStatusResult {
+	mods := &minecraft.ModInfo{Loader: minecraft.ModLoaderForge, Truncated: truncated}
+	for i := 0; i < count; i++ {
+		mods.Mods = append(mods.Mods, minecraft.Mod{Name: fmt.Sprintf("mod%02d", i), Version: "1.0"})
+	}
+
+	return &ServerStatusResult{
+		Server: &models.Server{IP: "mc.example.com", Port: 25565, Name: "Modpack"},
+		Status: &minecraft.ServerStatus{Online: true, Version: "1.20.1", Mods: mods},
+	}
+}
+
+func TestServerStatusResult_ModPages(t *testing.T) {
+	assert.Equal(t, 0, (&ServerStatusResult{Status: &minecraft.ServerStatus{}}).ModPages())
+	assert.Equal(t, 0, modsResult(0, false).ModPages())
+	assert.Equal(t, 1, modsResult(modsPerPage, false).ModPages())
+	assert.Equal(t, 2, modsResult(modsPerPage+1, false).ModPages())
+}
+
+func TestServerStatusResult_FormatMods(t *testing.T) {
+	result := modsResult(modsPerPage+5, true)
+	result.Status.Mods.Mods[0] = minecraft.Mod{Name: "spark"}
+
+	first := result.FormatMods(i18n.RU, 0)
+	assert.Contains(t, first, "🧩 *Моды Modpack*")
+	assert.Contains(t, first, "Загрузчик: Forge\nВсего: 25")
+	assert.Contains(t, first, "• spark\n")
+	assert.Contains(t, first, "• mod01 `1\\.0`")
+	assert.NotContains(t, first, "mod20")
+	assert.Contains(t, first, "не весь список модов")
+	assert.Contains(t, first, "Страница 1/2")
+
+	second := result.FormatMods(i18n.RU, 1)
+	assert.Contains(t, second, "mod20")
+	assert.NotContains(t, second, "mod19")
+	assert.Contains(t, second, "Страница 2/2")
+
+	// Out of range pages are clamped
+	assert.Equal(t, second, result.FormatMods(i18n.RU, 7))
+	assert.Equal(t, first, result.FormatMods(i18n.RU, -1))
+}
+
+func TestServerStatusResult_FormatMods_NoMods(t *testing.T) {
+	result := modsResult(0, false)
+
+	assert.Contains(t, result.FormatMods(i18n.RU, 0), "Сервер не сообщает о модах")
+}
+
+func TestServerStatusResult_FormatStatus_ModsSummary(t *testing.T) {
+	assert.Contains(t, modsResult(3, false).FormatStatus(i18n.RU), "Моды: 3 \\(Forge\\)\n")
+	assert.NotContains(t, modsResult(0, false).FormatStatus(i18n.RU), "Моды:")
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/ykhdr/mss-bot/internal/minecraft"
+)
+
+// formatMOTD renders a MOTD as MarkdownV2, one trimmed line per MOTD line.
+// Telegram has no colored text, so colors are dropped; obfuscated text becomes a spoiler.
+func formatMOTD(text minecraft.ChatText) string {
+	var lines []string
+	for _, line := range text.Lines() {
+		line = line.TrimSpace()
+		if len(line) == 0 {
+			continue
+		}
+
+		var sb strings.Builder
+		prevClose := ""
+		for _, span := range line {
+			open, closing := styleMarkers(span.Style)
+			// "_" next to "__" is ambiguous, so an empty bold entity separates them
+			if strings.HasSuffix(prevClose, "_") && strings.HasPrefix(open, "_") {
+				sb.WriteString("**")
+			}
+			sb.WriteString(open)
+			sb.WriteString(EscapeMarkdown(span.Text))
+			sb.WriteString(closing)
+			prevClose = closing
+		}
+		lines = append(lines, sb.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// styleMarkers returns the MarkdownV2 markers opening and closing a text style.
+func styleMarkers(style minecraft.TextStyle) (open, closing string) {
+	var markers []string
+	if style.Obfuscated {
+		markers = append(markers, "||")
+	}
+	if style.Strikethrough {
+		markers = append(markers, "~")
+	}
+	if style.Underlined {
+		markers = append(markers, "__")
+	}
+	if style.Bold {
+		markers = append(markers, "*")
+	}
+	if style.Italic {
+		markers = append(markers, "_")
+	}
+
+	for i := range markers {
+		open += markers[i]
+		closing += markers[len(markers)-1-i]
+	}
+	return open, closing
+}
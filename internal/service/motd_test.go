@@ -0,0 +1,34 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ykhdr/mss-bot/internal/minecraft"
+)
+
+func TestFormatMOTD(t *testing.T) {
+	tests := []struct {
+		name string
+		motd string
+		want string
+	}{
+		{"plain", "A Minecraft Server", "A Minecraft Server"},
+		{"escaped", "Server v1.2 [EU]!", "Server v1\\.2 \\[EU\\]\\!"},
+		{"colors dropped", "§aGreen §cRed", "Green Red"},
+		{"bold", "§lBold§r text", "*Bold* text"},
+		{"nested", "§l§oBoth§r", "*_Both_*"},
+		{"all styles", "§k§m§n§l§oall", "||~__*_all_*__~||"},
+		{"underline then italic", "§nu§r§oi", "__u__**_i_"},
+		{"lines trimmed", "   §6Centered   \n§7  second  ", "Centered\nsecond"},
+		{"blank lines skipped", "first\n   \n", "first"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatMOTD(minecraft.ParseLegacyText(tt.motd)))
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/minecraft"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+// FormatTransition formats an alert about the server going offline or coming back online.
+func (r *ServerStatusResult) FormatTransition(lang i18n.Lang) string {
+	serverName := EscapeMarkdown(ServerTitle(r.Server))
+	address := minecraft.FormatAddress(r.Server.IP, r.Server.Port)
+
+	if !r.Status.Online {
+		return lang.T("notify.offline", serverName, address, r.formatReason(lang))
+	}
+
+	return lang.T("notify.online",
+		serverName,
+		address,
+		r.Status.Players.Online,
+		r.Status.Players.Max,
+	)
+}
+
+// FormatPlayerChanges formats a single message listing players who joined and left a server.
+func FormatPlayerChanges(lang i18n.Lang, server *models.Server, joined, left []string) string {
+	var sb strings.Builder
+	sb.WriteString("👥 *" + EscapeMarkdown(ServerTitle(server)) + "*\n")
+
+	if len(joined) > 0 {
+		sb.WriteString("\n" + lang.T("notify.joined", EscapeMarkdown(strings.Join(joined, ", "))))
+	}
+	if len(left) > 0 {
+		sb.WriteString("\n" + lang.T("notify.left", EscapeMarkdown(strings.Join(left, ", "))))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/minecraft"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+func TestServerStatusResult_FormatTransition_Offline(t *testing.T) {
+	result := &ServerStatusResult{
+		Server: &models.Server{IP: "mc.example.com", Port: 25565, Name: "Test Server"},
+		Status: &minecraft.ServerStatus{Online: false},
+	}
+
+	formatted := result.FormatTransition(i18n.RU)
+	assert.Contains(t, formatted, "🔴")
+	assert.Contains(t, formatted, "Test Server")
+	assert.Contains(t, formatted, "перестал отвечать")
+}
+
+func TestServerStatusResult_FormatTransition_OfflineReason(t *testing.T) {
+	result := &ServerStatusResult{
+		Server: &models.Server{IP: "mc.example.com", Port: 25565},
+		Status: &minecraft.ServerStatus{Online: false},
+		Error:  &minecraft.StatusError{Kind: minecraft.ErrorKindTimeout, Err: context.DeadlineExceeded},
+	}
+
+	formatted := result.FormatTransition(i18n.RU)
+	assert.Contains(t, formatted, "Причина: сервер не ответил вовремя")
+}
+
+func TestServerStatusResult_FormatTransition_Online(t *testing.T) {
+	result := &ServerStatusResult{
+		Server: &models.Server{IP: "mc.example.com", Port: 25566},
+		Status: &minecraft.ServerStatus{
+			Online:  true,
+			Players: minecraft.PlayersInfo{Online: 3, Max: 20},
+		},
+	}
+
+	formatted := result.FormatTransition(i18n.RU)
+	assert.Contains(t, formatted, "🟢")
+	assert.Contains(t, formatted, "mc\\.example\\.com:25566")
+	assert.Contains(t, formatted, "3/20")
+}
+
+func TestFormatPlayerChanges(t *testing.T) {
+	server := &models.Server{IP: "mc.example.com", Port: 25565, Name: "Survival"}
+
+	formatted := FormatPlayerChanges(i18n.RU, server, []string{"Steve", "Alex_2"}, []string{"Notch"})
+
+	assert.Contains(t, formatted, "Survival")
+	assert.Contains(t, formatted, "Зашли: Steve, Alex\\_2")
+	assert.Contains(t, formatted, "Вышли: Notch")
+}
+
+func TestFormatPlayerChanges_JoinOnly(t *testing.T) {
+	server := &models.Server{IP: "mc.example.com", Port: 25565}
+
+	formatted := FormatPlayerChanges(i18n.RU, server, []string{"Steve"}, nil)
+
+	assert.Contains(t, formatted, "Зашли: Steve")
+	assert.NotContains(t, formatted, "Вышли")
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/storage"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+// pinnedTimeLayout is how the time of the last update is shown on pinned status cards
+const pinnedTimeLayout = "02.01.2006 15:04 MST"
+
+// PinService keeps track of pinned status cards, which are edited in place with the latest status.
+type PinService struct {
+	storage storage.PinnedMessageStorage
+}
+
+// NewPinService creates a new pinned status card service.
+func NewPinService(storage storage.PinnedMessageStorage) *PinService {
+	return &PinService{
+		storage: storage,
+	}
+}
+
+// Pin records the pinned status card of a server in a chat and returns the card it replaces, if any.
+func (s *PinService) Pin(ctx context.Context, chatID, serverID int64, messageID int) (*models.PinnedMessage, error) {
+	log.Info().
+		Int64("chat_id", chatID).
+		Int64("server_id", serverID).
+		Int("message_id", messageID).
+		Msg("pinning status card")
+
+	previous, err := s.storage.GetPinnedMessage(ctx, chatID, serverID)
+	if err != nil {
+		return nil, err
+	}
+
+	pin := &models.PinnedMessage{ChatID: chatID, ServerID: serverID, MessageID: messageID}
+	if err := s.storage.UpsertPinnedMessage(ctx, pin); err != nil {
+		return nil, err
+	}
+	return previous, nil
+}
+
+// ListPins returns the pinned status cards of all chats.
+func (s *PinService) ListPins(ctx context.Context) ([]*models.PinnedMessage, error) {
+	log.Debug().Msg("listing pinned status cards")
+	return s.storage.ListPinnedMessages(ctx)
+}
+
+// Unpin stops updating a pinned status card.
+func (s *PinService) Unpin(ctx context.Context, pin *models.PinnedMessage) error {
+	log.Info().
+		Int64("chat_id", pin.ChatID).
+		Int64("server_id", pin.ServerID).
+		Int("message_id", pin.MessageID).
+		Msg("unpinning status card")
+	return s.storage.DeletePinnedMessage(ctx, pin.ID)
+}
+
+// FormatPinnedStatus formats the server status for a pinned status card, noting when it was updated.
+func (r *ServerStatusResult) FormatPinnedStatus(lang i18n.Lang, updatedAt time.Time) string {
+	return r.FormatStatus(lang) + "\n\n" + lang.T("pin.updated", EscapeMarkdown(updatedAt.Format(pinnedTimeLayout)))
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/minecraft"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+// MockPinnedMessageStorage is a mock implementation of storage.PinnedMessageStorage
+type MockPinnedMessageStorage struct {
+	pins   map[int64]*models.PinnedMessage
+	nextID int64
+}
+
+func NewMockPinnedMessageStorage() *MockPinnedMessageStorage {
+	return &MockPinnedMessageStorage{
+		pins: make(map[int64]*models.PinnedMessage),
+	}
+}
+
+func (m *MockPinnedMessageStorage) GetPinnedMessage(ctx context.Context, chatID, serverID int64) (*models.PinnedMessage, error) {
+	for _, pin := range m.pins {
+		if pin.ChatID == chatID && pin.ServerID == serverID {
+			copied := *pin
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockPinnedMessageStorage) ListPinnedMessages(ctx context.Context) ([]*models.PinnedMessage, error) {
+	var pins []*models.PinnedMessage
+	for _, pin := range m.pins {
+		copied := *pin
+		pins = append(pins, &copied)
+	}
+	return pins, nil
+}
+
+func (m *MockPinnedMessageStorage) UpsertPinnedMessage(ctx context.Context, pin *models.PinnedMessage) error {
+	existing, _ := m.GetPinnedMessage(ctx, pin.ChatID, pin.ServerID)
+	if existing != nil {
+		pin.ID = existing.ID
+	} else {
+		m.nextID++
+		pin.ID = m.nextID
+	}
+	copied := *pin
+	m.pins[pin.ID] = &copied
+	return nil
+}
+
+func (m *MockPinnedMessageStorage) DeletePinnedMessage(ctx context.Context, id int64) error {
+	delete(m.pins, id)
+	return nil
+}
+
+func TestPinService_Pin(t *testing.T) {
+	pins := NewPinService(NewMockPinnedMessageStorage())
+	ctx := context.Background()
+
+	previous, err := pins.Pin(ctx, 111, 1, 10)
+	require.NoError(t, err)
+	assert.Nil(t, previous)
+
+	previous, err = pins.Pin(ctx, 111, 1, 20)
+	require.NoError(t, err)
+	require.NotNil(t, previous)
+	assert.Equal(t, 10, previous.MessageID, "the replaced card is returned")
+
+	list, err := pins.ListPins(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, 20, list[0].MessageID)
+
+	require.NoError(t, pins.Unpin(ctx, list[0]))
+	list, err = pins.ListPins(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestServerStatusResult_FormatPinnedStatus(t *testing.T) {
+	result := &ServerStatusResult{
+		Server: &models.Server{IP: "mc.example.com", Port: 25565, Name: "Main"},
+		Status: &minecraft.ServerStatus{Online: true, Players: minecraft.PlayersInfo{Online: 1, Max: 20}},
+	}
+
+	text := result.FormatPinnedStatus(i18n.RU, time.Date(2024, 3, 5, 14, 7, 0, 0, time.UTC))
+	assert.Contains(t, text, "*Main*")
+	assert.Contains(t, text, "Обновлено: 05\\.03\\.2024 14:07 UTC")
+}
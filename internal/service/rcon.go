@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/minecraft"
+	"github.com/ykhdr/mss-bot/internal/secret"
+	"github.com/ykhdr/mss-bot/internal/storage"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+// maxRCONOutput is how many characters of command output are shown, leaving room for the header
+const maxRCONOutput = 3500
+
+var (
+	// ErrRCONDisabled is returned when no key to encrypt RCON passwords is configured
+	ErrRCONDisabled = errors.New("rcon is disabled")
+	// ErrRCONNotConfigured is returned for servers without RCON settings
+	ErrRCONNotConfigured = errors.New("rcon is not configured for the server")
+)
+
+// dangerousCommands are commands that stop the server or act on players,
+// keyed by the command name and, for commands whose subcommands differ, the subcommand
+var dangerousCommands = map[string]bool{
+	"stop":             true,
+	"restart":          true,
+	"reload":           true,
+	"end":              true, // stops BungeeCord proxies
+	"ban":              true,
+	"ban-ip":           true,
+	"kick":             true,
+	"kill":             true,
+	"op":               true,
+	"deop":             true,
+	"clear":            true,
+	"save-off":         true,
+	"whitelist off":    true,
+	"whitelist remove": true,
+}
+
+// RCONExecutor runs commands on the remote console of a server
+type RCONExecutor interface {
+	ExecRCON(ctx context.Context, host string, port int, password, command string) (string, error)
+}
+
+// RCONService runs console commands on servers registered in a chat.
+// Passwords are encrypted before they are stored and decrypted only to log in.
+type RCONService struct {
+	servers  *ServerService
+	box      *secret.Box
+	executor RCONExecutor
+}
+
+// NewRCONService creates a new RCON service; a nil box disables RCON.
+func NewRCONService(servers *ServerService, box *secret.Box, executor RCONExecutor) *RCONService {
+	return &RCONService{
+		servers:  servers,
+		box:      box,
+		executor: executor,
+	}
+}
+
+// Enabled reports whether RCON passwords can be stored.
+func (s *RCONService) Enabled() bool {
+	return s.box != nil
+}
+
+// ListServers returns the servers of a chat with RCON configured.
+func (s *RCONService) ListServers(ctx context.Context, chatID int64) ([]*models.Server, error) {
+	servers, err := s.servers.ListServers(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	var configured []*models.Server
+	for _, server := range servers {
+		if server.RCONEnabled() {
+			configured = append(configured, server)
+		}
+	}
+	return configured, nil
+}
+
+// SetRCON stores the RCON port and password of a Java server registered in a chat.
+// A zero port or an empty password removes the settings.
+func (s *RCONService) SetRCON(ctx context.Context, chatID, serverID int64, port int, password string) (*models.Server, error) {
+	log.Info().
+		Int64("chat_id", chatID).
+		Int64("server_id", serverID).
+		Int("port", port).
+		Msg("setting server rcon")
+
+	if !s.Enabled() {
+		return nil, ErrRCONDisabled
+	}
+	if port < 0 || port > 65535 {
+		return nil, fmt.Errorf("invalid rcon port: %d", port)
+	}
+
+	server, err := s.servers.GetServer(ctx, chatID, serverID)
+	if err != nil {
+		return nil, err
+	}
+	if server.IsBedrock() {
+		return nil, fmt.Errorf("rcon is not supported by Bedrock servers")
+	}
+
+	if port == 0 || password == "" {
+		server.RCONPort = 0
+		server.RCONPassword = ""
+	} else {
+		encrypted, err := s.box.Encrypt(password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt rcon password: %w", err)
+		}
+		server.RCONPort = port
+		server.RCONPassword = encrypted
+	}
+
+	if err := s.servers.storage.Upsert(ctx, server); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+// Execute runs a command on the console of a server registered in a chat and returns its output.
+func (s *RCONService) Execute(ctx context.Context, chatID, serverID int64, command string) (string, error) {
+	log.Info().
+		Int64("chat_id", chatID).
+		Int64("server_id", serverID).
+		Str("command", commandName(command)).
+		Msg("executing rcon command")
+
+	if !s.Enabled() {
+		return "", ErrRCONDisabled
+	}
+
+	server, err := s.servers.GetServer(ctx, chatID, serverID)
+	if err != nil {
+		return "", err
+	}
+	if !server.RCONEnabled() {
+		return "", ErrRCONNotConfigured
+	}
+
+	password, err := s.box.Decrypt(server.RCONPassword)
+	if err != nil {
+		log.Error().Err(err).Int64("server_id", serverID).Msg("failed to decrypt rcon password")
+		return "", fmt.Errorf("failed to decrypt rcon password: %w", err)
+	}
+
+	return s.executor.ExecRCON(ctx, server.IP, server.RCONPort, password, strings.TrimPrefix(strings.TrimSpace(command), "/"))
+}
+
+// IsDangerousCommand reports whether a command should be confirmed before it is run.
+func IsDangerousCommand(command string) bool {
+	fields := strings.Fields(strings.ToLower(strings.TrimPrefix(strings.TrimSpace(command), "/")))
+	if len(fields) == 0 {
+		return false
+	}
+
+	name := strings.TrimPrefix(fields[0], "minecraft:")
+	if dangerousCommands[name] {
+		return true
+	}
+	return len(fields) > 1 && dangerousCommands[name+" "+fields[1]]
+}
+
+// commandName returns the name of a command without its arguments, which may hold private data.
+func commandName(command string) string {
+	name, _, _ := strings.Cut(strings.TrimSpace(command), " ")
+	return name
+}
+
+// FormatRCONOutput formats the output of a console command for display.
+// Formatting codes are stripped and long output is truncated.
+func FormatRCONOutput(lang i18n.Lang, server *models.Server, command, output string) string {
+	text := minecraft.ParseLegacyText(output).String()
+	if strings.TrimSpace(text) == "" {
+		text = lang.T("rcon.empty_reply")
+	}
+	if utf8.RuneCountInString(text) > maxRCONOutput {
+		text = string([]rune(text)[:maxRCONOutput]) + "…"
+	}
+
+	return fmt.Sprintf("🖥 *%s*\n`%s`\n\n```\n%s\n```",
+		EscapeMarkdown(ServerTitle(server)),
+		escapeCode(command),
+		escapeCode(text),
+	)
+}
+
+// RCONErrorText describes why a console command failed, for showing to users.
+func RCONErrorText(lang i18n.Lang, err error) string {
+	switch {
+	case errors.Is(err, ErrRCONDisabled):
+		return lang.T("rcon.error.disabled")
+	case errors.Is(err, ErrRCONNotConfigured):
+		return lang.T("rcon.error.not_configured")
+	case errors.Is(err, minecraft.ErrRCONAuth):
+		return lang.T("rcon.error.auth")
+	case errors.Is(err, ErrInvalidPlayerName):
+		return lang.T("rcon.error.invalid_player")
+	case errors.As(err, new(storage.ErrNotFound)):
+		return lang.T("rcon.error.not_found")
+	default:
+		return ExplainError(lang, err)
+	}
+}
+
+// escapeCode escapes text for MarkdownV2 code spans and blocks, where only ` and \ are special.
+func escapeCode(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "`", "\\`").Replace(s)
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/minecraft"
+	"github.com/ykhdr/mss-bot/internal/minecraft/mctest"
+	"github.com/ykhdr/mss-bot/internal/secret"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+func newTestRCONService(t *testing.T, storage *MockStorage) *RCONService {
+	t.Helper()
+
+	box, err := secret.NewBox(bytes.Repeat([]byte{1}, secret.KeySize))
+	require.NoError(t, err)
+	client := minecraft.NewClient(2 * time.Second)
+	return NewRCONService(NewServerService(storage, storage, clientProviders(client), client), box, client)
+}
+
+func TestRCONService_Execute(t *testing.T) {
+	commands := make(chan string, 10)
+	server := mctest.NewServer(mctest.WithRCON("hunter2", func(command string) string {
+		commands <- command
+		return "§6There are §c0§6 of a max of §c20§6 players online"
+	}))
+	defer server.Close()
+
+	mockStorage := NewMockStorage()
+	rcon := newTestRCONService(t, mockStorage)
+	ctx := context.Background()
+
+	require.NoError(t, rcon.servers.SetServerConfig(ctx, 111, 0, models.EditionJava, server.Host, server.Port, "Fake"))
+	stored, err := mockStorage.firstByChatID(ctx, 111)
+	require.NoError(t, err)
+
+	_, err = rcon.Execute(ctx, 111, stored.ID, "list")
+	assert.ErrorIs(t, err, ErrRCONNotConfigured)
+
+	_, err = rcon.SetRCON(ctx, 111, stored.ID, server.RCONPort, "hunter2")
+	require.NoError(t, err)
+	assert.NotContains(t, mockStorage.servers[stored.ID].RCONPassword, "hunter2", "passwords are stored encrypted")
+
+	output, err := rcon.Execute(ctx, 111, stored.ID, "/list")
+	require.NoError(t, err)
+	assert.Equal(t, "list", <-commands, "the slash is not sent")
+	assert.Contains(t, FormatRCONOutput(i18n.RU, stored, "list", output), "There are 0 of a max of 20 players online")
+
+	_, err = rcon.Execute(ctx, 222, stored.ID, "list")
+	assert.Error(t, err, "servers of other chats are not reachable")
+
+	configured, err := rcon.ListServers(ctx, 111)
+	require.NoError(t, err)
+	assert.Len(t, configured, 1)
+
+	_, err = rcon.SetRCON(ctx, 111, stored.ID, server.RCONPort, "wrong")
+	require.NoError(t, err)
+	_, err = rcon.Execute(ctx, 111, stored.ID, "list")
+	assert.ErrorIs(t, err, minecraft.ErrRCONAuth)
+	assert.Equal(t, "сервер отклонил пароль RCON", RCONErrorText(i18n.RU, err))
+
+	_, err = rcon.SetRCON(ctx, 111, stored.ID, 0, "")
+	require.NoError(t, err)
+	configured, err = rcon.ListServers(ctx, 111)
+	require.NoError(t, err)
+	assert.Empty(t, configured)
+}
+
+func TestRCONService_Disabled(t *testing.T) {
+	mockStorage := NewMockStorage()
+	rcon := NewRCONService(NewServerService(mockStorage, mockStorage, StatusProviders{}, nil), nil, nil)
+
+	assert.False(t, rcon.Enabled())
+	_, err := rcon.SetRCON(context.Background(), 111, 1, minecraft.DefaultRCONPort, "hunter2")
+	assert.ErrorIs(t, err, ErrRCONDisabled)
+	_, err = rcon.Execute(context.Background(), 111, 1, "list")
+	assert.ErrorIs(t, err, ErrRCONDisabled)
+}
+
+func TestRCONService_SetRCON_Bedrock(t *testing.T) {
+	mockStorage := NewMockStorage()
+	rcon := newTestRCONService(t, mockStorage)
+	ctx := context.Background()
+
+	require.NoError(t, rcon.servers.SetServerConfig(ctx, 111, 0, models.EditionBedrock, "pe.example.com", 19132, ""))
+	stored, err := mockStorage.firstByChatID(ctx, 111)
+	require.NoError(t, err)
+
+	_, err = rcon.SetRCON(ctx, 111, stored.ID, minecraft.DefaultRCONPort, "hunter2")
+	assert.Error(t, err)
+}
+
+func TestIsDangerousCommand(t *testing.T) {
+	for _, command := range []string{"stop", "/stop", "ban Steve", "minecraft:kill @e", "WHITELIST OFF", "whitelist remove Alex"} {
+		assert.True(t, IsDangerousCommand(command), command)
+	}
+	for _, command := range []string{"", "list", "say stop", "whitelist add Alex", "whitelist list"} {
+		assert.False(t, IsDangerousCommand(command), command)
+	}
+}
+
+func TestFormatRCONOutput(t *testing.T) {
+	server := &models.Server{IP: "mc.example.com", Port: 25565, Name: "Main"}
+
+	text := FormatRCONOutput(i18n.RU, server, "say `hi`", "")
+	assert.Contains(t, text, "*Main*")
+	assert.Contains(t, text, "`say \\`hi\\``")
+	assert.Contains(t, text, "(пустой ответ)")
+
+	text = FormatRCONOutput(i18n.RU, server, "help", strings.Repeat("x", 5000))
+	assert.Less(t, len(text), 4096)
+	assert.Contains(t, text, "…")
+}
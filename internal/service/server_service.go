@@ -2,88 +2,440 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/ykhdr/mss-bot/internal/i18n"
 	"github.com/ykhdr/mss-bot/internal/minecraft"
 	"github.com/ykhdr/mss-bot/internal/storage"
 	"github.com/ykhdr/mss-bot/internal/storage/models"
 )
 
+// StatusProviders maps each edition to the provider its servers are queried with.
+type StatusProviders map[models.Edition]minecraft.StatusProvider
+
 // ServerService provides business logic for server operations.
 type ServerService struct {
-	storage storage.ServerStorage
-	mc      *minecraft.Client
+	storage   storage.ServerStorage
+	history   storage.StatusHistoryStorage
+	providers StatusProviders
+	querier   minecraft.FullStatQuerier
+	resolver  minecraft.Resolver
+
+	// recorded keeps the time of the last query recorded per server, so cached results are recorded once
+	recordedMu sync.Mutex
+	recorded   map[int64]time.Time
+	// latest keeps the last result of every stored server
+	latest map[int64]*ServerStatusResult
+}
+
+// ServerServiceOption configures optional ServerService settings.
+type ServerServiceOption func(*ServerService)
+
+// WithAddressResolver sets the resolver used to check addresses typed by users before they are queried.
+func WithAddressResolver(resolver minecraft.Resolver) ServerServiceOption {
+	return func(s *ServerService) {
+		s.resolver = resolver
+	}
 }
 
 // NewServerService creates a new server service.
-func NewServerService(storage storage.ServerStorage, mc *minecraft.Client) *ServerService {
-	return &ServerService{
-		storage: storage,
-		mc:      mc,
+// Servers are queried with the provider of their edition; querier serves full stat queries
+// of servers with query enabled and may be nil.
+func NewServerService(
+	storage storage.ServerStorage,
+	history storage.StatusHistoryStorage,
+	providers StatusProviders,
+	querier minecraft.FullStatQuerier,
+	opts ...ServerServiceOption,
+) *ServerService {
+	s := &ServerService{
+		storage:   storage,
+		history:   history,
+		providers: providers,
+		querier:   querier,
+		resolver:  net.DefaultResolver,
+		recorded:  make(map[int64]time.Time),
+		latest:    make(map[int64]*ServerStatusResult),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ListServers returns all servers registered in a chat.
+func (s *ServerService) ListServers(ctx context.Context, chatID int64) ([]*models.Server, error) {
+	log.Debug().Int64("chat_id", chatID).Msg("listing servers")
+	return s.storage.ListByChatID(ctx, chatID)
+}
+
+// GetServer returns a server registered in a chat.
+// Servers belonging to other chats are reported as not found.
+func (s *ServerService) GetServer(ctx context.Context, chatID, serverID int64) (*models.Server, error) {
+	log.Debug().Int64("chat_id", chatID).Int64("server_id", serverID).Msg("getting server")
+
+	server, err := s.storage.GetByID(ctx, serverID)
+	if err != nil {
+		return nil, err
 	}
+	if server.ChatID != chatID {
+		log.Warn().Int64("chat_id", chatID).Int64("server_id", serverID).Msg("server belongs to another chat")
+		return nil, storage.ErrNotFound{ChatID: chatID, ID: serverID}
+	}
+
+	return server, nil
 }
 
-// GetServerConfig returns the server configuration for a chat.
-func (s *ServerService) GetServerConfig(ctx context.Context, chatID int64) (*models.Server, error) {
-	log.Debug().Int64("chat_id", chatID).Msg("getting server config")
-	return s.storage.GetByChatID(ctx, chatID)
+// RemoveServer removes a server registered in a chat.
+func (s *ServerService) RemoveServer(ctx context.Context, chatID, serverID int64) error {
+	log.Info().Int64("chat_id", chatID).Int64("server_id", serverID).Msg("removing server")
+
+	if _, err := s.GetServer(ctx, chatID, serverID); err != nil {
+		return err
+	}
+
+	s.recordedMu.Lock()
+	delete(s.recorded, serverID)
+	delete(s.latest, serverID)
+	s.recordedMu.Unlock()
+
+	return s.storage.DeleteByID(ctx, serverID)
 }
 
-// SetServerConfig sets or updates the server configuration for a chat.
-func (s *ServerService) SetServerConfig(ctx context.Context, chatID int64, ip string, port int, name string) error {
-	log.Info().Int64("chat_id", chatID).Str("ip", ip).Int("port", port).Str("name", name).Msg("setting server config")
+// SetServerConfig adds a server to a chat or renames it if the address is already registered.
+// Other settings of an already registered server are kept. The user adding a server is recorded,
+// zero when unknown.
+func (s *ServerService) SetServerConfig(
+	ctx context.Context,
+	chatID int64,
+	userID int64,
+	edition models.Edition,
+	ip string,
+	port int,
+	name string,
+) error {
+	log.Info().
+		Int64("chat_id", chatID).
+		Int64("user_id", userID).
+		Str("edition", string(edition)).
+		Str("ip", ip).
+		Int("port", port).
+		Str("name", name).
+		Msg("setting server config")
 
 	server := &models.Server{
-		ChatID: chatID,
-		IP:     ip,
-		Port:   port,
-		Name:   name,
+		ChatID:  chatID,
+		IP:      ip,
+		Port:    port,
+		AddedBy: userID,
+	}
+
+	servers, err := s.storage.ListByChatID(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	for _, existing := range servers {
+		if existing.IP == ip && existing.Port == port {
+			server = existing
+			break
+		}
 	}
 
+	server.Name = name
+	server.Edition = edition
 	return s.storage.Upsert(ctx, server)
 }
 
-// GetServerStatus returns the status of the configured server for a chat.
-func (s *ServerService) GetServerStatus(ctx context.Context, chatID int64) (*ServerStatusResult, error) {
-	log.Debug().Int64("chat_id", chatID).Msg("getting server status")
+// SetQuery configures GameSpy4 full stat queries for a server registered in a chat.
+// A zero port sends queries to the game port.
+func (s *ServerService) SetQuery(ctx context.Context, chatID, serverID int64, enabled bool, port int) (*models.Server, error) {
+	log.Info().
+		Int64("chat_id", chatID).
+		Int64("server_id", serverID).
+		Bool("enabled", enabled).
+		Int("port", port).
+		Msg("setting server query")
+
+	server, err := s.GetServer(ctx, chatID, serverID)
+	if err != nil {
+		return nil, err
+	}
+	if server.IsBedrock() {
+		return nil, fmt.Errorf("query is not supported by Bedrock servers")
+	}
+
+	server.QueryEnabled = enabled
+	server.QueryPort = port
+	if err := s.storage.Upsert(ctx, server); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+// SetProtocolVersion sets the protocol version advertised to a Java server registered in a chat.
+// models.AutoProtocolVersion uses the server's own protocol.
+func (s *ServerService) SetProtocolVersion(ctx context.Context, chatID, serverID int64, version int) (*models.Server, error) {
+	log.Info().
+		Int64("chat_id", chatID).
+		Int64("server_id", serverID).
+		Int("protocol_version", version).
+		Msg("setting server protocol version")
 
-	server, err := s.storage.GetByChatID(ctx, chatID)
+	if version < 0 {
+		return nil, fmt.Errorf("invalid protocol version: %d", version)
+	}
+
+	server, err := s.GetServer(ctx, chatID, serverID)
 	if err != nil {
-		log.Warn().Err(err).Int64("chat_id", chatID).Msg("server config not found for status check")
 		return nil, err
 	}
+	if server.IsBedrock() {
+		return nil, fmt.Errorf("protocol version is not supported by Bedrock servers")
+	}
+
+	server.ProtocolVersion = version
+	if err := s.storage.Upsert(ctx, server); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+// GetServerStatus returns the status of a server registered in a chat.
+func (s *ServerService) GetServerStatus(ctx context.Context, chatID, serverID int64) (*ServerStatusResult, error) {
+	log.Debug().Int64("chat_id", chatID).Int64("server_id", serverID).Msg("getting server status")
+
+	server, err := s.GetServer(ctx, chatID, serverID)
+	if err != nil {
+		log.Warn().Err(err).Int64("chat_id", chatID).Int64("server_id", serverID).Msg("server config not found for status check")
+		return nil, err
+	}
+
+	return s.CheckServer(ctx, server), nil
+}
+
+// ListAllServers returns the servers of all chats.
+func (s *ServerService) ListAllServers(ctx context.Context) ([]*models.Server, error) {
+	log.Debug().Msg("listing all servers")
+	return s.storage.List(ctx)
+}
+
+// ListUserServers returns the servers a user added in any chat or registered in their private chat.
+// A server registered in several chats is listed once.
+func (s *ServerService) ListUserServers(ctx context.Context, userID int64) ([]*models.Server, error) {
+	log.Debug().Int64("user_id", userID).Msg("listing user servers")
+
+	servers, err := s.storage.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	unique := servers[:0]
+	seen := make(map[string]bool, len(servers))
+	for _, server := range servers {
+		key := string(server.Edition) + " " + minecraft.FormatAddress(server.IP, server.Port)
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, server)
+		}
+	}
+	return unique, nil
+}
+
+// CheckServer queries the current status of a stored server and records it in the status history.
+// Query failures are reported through ServerStatusResult.Error as a *minecraft.StatusError.
+func (s *ServerService) CheckServer(ctx context.Context, server *models.Server) *ServerStatusResult {
+	result := s.check(ctx, server)
+	s.recordStatus(ctx, result)
+	return result
+}
+
+// RecentStatus returns the last result of a stored server if it was queried within maxAge,
+// so that the status can be shown without querying the server again.
+func (s *ServerService) RecentStatus(server *models.Server, maxAge time.Duration) (*ServerStatusResult, bool) {
+	s.recordedMu.Lock()
+	defer s.recordedMu.Unlock()
+
+	result, ok := s.latest[server.ID]
+	if !ok || time.Since(result.Status.CheckedAt) >= maxAge {
+		return nil, false
+	}
+	return &ServerStatusResult{Server: server, Status: result.Status, Error: result.Error}, true
+}
+
+// CheckAddress queries the current status of a server that does not have to be registered in any chat.
+// Nothing is recorded in the status history.
+func (s *ServerService) CheckAddress(
+	ctx context.Context, edition models.Edition, host string, port int,
+) *ServerStatusResult {
+	return s.check(ctx, &models.Server{IP: host, Port: port, Edition: edition})
+}
+
+// CheckPublicAddress is CheckAddress for addresses typed by any user. The address is resolved first
+// and refused with minecraft.ErrPrivateAddress when it leads into a private network; the resolved IP is queried.
+func (s *ServerService) CheckPublicAddress(
+	ctx context.Context, edition models.Edition, host string, port int,
+) *ServerStatusResult {
+	server := &models.Server{IP: host, Port: port, Edition: edition}
+
+	target, err := minecraft.ResolvePublic(ctx, s.resolver, host, port, edition != models.EditionBedrock)
+	if err != nil {
+		log.Warn().Err(err).Str("ip", host).Int("port", port).Msg("refusing to query minecraft server")
+		return &ServerStatusResult{Server: server, Status: &minecraft.ServerStatus{Online: false}, Error: err}
+	}
+
+	result := s.check(ctx, &models.Server{IP: target.Host, Port: target.Port, Edition: edition})
+	result.Server = server
+	return result
+}
+
+// check queries the current status of a server.
+func (s *ServerService) check(ctx context.Context, server *models.Server) *ServerStatusResult {
+	log.Debug().
+		Int64("chat_id", server.ChatID).
+		Str("edition", string(server.Edition)).
+		Str("ip", server.IP).
+		Int("port", server.Port).
+		Msg("querying minecraft server")
+
+	status, err := s.queryStatus(ctx, server)
+	if status == nil {
+		status = &minecraft.ServerStatus{Online: false}
+	}
+
+	// A server that drops the ping but answers query is still online
+	if !server.IsBedrock() && server.QueryEnabled && s.querier != nil && s.applyQuery(ctx, server, status) {
+		err = nil
+	}
+
+	result := &ServerStatusResult{
+		Server: server,
+		Status: status,
+		Error:  err,
+	}
 
-	log.Debug().Int64("chat_id", chatID).Str("ip", server.IP).Int("port", server.Port).Msg("querying minecraft server")
-	status, err := s.mc.GetStatus(ctx, server.IP, server.Port)
 	if err != nil {
 		log.Warn().
 			Err(err).
-			Int64("chat_id", chatID).
+			Int64("chat_id", server.ChatID).
 			Str("ip", server.IP).
 			Int("port", server.Port).
+			Str("error_kind", string(minecraft.ErrorKindOf(err))).
 			Msg("minecraft server query failed")
-		return &ServerStatusResult{
-			Server: server,
-			Status: &minecraft.ServerStatus{Online: false},
-			Error:  err,
-		}, nil
+		return result
 	}
 
 	log.Info().
-		Int64("chat_id", chatID).
+		Int64("chat_id", server.ChatID).
 		Str("ip", server.IP).
 		Int("port", server.Port).
 		Bool("online", status.Online).
+		Dur("latency", status.Latency).
 		Int("players", status.Players.Online).
 		Msg("minecraft server status retrieved")
-	return &ServerStatusResult{
-		Server: server,
-		Status: status,
-	}, nil
+	return result
 }
 
+// queryStatus queries the server with the provider of its edition.
+func (s *ServerService) queryStatus(ctx context.Context, server *models.Server) (*minecraft.ServerStatus, error) {
+	edition := server.Edition
+	if edition == "" {
+		edition = models.EditionJava
+	}
+
+	provider, ok := s.providers[edition]
+	if !ok {
+		return nil, &minecraft.StatusError{
+			Kind: minecraft.ErrorKindUnknown,
+			Err:  fmt.Errorf("no status provider for %s edition", edition),
+		}
+	}
+	return provider.Status(ctx, minecraft.StatusRequest{
+		Host:            server.IP,
+		Port:            server.Port,
+		ProtocolVersion: server.ProtocolVersion,
+	})
+}
+
+// recordStatus stores the check outcome in the status history.
+// Checks interrupted by shutdown and cached results that were already recorded are skipped.
+func (s *ServerService) recordStatus(ctx context.Context, result *ServerStatusResult) {
+	if ctx.Err() != nil || !s.markRecorded(result) {
+		return
+	}
+
+	record := &models.StatusRecord{
+		ServerID:      result.Server.ID,
+		Online:        result.Status.Online,
+		PlayersOnline: result.Status.Players.Online,
+		Latency:       result.Status.Latency,
+		CheckedAt:     result.Status.CheckedAt,
+	}
+	if result.Error != nil {
+		record.ErrorKind = string(minecraft.ErrorKindOf(result.Error))
+		record.Error = result.Error.Error()
+	}
+
+	if err := s.history.AddStatusRecord(ctx, record); err != nil {
+		log.Error().Err(err).Int64("server_id", result.Server.ID).Msg("failed to record server status")
+	}
+}
+
+// markRecorded keeps the result as the latest of its server and reports whether it still has to be recorded.
+func (s *ServerService) markRecorded(result *ServerStatusResult) bool {
+	serverID, checkedAt := result.Server.ID, result.Status.CheckedAt
+	if checkedAt.IsZero() {
+		return true
+	}
+
+	s.recordedMu.Lock()
+	defer s.recordedMu.Unlock()
+
+	s.latest[serverID] = result
+
+	if s.recorded[serverID].Equal(checkedAt) {
+		return false
+	}
+	s.recorded[serverID] = checkedAt
+	return true
+}
+
+// applyQuery merges the full stat query into the ping status and reports whether the query succeeded.
+// When the query fails, the ping status is kept as is.
+func (s *ServerService) applyQuery(ctx context.Context, server *models.Server, status *minecraft.ServerStatus) bool {
+	// Query listens next to the game port, so follow the SRV target when there is one
+	host, port := server.IP, server.GamePort()
+	if status.ResolvedHost != "" {
+		host, port = status.ResolvedHost, status.ResolvedPort
+	}
+	if server.QueryPort != 0 {
+		port = server.QueryPort
+	}
+
+	query, err := s.querier.QueryFull(ctx, host, port)
+	if err != nil {
+		log.Debug().
+			Err(err).
+			Int64("server_id", server.ID).
+			Str("host", host).
+			Int("port", port).
+			Msg("full stat query failed, using ping status")
+		return false
+	}
+
+	status.ApplyQuery(query)
+	return true
+}
+
+// maxListedPlayers caps the player list of a status message to keep it within Telegram limits.
+const maxListedPlayers = 50
+
 // ServerStatusResult contains both server config and its current status.
 type ServerStatusResult struct {
 	Server *models.Server
@@ -91,115 +443,332 @@ type ServerStatusResult struct {
 	Error  error
 }
 
+// FormatSummary formats the server status as one line of plain text, e.g. for inline query results.
+func (r *ServerStatusResult) FormatSummary(lang i18n.Lang) string {
+	if !r.Status.Online {
+		if r.Error != nil {
+			return lang.T("summary.offline_reason", ExplainError(lang, r.Error))
+		}
+		return lang.T("summary.offline")
+	}
+
+	summary := lang.T("summary.online", r.Status.Players.Online, r.Status.Players.Max)
+	if r.Status.Version != "" {
+		summary += " · " + r.Status.Version
+	}
+	if r.Status.Description != "" {
+		summary += " · " + strings.Join(strings.Fields(r.Status.Description), " ")
+	}
+	return summary
+}
+
 // FormatStatus formats the server status for display.
-func (r *ServerStatusResult) FormatStatus() string {
+func (r *ServerStatusResult) FormatStatus(lang i18n.Lang) string {
 	if r.Server == nil {
-		return "Сервер не настроен. Используйте настройки для добавления сервера."
+		return lang.T("status.not_configured")
 	}
 
-	serverName := r.Server.Name
-	if serverName == "" {
-		serverName = minecraft.FormatAddress(r.Server.IP, r.Server.Port)
-	}
+	serverName := ServerTitle(r.Server)
 
 	if !r.Status.Online {
-		return fmt.Sprintf("🔴 *%s*\n\n"+
-			"Адрес: `%s`\n"+
-			"Статус: Недоступен",
-			escapeMarkdown(serverName),
+		return lang.T("status.offline",
+			EscapeMarkdown(serverName),
 			minecraft.FormatAddress(r.Server.IP, r.Server.Port),
+			r.formatReason(lang),
+			r.formatCheckedAt(lang, time.Now()),
 		)
 	}
 
 	playersStr := ""
 	if len(r.Status.Players.Sample) > 0 {
-		playersStr = "\n\n👥 *Игроки онлайн:*\n"
-		for _, p := range r.Status.Players.Sample {
-			playersStr += fmt.Sprintf("• %s\n", escapeMarkdown(p.Name))
+		playersStr = "\n\n" + lang.T("status.players") + "\n"
+		for i, p := range r.Status.Players.Sample {
+			if i == maxListedPlayers {
+				playersStr += lang.N("status.more_players", len(r.Status.Players.Sample)-maxListedPlayers) + "\n"
+				break
+			}
+			playersStr += fmt.Sprintf("• %s\n", EscapeMarkdown(p.Name))
 		}
+		playersStr = strings.TrimSuffix(playersStr, "\n")
 	}
 
-	return fmt.Sprintf("🟢 *%s*\n\n"+
-		"Адрес: `%s`\n"+
-		"Версия: %s\n"+
-		"Онлайн: %d/%d%s",
-		escapeMarkdown(serverName),
-		minecraft.FormatAddress(r.Server.IP, r.Server.Port),
-		escapeMarkdown(r.Status.Version),
+	return lang.T("status.online",
+		EscapeMarkdown(serverName),
+		r.formatMOTD(),
+		r.formatAddress(),
+		EscapeMarkdown(r.Status.Version),
+		r.formatLatency(lang),
+		r.formatModsSummary(lang),
+		r.formatBedrockDetails(lang),
+		r.formatQueryDetails(lang),
 		r.Status.Players.Online,
 		r.Status.Players.Max,
 		playersStr,
+		r.formatCheckedAt(lang, time.Now()),
 	)
 }
 
-// FormatConfig formats the server configuration for display.
-func FormatConfig(server *models.Server) string {
-	if server == nil {
-		return "⚙️ *Настройки сервера*\n\n" +
-			"Сервер не настроен\\.\n\n" +
-			"Для настройки отправьте команду:\n" +
-			"`/set <ip>:<port> <name>`\n\n" +
-			"Пример:\n" +
-			"`/set mc\\.example\\.com:25565 My Server`"
+// formatCheckedAt formats how long ago the status was queried as a paragraph with a leading blank line.
+func (r *ServerStatusResult) formatCheckedAt(lang i18n.Lang, now time.Time) string {
+	if r.Status.CheckedAt.IsZero() {
+		return ""
+	}
+	return "\n\n" + lang.T("status.checked", formatAge(lang, now.Sub(r.Status.CheckedAt)))
+}
+
+// formatAge formats a duration in the past.
+func formatAge(lang i18n.Lang, age time.Duration) string {
+	switch {
+	case age < time.Second:
+		return lang.T("age.now")
+	case age < time.Minute:
+		return lang.T("age.seconds", int(age.Seconds()))
+	default:
+		return lang.T("age.minutes", int(age.Minutes()))
+	}
+}
+
+// formatMOTD formats the server MOTD as a paragraph with a trailing blank line.
+func (r *ServerStatusResult) formatMOTD() string {
+	motd := formatMOTD(r.Status.MOTD)
+	if motd == "" {
+		return ""
+	}
+	return motd + "\n\n"
+}
+
+// formatReason formats the explanation of a failed query as a line with a leading newline.
+func (r *ServerStatusResult) formatReason(lang i18n.Lang) string {
+	if r.Error == nil {
+		return ""
+	}
+	return "\n" + lang.T("status.reason", EscapeMarkdown(ExplainError(lang, r.Error)))
+}
+
+// ExplainError returns a human-readable explanation of a status query failure.
+func ExplainError(lang i18n.Lang, err error) string {
+	if errors.Is(err, minecraft.ErrPrivateAddress) {
+		return lang.T("explain.private")
+	}
+
+	switch minecraft.ErrorKindOf(err) {
+	case minecraft.ErrorKindDNS:
+		return lang.T("explain.dns")
+	case minecraft.ErrorKindRefused:
+		return lang.T("explain.refused")
+	case minecraft.ErrorKindUnreachable:
+		return lang.T("explain.unreachable")
+	case minecraft.ErrorKindTimeout:
+		return lang.T("explain.timeout")
+	case minecraft.ErrorKindReset:
+		return lang.T("explain.reset")
+	case minecraft.ErrorKindProtocol:
+		return lang.T("explain.protocol")
+	default:
+		return lang.T("explain.unknown")
+	}
+}
+
+// formatAddress formats the server address together with its SRV target, if any.
+func (r *ServerStatusResult) formatAddress() string {
+	address := fmt.Sprintf("`%s`", minecraft.FormatAddress(r.Server.IP, r.Server.Port))
+	if r.Status.ResolvedHost == "" {
+		return address
+	}
+	return fmt.Sprintf("%s → `%s:%d`", address, r.Status.ResolvedHost, r.Status.ResolvedPort)
+}
+
+// Latency thresholds of the ping indicator
+const (
+	latencyGood = 100 * time.Millisecond
+	latencyFair = 250 * time.Millisecond
+)
+
+// formatLatency formats the measured latency as a line with a trailing newline.
+// The DNS and connect times are shown when they were measured.
+func (r *ServerStatusResult) formatLatency(lang i18n.Lang) string {
+	latency := r.Status.Latency
+	if latency <= 0 {
+		return ""
+	}
+
+	indicator := "🔴"
+	switch {
+	case latency < latencyGood:
+		indicator = "🟢"
+	case latency < latencyFair:
+		indicator = "🟡"
+	}
+
+	line := lang.T("status.ping", indicator, latency.Milliseconds())
+	if r.Status.DNSTime > 0 || r.Status.ConnectTime > 0 {
+		line += lang.T("status.ping_details", r.Status.DNSTime.Milliseconds(), r.Status.ConnectTime.Milliseconds())
+	}
+	return line + "\n"
+}
+
+// formatBedrockDetails formats Bedrock-specific status lines, one per line with a trailing newline.
+func (r *ServerStatusResult) formatBedrockDetails(lang i18n.Lang) string {
+	if !r.Server.IsBedrock() {
+		return ""
 	}
 
-	serverName := server.Name
-	if serverName == "" {
-		serverName = "Не указано"
+	var sb strings.Builder
+	edition := "Bedrock"
+	if r.Status.Edition == "MCEE" {
+		edition = "Education"
+	}
+	sb.WriteString(lang.T("status.edition", edition) + "\n")
+	if r.Status.GameMode != "" {
+		sb.WriteString(lang.T("status.game_mode", EscapeMarkdown(r.Status.GameMode)) + "\n")
+	}
+	if r.Status.LevelName != "" {
+		sb.WriteString(lang.T("status.world", EscapeMarkdown(r.Status.LevelName)) + "\n")
+	}
+	return sb.String()
+}
+
+// formatQueryDetails formats full stat query lines, one per line with a trailing newline.
+func (r *ServerStatusResult) formatQueryDetails(lang i18n.Lang) string {
+	query := r.Status.Query
+	if query == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	if query.Map != "" {
+		sb.WriteString(lang.T("status.map", EscapeMarkdown(query.Map)) + "\n")
+	}
+	if query.Software != "" {
+		sb.WriteString(lang.T("status.software", EscapeMarkdown(query.Software)) + "\n")
+	}
+	if len(query.Plugins) > 0 {
+		names := make([]string, 0, len(query.Plugins))
+		for _, p := range query.Plugins {
+			names = append(names, EscapeMarkdown(p.Name))
+		}
+		sb.WriteString(lang.T("status.plugins", len(names), strings.Join(names, ", ")) + "\n")
+	}
+	return sb.String()
+}
+
+// FormatConfig formats the servers registered in a chat for display.
+func FormatConfig(lang i18n.Lang, servers []*models.Server) string {
+	if len(servers) == 0 {
+		return lang.T("config.empty")
 	}
 
-	return fmt.Sprintf("⚙️ *Настройки сервера*\n\n"+
-		"IP: `%s`\n"+
-		"Порт: `%d`\n"+
-		"Название: %s\n\n"+
-		"Для изменения отправьте команду:\n"+
-		"`/set <ip>:<port> <name>`",
+	var sb strings.Builder
+	sb.WriteString(lang.T("config.title") + "\n\n")
+	for i, server := range servers {
+		serverName := server.Name
+		if serverName == "" {
+			serverName = lang.T("config.unnamed")
+		}
+		sb.WriteString(lang.T("config.server",
+			i+1,
+			EscapeMarkdown(serverName),
+			EditionTitle(server.Edition),
+			server.IP,
+			server.GamePort(),
+			formatQueryConfig(lang, server),
+		))
+	}
+	sb.WriteString(lang.T("config.footer"))
+
+	return sb.String()
+}
+
+// FormatServerSettings formats the settings of a single server for display.
+func FormatServerSettings(lang i18n.Lang, server *models.Server) string {
+	text := lang.T("server_settings",
+		EscapeMarkdown(ServerTitle(server)),
+		EditionTitle(server.Edition),
 		server.IP,
-		server.Port,
-		escapeMarkdown(serverName),
+		server.GamePort(),
+		formatProtocolConfig(lang, server),
+		formatQueryConfig(lang, server),
+		formatRCONConfig(lang, server),
 	)
+	if !server.IsBedrock() {
+		text += "\n" + lang.T("server_settings.query_hint")
+	}
+	return text
 }
 
-// escapeMarkdown escapes special Markdown characters.
-func escapeMarkdown(s string) string {
-	replacer := []struct {
-		old, new string
-	}{
-		{"_", "\\_"},
-		{"*", "\\*"},
-		{"[", "\\["},
-		{"]", "\\]"},
-		{"(", "\\("},
-		{")", "\\)"},
-		{"~", "\\~"},
-		{"`", "\\`"},
-		{">", "\\>"},
-		{"#", "\\#"},
-		{"+", "\\+"},
-		{"-", "\\-"},
-		{"=", "\\="},
-		{"|", "\\|"},
-		{"{", "\\{"},
-		{"}", "\\}"},
-		{".", "\\."},
-		{"!", "\\!"},
-	}
-
-	result := s
-	for _, r := range replacer {
-		result = replaceAll(result, r.old, r.new)
+// formatProtocolConfig formats the protocol version line of a Java server with a trailing newline.
+func formatProtocolConfig(lang i18n.Lang, server *models.Server) string {
+	if server.IsBedrock() {
+		return ""
 	}
-	return result
+	if server.ProtocolVersion == models.AutoProtocolVersion {
+		return lang.T("config.protocol_auto") + "\n"
+	}
+	return lang.T("config.protocol", server.ProtocolVersion) + "\n"
 }
 
-func replaceAll(s, old, replacement string) string {
-	result := ""
-	for i := 0; i < len(s); i++ {
-		if string(s[i]) == old {
-			result += replacement
-		} else {
-			result += string(s[i])
-		}
+// formatQueryConfig formats the query settings line of a Java server with a trailing newline.
+func formatQueryConfig(lang i18n.Lang, server *models.Server) string {
+	if server.IsBedrock() {
+		return ""
 	}
-	return result
+	if !server.QueryEnabled {
+		return lang.T("config.query_off") + "\n"
+	}
+	return lang.T("config.query_on", server.QueryTargetPort()) + "\n"
+}
+
+// formatRCONConfig formats the remote console settings line of a Java server with a trailing newline.
+func formatRCONConfig(lang i18n.Lang, server *models.Server) string {
+	if server.IsBedrock() {
+		return ""
+	}
+	if !server.RCONEnabled() {
+		return lang.T("config.rcon_off") + "\n"
+	}
+	return lang.T("config.rcon_on", server.RCONPort) + "\n"
+}
+
+// EditionTitle returns a human-readable edition name.
+func EditionTitle(edition models.Edition) string {
+	if edition == models.EditionBedrock {
+		return "Bedrock"
+	}
+	return "Java"
+}
+
+// ServerTitle returns the server name, or its address when no name is set.
+func ServerTitle(server *models.Server) string {
+	if server.Name != "" {
+		return server.Name
+	}
+	return minecraft.FormatAddress(server.IP, server.Port)
+}
+
+// markdownEscaper escapes the characters special in Telegram MarkdownV2, including the backslash.
+var markdownEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"_", "\\_",
+	"*", "\\*",
+	"[", "\\[",
+	"]", "\\]",
+	"(", "\\(",
+	")", "\\)",
+	"~", "\\~",
+	"`", "\\`",
+	">", "\\>",
+	"#", "\\#",
+	"+", "\\+",
+	"-", "\\-",
+	"=", "\\=",
+	"|", "\\|",
+	"{", "\\{",
+	"}", "\\}",
+	".", "\\.",
+	"!", "\\!",
+)
+
+// EscapeMarkdown escapes text to be shown as is in a MarkdownV2 message.
+func EscapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
 }
@@ -2,13 +2,19 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ykhdr/mss-bot/internal/i18n"
 	"github.com/ykhdr/mss-bot/internal/minecraft"
+	"github.com/ykhdr/mss-bot/internal/minecraft/mctest"
 	"github.com/ykhdr/mss-bot/internal/storage"
 	"github.com/ykhdr/mss-bot/internal/storage/models"
 )
@@ -16,6 +22,8 @@ import (
 // MockStorage is a mock implementation of storage.ServerStorage
 type MockStorage struct {
 	servers map[int64]*models.Server
+	nextID  int64
+	history []*models.StatusRecord
 }
 
 func NewMockStorage() *MockStorage {
@@ -24,30 +32,84 @@ func NewMockStorage() *MockStorage {
 	}
 }
 
-func (m *MockStorage) GetByChatID(ctx context.Context, chatID int64) (*models.Server, error) {
-	server, ok := m.servers[chatID]
-	if !ok {
+// firstByChatID returns the first server registered in a chat
+func (m *MockStorage) firstByChatID(ctx context.Context, chatID int64) (*models.Server, error) {
+	list, _ := m.ListByChatID(ctx, chatID)
+	if len(list) == 0 {
 		return nil, storage.ErrNotFound{ChatID: chatID}
 	}
+	return list[0], nil
+}
+
+func (m *MockStorage) GetByID(ctx context.Context, id int64) (*models.Server, error) {
+	server, ok := m.servers[id]
+	if !ok {
+		return nil, storage.ErrNotFound{ID: id}
+	}
 	return server, nil
 }
 
+func (m *MockStorage) ListByChatID(ctx context.Context, chatID int64) ([]*models.Server, error) {
+	var list []*models.Server
+	for id := int64(1); id <= m.nextID; id++ {
+		if server, ok := m.servers[id]; ok && server.ChatID == chatID {
+			list = append(list, server)
+		}
+	}
+	return list, nil
+}
+
+func (m *MockStorage) ListByUserID(ctx context.Context, userID int64) ([]*models.Server, error) {
+	var list []*models.Server
+	for id := int64(1); id <= m.nextID; id++ {
+		if server, ok := m.servers[id]; ok && (server.AddedBy == userID || server.ChatID == userID) {
+			list = append(list, server)
+		}
+	}
+	return list, nil
+}
+
+func (m *MockStorage) List(ctx context.Context) ([]*models.Server, error) {
+	var list []*models.Server
+	for id := int64(1); id <= m.nextID; id++ {
+		if server, ok := m.servers[id]; ok {
+			list = append(list, server)
+		}
+	}
+	return list, nil
+}
+
 func (m *MockStorage) Upsert(ctx context.Context, server *models.Server) error {
 	now := time.Now()
-	if existing, ok := m.servers[server.ChatID]; ok {
-		server.ID = existing.ID
-		server.CreatedAt = existing.CreatedAt
-	} else {
-		server.ID = int64(len(m.servers) + 1)
-		server.CreatedAt = now
+	for _, existing := range m.servers {
+		if existing.ID == server.ID ||
+			(existing.ChatID == server.ChatID && existing.IP == server.IP && existing.Port == server.Port) {
+			server.ID = existing.ID
+			server.CreatedAt = existing.CreatedAt
+			server.UpdatedAt = now
+			m.servers[server.ID] = server
+			return nil
+		}
 	}
+	m.nextID++
+	server.ID = m.nextID
+	server.CreatedAt = now
 	server.UpdatedAt = now
-	m.servers[server.ChatID] = server
+	m.servers[server.ID] = server
 	return nil
 }
 
 func (m *MockStorage) Delete(ctx context.Context, chatID int64) error {
-	delete(m.servers, chatID)
+	for id, server := range m.servers {
+		if server.ChatID == chatID {
+			delete(m.servers, id)
+		}
+	}
+	return nil
+}
+
+func (m *MockStorage) DeleteByID(ctx context.Context, id int64) error {
+	delete(m.servers, id)
 	return nil
 }
 
@@ -55,17 +117,56 @@ func (m *MockStorage) Close() error {
 	return nil
 }
 
+func (m *MockStorage) AddStatusRecord(ctx context.Context, record *models.StatusRecord) error {
+	record.ID = int64(len(m.history) + 1)
+	m.history = append(m.history, record)
+	return nil
+}
+
+// listStatusHistory returns the recorded status checks of a server, newest first
+func (m *MockStorage) listStatusHistory(ctx context.Context, serverID int64, limit int) ([]*models.StatusRecord, error) {
+	var records []*models.StatusRecord
+	for i := len(m.history) - 1; i >= 0 && len(records) < limit; i-- {
+		if m.history[i].ServerID == serverID {
+			records = append(records, m.history[i])
+		}
+	}
+	return records, nil
+}
+
+// clientProviders queries servers of both editions directly with the client
+func clientProviders(client *minecraft.Client) StatusProviders {
+	return StatusProviders{
+		models.EditionJava:    minecraft.JavaClient{Client: client},
+		models.EditionBedrock: minecraft.BedrockClient{Client: client},
+	}
+}
+
+// fakeProvider returns a fixed status and remembers the servers it was asked about
+type fakeProvider struct {
+	status    *minecraft.ServerStatus
+	queried   []string
+	protocols []int
+}
+
+func (p *fakeProvider) Status(ctx context.Context, req minecraft.StatusRequest) (*minecraft.ServerStatus, error) {
+	p.queried = append(p.queried, minecraft.FormatAddress(req.Host, req.Port))
+	p.protocols = append(p.protocols, req.ProtocolVersion)
+	status := *p.status
+	return &status, nil
+}
+
 func TestServerService_SetServerConfig(t *testing.T) {
 	mockStorage := NewMockStorage()
 	mcClient := minecraft.NewClient(5 * time.Second)
-	service := NewServerService(mockStorage, mcClient)
+	service := NewServerService(mockStorage, mockStorage, clientProviders(mcClient), mcClient)
 
 	ctx := context.Background()
-	err := service.SetServerConfig(ctx, 12345, "mc.example.com", 25565, "Test Server")
+	err := service.SetServerConfig(ctx, 12345, 0, models.EditionJava, "mc.example.com", 25565, "Test Server")
 
 	require.NoError(t, err)
 
-	server, err := mockStorage.GetByChatID(ctx, 12345)
+	server, err := mockStorage.firstByChatID(ctx, 12345)
 	require.NoError(t, err)
 
 	assert.Equal(t, "mc.example.com", server.IP)
@@ -73,42 +174,443 @@ func TestServerService_SetServerConfig(t *testing.T) {
 	assert.Equal(t, "Test Server", server.Name)
 }
 
-func TestServerService_GetServerConfig(t *testing.T) {
+func TestServerService_MultipleServers(t *testing.T) {
 	mockStorage := NewMockStorage()
 	mcClient := minecraft.NewClient(5 * time.Second)
-	service := NewServerService(mockStorage, mcClient)
+	service := NewServerService(mockStorage, mockStorage, clientProviders(mcClient), mcClient)
 
 	ctx := context.Background()
+	require.NoError(t, service.SetServerConfig(ctx, 12345, 0, models.EditionJava, "lobby.example.com", 25565, "Lobby"))
+	require.NoError(t, service.SetServerConfig(ctx, 12345, 0, models.EditionJava, "survival.example.com", 25565, "Survival"))
+
+	servers, err := service.ListServers(ctx, 12345)
+	require.NoError(t, err)
+	require.Len(t, servers, 2)
+	assert.Equal(t, "Lobby", servers[0].Name)
+	assert.Equal(t, "Survival", servers[1].Name)
 
-	// Set a server config first
-	err := service.SetServerConfig(ctx, 12345, "mc.example.com", 25565, "Test Server")
+	require.NoError(t, service.RemoveServer(ctx, 12345, servers[0].ID))
+
+	servers, err = service.ListServers(ctx, 12345)
 	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "Survival", servers[0].Name)
+}
+
+func TestServerService_GetServer_OtherChat(t *testing.T) {
+	mockStorage := NewMockStorage()
+	mcClient := minecraft.NewClient(5 * time.Second)
+	service := NewServerService(mockStorage, mockStorage, clientProviders(mcClient), mcClient)
 
-	// Get it back
-	server, err := service.GetServerConfig(ctx, 12345)
+	ctx := context.Background()
+	require.NoError(t, service.SetServerConfig(ctx, 111, 0, models.EditionJava, "mc.example.com", 25565, "Test Server"))
+	server, err := mockStorage.firstByChatID(ctx, 111)
 	require.NoError(t, err)
 
-	assert.Equal(t, "mc.example.com", server.IP)
-	assert.Equal(t, 25565, server.Port)
-	assert.Equal(t, "Test Server", server.Name)
+	_, err = service.GetServer(ctx, 222, server.ID)
+	var notFound storage.ErrNotFound
+	assert.ErrorAs(t, err, &notFound)
+
+	err = service.RemoveServer(ctx, 222, server.ID)
+	assert.ErrorAs(t, err, &notFound)
+
+	_, err = service.GetServer(ctx, 111, server.ID)
+	assert.NoError(t, err)
 }
 
-func TestServerService_GetServerConfig_NotFound(t *testing.T) {
+func TestServerService_SetQuery(t *testing.T) {
 	mockStorage := NewMockStorage()
 	mcClient := minecraft.NewClient(5 * time.Second)
-	service := NewServerService(mockStorage, mcClient)
+	service := NewServerService(mockStorage, mockStorage, clientProviders(mcClient), mcClient)
 
 	ctx := context.Background()
+	require.NoError(t, service.SetServerConfig(ctx, 111, 0, models.EditionJava, "mc.example.com", 25565, "Main"))
+	server, err := mockStorage.firstByChatID(ctx, 111)
+	require.NoError(t, err)
 
-	_, err := service.GetServerConfig(ctx, 99999)
-	assert.Error(t, err)
+	updated, err := service.SetQuery(ctx, 111, server.ID, true, 25575)
+	require.NoError(t, err)
+	assert.True(t, updated.QueryEnabled)
+	assert.Equal(t, 25575, updated.QueryTargetPort())
 
+	// Renaming the server through /set keeps its query settings
+	require.NoError(t, service.SetServerConfig(ctx, 111, 0, models.EditionJava, "mc.example.com", 25565, "Renamed"))
+	server, err = mockStorage.GetByID(ctx, server.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", server.Name)
+	assert.True(t, server.QueryEnabled)
+
+	_, err = service.SetQuery(ctx, 222, server.ID, false, 0)
 	var notFound storage.ErrNotFound
 	assert.ErrorAs(t, err, &notFound)
 }
 
+func TestServerService_SetQuery_Bedrock(t *testing.T) {
+	mockStorage := NewMockStorage()
+	mcClient := minecraft.NewClient(5 * time.Second)
+	service := NewServerService(mockStorage, mockStorage, clientProviders(mcClient), mcClient)
+
+	ctx := context.Background()
+	require.NoError(t, service.SetServerConfig(ctx, 111, 0, models.EditionBedrock, "bedrock.example.com", 19132, ""))
+	server, err := mockStorage.firstByChatID(ctx, 111)
+	require.NoError(t, err)
+
+	_, err = service.SetQuery(ctx, 111, server.ID, true, 0)
+	assert.Error(t, err)
+}
+
+func TestServerService_SetProtocolVersion(t *testing.T) {
+	mockStorage := NewMockStorage()
+	service := NewServerService(mockStorage, mockStorage, StatusProviders{}, nil)
+
+	ctx := context.Background()
+	require.NoError(t, service.SetServerConfig(ctx, 111, 0, models.EditionJava, "mc.example.com", 25565, "Main"))
+	require.NoError(t, service.SetServerConfig(ctx, 111, 0, models.EditionBedrock, "bedrock.example.com", 19132, ""))
+	servers, err := mockStorage.ListByChatID(ctx, 111)
+	require.NoError(t, err)
+	require.Len(t, servers, 2)
+
+	server, err := service.SetProtocolVersion(ctx, 111, servers[0].ID, 47)
+	require.NoError(t, err)
+	assert.Equal(t, 47, server.ProtocolVersion)
+
+	// Setting the address again keeps the protocol version
+	require.NoError(t, service.SetServerConfig(ctx, 111, 0, models.EditionJava, "mc.example.com", 25565, "Renamed"))
+	stored, err := mockStorage.GetByID(ctx, servers[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, 47, stored.ProtocolVersion)
+
+	_, err = service.SetProtocolVersion(ctx, 111, servers[0].ID, -1)
+	assert.Error(t, err)
+	_, err = service.SetProtocolVersion(ctx, 111, servers[1].ID, 47)
+	assert.Error(t, err)
+	_, err = service.SetProtocolVersion(ctx, 222, servers[0].ID, 47)
+	assert.Error(t, err)
+}
+
+func TestServerService_CheckServer_RecordsFailure(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	mockStorage := NewMockStorage()
+	mcClient := minecraft.NewClient(2 * time.Second)
+	service := NewServerService(mockStorage, mockStorage, clientProviders(mcClient), mcClient)
+
+	ctx := context.Background()
+	require.NoError(t, service.SetServerConfig(ctx, 111, 0, models.EditionJava, "127.0.0.1", port, "Local"))
+	server, err := mockStorage.firstByChatID(ctx, 111)
+	require.NoError(t, err)
+
+	result, err := service.GetServerStatus(ctx, 111, server.ID)
+	require.NoError(t, err)
+	assert.False(t, result.Status.Online)
+	assert.Equal(t, minecraft.ErrorKindRefused, minecraft.ErrorKindOf(result.Error))
+	assert.Contains(t, result.FormatStatus(i18n.RU), "Причина: соединение отклонено")
+
+	records, err := mockStorage.listStatusHistory(ctx, server.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.False(t, records[0].Online)
+	assert.Equal(t, "refused", records[0].ErrorKind)
+}
+
+func TestServerService_CheckServer_ProviderPerEdition(t *testing.T) {
+	java := &fakeProvider{status: &minecraft.ServerStatus{Online: true, Version: "1.21"}}
+	bedrock := &fakeProvider{status: &minecraft.ServerStatus{Online: true, Version: "1.20.80"}}
+	mockStorage := NewMockStorage()
+	service := NewServerService(mockStorage, mockStorage, StatusProviders{
+		models.EditionJava:    java,
+		models.EditionBedrock: bedrock,
+	}, nil)
+
+	ctx := context.Background()
+	result := service.CheckServer(ctx, &models.Server{ID: 1, IP: "java.example.com", Port: 25565, Edition: models.EditionJava})
+	require.NoError(t, result.Error)
+	assert.Equal(t, "1.21", result.Status.Version)
+
+	result = service.CheckServer(ctx, &models.Server{ID: 2, IP: "pe.example.com", Port: 19132, Edition: models.EditionBedrock})
+	require.NoError(t, result.Error)
+	assert.Equal(t, "1.20.80", result.Status.Version)
+
+	// Servers stored before editions existed are Java servers; query needs a querier and is skipped without one
+	result = service.CheckServer(ctx, &models.Server{ID: 3, IP: "old.example.com", Port: 25565, QueryEnabled: true, ProtocolVersion: 47})
+	require.NoError(t, result.Error)
+
+	assert.Equal(t, []string{"java.example.com", "old.example.com"}, java.queried)
+	assert.Equal(t, []int{models.AutoProtocolVersion, 47}, java.protocols)
+	assert.Equal(t, []string{"pe.example.com:19132"}, bedrock.queried)
+}
+
+func TestServerService_CheckServer_NoProvider(t *testing.T) {
+	mockStorage := NewMockStorage()
+	service := NewServerService(mockStorage, mockStorage, StatusProviders{}, nil)
+
+	result := service.CheckServer(context.Background(), &models.Server{ID: 1, IP: "pe.example.com", Port: 19132, Edition: models.EditionBedrock})
+	require.Error(t, result.Error)
+	assert.False(t, result.Status.Online)
+	assert.Equal(t, minecraft.ErrorKindUnknown, minecraft.ErrorKindOf(result.Error))
+}
+
+func TestServerService_CheckServer_FakeServerWithQuery(t *testing.T) {
+	server := mctest.NewServer(mctest.WithQuery(), mctest.WithStatus(mctest.Status{
+		Version:    "Paper 1.20.4",
+		Protocol:   765,
+		MOTD:       "Survival",
+		MaxPlayers: 20,
+		Players:    []string{"Steve", "Alex"},
+		Map:        "world",
+		Plugins:    "Paper on Bukkit 1.20.4: LuckPerms 5.4.102",
+	}))
+	defer server.Close()
+
+	mockStorage := NewMockStorage()
+	mcClient := minecraft.NewClient(2 * time.Second)
+	service := NewServerService(mockStorage, mockStorage, clientProviders(mcClient), mcClient)
+
+	ctx := context.Background()
+	require.NoError(t, service.SetServerConfig(ctx, 111, 0, models.EditionJava, server.Host, server.Port, "Fake"))
+	stored, err := mockStorage.firstByChatID(ctx, 111)
+	require.NoError(t, err)
+	_, err = service.SetQuery(ctx, 111, stored.ID, true, server.QueryPort)
+	require.NoError(t, err)
+
+	result, err := service.GetServerStatus(ctx, 111, stored.ID)
+	require.NoError(t, err)
+	require.NoError(t, result.Error)
+	assert.True(t, result.Status.Online)
+	require.NotNil(t, result.Status.Query)
+	assert.Equal(t, "world", result.Status.Query.Map)
+
+	text := result.FormatStatus(i18n.RU)
+	assert.Contains(t, text, "Paper 1\\.20\\.4")
+	assert.Contains(t, text, "LuckPerms")
+	assert.Contains(t, text, "Alex")
+
+	records, err := mockStorage.listStatusHistory(ctx, stored.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.True(t, records[0].Online)
+}
+
+func TestServerService_CheckAddress(t *testing.T) {
+	server := mctest.NewServer(mctest.WithStatus(mctest.Status{
+		Version:    "Paper 1.20.4",
+		Protocol:   765,
+		MOTD:       "Survival\nand more",
+		MaxPlayers: 20,
+		Players:    []string{"Steve"},
+	}))
+	defer server.Close()
+
+	mockStorage := NewMockStorage()
+	mcClient := minecraft.NewClient(2 * time.Second)
+	service := NewServerService(mockStorage, mockStorage, clientProviders(mcClient), mcClient)
+
+	result := service.CheckAddress(context.Background(), models.EditionJava, server.Host, server.Port)
+	require.NoError(t, result.Error)
+	assert.True(t, result.Status.Online)
+	assert.Equal(t, "🟢 Онлайн 1/20 · Paper 1.20.4 · Survival and more", result.FormatSummary(i18n.RU))
+	assert.Empty(t, mockStorage.history, "unregistered servers are not recorded")
+}
+
+// fakeResolver resolves host names from a map and has no SRV records
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	return "", nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+func TestServerService_CheckPublicAddress(t *testing.T) {
+	java := &fakeProvider{status: &minecraft.ServerStatus{Online: true, Version: "1.21"}}
+	mockStorage := NewMockStorage()
+	resolver := fakeResolver{
+		"mc.example.com":  {"203.0.113.7"},
+		"lan.example.com": {"192.168.0.10"},
+	}
+	service := NewServerService(mockStorage, mockStorage, StatusProviders{models.EditionJava: java}, nil,
+		WithAddressResolver(resolver))
+	ctx := context.Background()
+
+	result := service.CheckPublicAddress(ctx, models.EditionJava, "mc.example.com", 25565)
+	require.NoError(t, result.Error)
+	assert.True(t, result.Status.Online)
+	assert.Equal(t, "mc.example.com", result.Server.IP, "the result shows the typed address")
+
+	for _, host := range []string{"lan.example.com", "127.0.0.1", "169.254.169.254"} {
+		result = service.CheckPublicAddress(ctx, models.EditionJava, host, 25565)
+		assert.False(t, result.Status.Online, host)
+		assert.ErrorIs(t, result.Error, minecraft.ErrPrivateAddress, host)
+		assert.Equal(t, "адрес ведёт во внутреннюю сеть", ExplainError(i18n.RU, result.Error))
+	}
+
+	assert.Equal(t, []string{"203.0.113.7"}, java.queried, "private addresses are never queried")
+}
+
+func TestServerService_RecentStatus(t *testing.T) {
+	java := &fakeProvider{status: &minecraft.ServerStatus{Online: true, CheckedAt: time.Now()}}
+	mockStorage := NewMockStorage()
+	service := NewServerService(mockStorage, mockStorage, StatusProviders{models.EditionJava: java}, nil)
+	ctx := context.Background()
+
+	require.NoError(t, service.SetServerConfig(ctx, 111, 0, models.EditionJava, "mc.example.com", 25565, "Main"))
+	server, err := mockStorage.firstByChatID(ctx, 111)
+	require.NoError(t, err)
+
+	_, ok := service.RecentStatus(server, time.Minute)
+	assert.False(t, ok, "the server was never checked")
+
+	service.CheckServer(ctx, server)
+	result, ok := service.RecentStatus(server, time.Minute)
+	require.True(t, ok)
+	assert.True(t, result.Status.Online)
+	assert.Same(t, server, result.Server)
+
+	_, ok = service.RecentStatus(server, 0)
+	assert.False(t, ok, "statuses older than maxAge are not reused")
+
+	require.NoError(t, service.RemoveServer(ctx, 111, server.ID))
+	_, ok = service.RecentStatus(server, time.Minute)
+	assert.False(t, ok, "statuses of removed servers are forgotten")
+}
+
+func TestServerService_ListUserServers(t *testing.T) {
+	mockStorage := NewMockStorage()
+	service := NewServerService(mockStorage, mockStorage, StatusProviders{}, nil)
+	ctx := context.Background()
+
+	require.NoError(t, service.SetServerConfig(ctx, -100, 42, models.EditionJava, "mc.example.com", 25565, "Group"))
+	require.NoError(t, service.SetServerConfig(ctx, -200, 42, models.EditionJava, "mc.example.com", 25565, "Other group"))
+	require.NoError(t, service.SetServerConfig(ctx, 42, 0, models.EditionBedrock, "pe.example.com", 19132, ""))
+	require.NoError(t, service.SetServerConfig(ctx, -100, 7, models.EditionJava, "other.example.com", 25565, ""))
+
+	servers, err := service.ListUserServers(ctx, 42)
+	require.NoError(t, err)
+	require.Len(t, servers, 2, "a server registered in several chats is listed once")
+	assert.Equal(t, "Group", servers[0].Name)
+	assert.Equal(t, "pe.example.com", servers[1].IP)
+}
+
+func TestServerStatusResult_FormatSummary_Offline(t *testing.T) {
+	result := &ServerStatusResult{
+		Server: &models.Server{IP: "mc.example.com", Port: 25565},
+		Status: &minecraft.ServerStatus{},
+		Error:  &minecraft.StatusError{Kind: minecraft.ErrorKindTimeout, Err: context.DeadlineExceeded},
+	}
+
+	assert.Equal(t, "🔴 Недоступен: "+ExplainError(i18n.RU, result.Error), result.FormatSummary(i18n.RU))
+}
+
+func TestServerService_CheckServer_RecordsCachedResultOnce(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	mockStorage := NewMockStorage()
+	mcClient := minecraft.NewClient(2 * time.Second)
+	providers := StatusProviders{
+		models.EditionJava: minecraft.NewCachedProvider(minecraft.JavaClient{Client: mcClient}, time.Minute),
+	}
+	service := NewServerService(mockStorage, mockStorage, providers, mcClient)
+
+	ctx := context.Background()
+	require.NoError(t, service.SetServerConfig(ctx, 111, 0, models.EditionJava, "127.0.0.1", port, "Local"))
+	require.NoError(t, service.SetServerConfig(ctx, 222, 0, models.EditionJava, "127.0.0.1", port, "Shared"))
+	first, err := mockStorage.firstByChatID(ctx, 111)
+	require.NoError(t, err)
+	second, err := mockStorage.firstByChatID(ctx, 222)
+	require.NoError(t, err)
+
+	a := service.CheckServer(ctx, first)
+	b := service.CheckServer(ctx, first)
+	assert.Equal(t, a.Status.CheckedAt, b.Status.CheckedAt, "second check is served from the cache")
+	service.CheckServer(ctx, second)
+
+	records, err := mockStorage.listStatusHistory(ctx, first.ID, 10)
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+
+	// Another chat watching the same server still gets its own record
+	records, err = mockStorage.listStatusHistory(ctx, second.ID, 10)
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+}
+
+func TestFormatAge(t *testing.T) {
+	assert.Equal(t, "только что", formatAge(i18n.RU, 300*time.Millisecond))
+	assert.Equal(t, "12 с назад", formatAge(i18n.RU, 12*time.Second))
+	assert.Equal(t, "3 мин назад", formatAge(i18n.RU, 3*time.Minute+20*time.Second))
+}
+
+func TestServerStatusResult_FormatCheckedAt(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	result := &ServerStatusResult{
+		Server: &models.Server{IP: "mc.example.com", Port: 25565},
+		Status: &minecraft.ServerStatus{Online: true},
+	}
+
+	assert.Empty(t, result.formatCheckedAt(i18n.RU, now))
+
+	result.Status.CheckedAt = now.Add(-15 * time.Second)
+	assert.Equal(t, "\n\n🕒 _Проверено 15 с назад_", result.formatCheckedAt(i18n.RU, now))
+
+	result.Status.CheckedAt = time.Now()
+	assert.True(t, strings.HasSuffix(result.FormatStatus(i18n.RU), "\n\n🕒 _Проверено только что_"))
+}
+
+func TestExplainError(t *testing.T) {
+	timeout := &minecraft.StatusError{Kind: minecraft.ErrorKindTimeout, Err: errors.New("i/o timeout")}
+	assert.Equal(t, "сервер не ответил вовремя", ExplainError(i18n.RU, timeout))
+
+	dns := &minecraft.StatusError{Kind: minecraft.ErrorKindDNS, Err: errors.New("no such host")}
+	assert.Contains(t, ExplainError(i18n.RU, dns), "DNS")
+
+	assert.Equal(t, "неизвестная ошибка", ExplainError(i18n.RU, context.Canceled))
+}
+
+func TestFormatServerSettings(t *testing.T) {
+	server := &models.Server{IP: "mc.example.com", Port: 25565, Name: "Main", QueryEnabled: true}
+
+	result := FormatServerSettings(i18n.RU, server)
+	assert.Contains(t, result, "Main")
+	assert.Contains(t, result, "Протокол: авто")
+	assert.Contains(t, result, "Query: вкл, порт `25565`")
+	assert.Contains(t, result, "RCON: выкл")
+
+	server.ProtocolVersion = 47
+	assert.Contains(t, FormatServerSettings(i18n.RU, server), "Протокол: `47`")
+
+	bedrock := &models.Server{IP: "bedrock.example.com", Port: 19132, Edition: models.EditionBedrock}
+	assert.NotContains(t, FormatServerSettings(i18n.RU, bedrock), "Query")
+	assert.NotContains(t, FormatServerSettings(i18n.RU, bedrock), "Протокол")
+	assert.NotContains(t, FormatServerSettings(i18n.RU, bedrock), "RCON")
+}
+
+func TestFormatConfig_MultipleServers(t *testing.T) {
+	servers := []*models.Server{
+		{IP: "lobby.example.com", Port: 25565, Name: "Lobby"},
+		{IP: "creative.example.com", Port: 25566, Name: "Creative"},
+	}
+
+	result := FormatConfig(i18n.RU, servers)
+
+	assert.Contains(t, result, "Lobby")
+	assert.Contains(t, result, "creative.example.com")
+	assert.Contains(t, result, "25566")
+}
+
 func TestFormatConfig_NoServer(t *testing.T) {
-	result := FormatConfig(nil)
+	result := FormatConfig(i18n.RU, nil)
 
 	assert.Contains(t, result, "Сервер не настроен")
 	assert.Contains(t, result, "/set")
@@ -121,7 +623,7 @@ func TestFormatConfig_WithServer(t *testing.T) {
 		Name: "Test Server",
 	}
 
-	result := FormatConfig(server)
+	result := FormatConfig(i18n.RU, []*models.Server{server})
 
 	assert.Contains(t, result, "mc.example.com")
 	assert.Contains(t, result, "25565")
@@ -137,11 +639,13 @@ func TestEscapeMarkdown(t *testing.T) {
 		{"test*bold*", "test\\*bold\\*"},
 		{"normal text", "normal text"},
 		{"[link](url)", "\\[link\\]\\(url\\)"},
+		{"Сервер (основной)", "Сервер \\(основной\\)"},
+		{"C:\\path", "C:\\\\path"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			result := escapeMarkdown(tt.input)
+			result := EscapeMarkdown(tt.input)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -152,7 +656,7 @@ func TestServerStatusResult_FormatStatus_NoServer(t *testing.T) {
 		Server: nil,
 	}
 
-	formatted := result.FormatStatus()
+	formatted := result.FormatStatus(i18n.RU)
 	assert.Contains(t, formatted, "Сервер не настроен")
 }
 
@@ -168,7 +672,7 @@ func TestServerStatusResult_FormatStatus_Offline(t *testing.T) {
 		},
 	}
 
-	formatted := result.FormatStatus()
+	formatted := result.FormatStatus(i18n.RU)
 	assert.Contains(t, formatted, "🔴")
 	assert.Contains(t, formatted, "Недоступен")
 }
@@ -194,10 +698,185 @@ func TestServerStatusResult_FormatStatus_Online(t *testing.T) {
 		},
 	}
 
-	formatted := result.FormatStatus()
+	formatted := result.FormatStatus(i18n.RU)
 	assert.Contains(t, formatted, "🟢")
 	assert.Contains(t, formatted, "1\\.20\\.4") // escaped for MarkdownV2
 	assert.Contains(t, formatted, "5/20")
 	assert.Contains(t, formatted, "Player1")
 	assert.Contains(t, formatted, "Player2")
 }
+
+func TestServerStatusResult_FormatStatus_English(t *testing.T) {
+	result := &ServerStatusResult{
+		Server: &models.Server{IP: "mc.example.com", Port: 25565, Name: "Test Server"},
+		Status: &minecraft.ServerStatus{
+			Online:  true,
+			Version: "1.20.4",
+			Players: minecraft.PlayersInfo{Online: 5, Max: 20},
+		},
+	}
+
+	formatted := result.FormatStatus(i18n.EN)
+	assert.Contains(t, formatted, "Version: 1\\.20\\.4")
+	assert.Contains(t, formatted, "Online: 5/20")
+	assert.NotContains(t, formatted, "Версия")
+
+	result.Status = &minecraft.ServerStatus{Online: false}
+	result.Error = errors.New("dial tcp: connection refused")
+	assert.Contains(t, result.FormatStatus(i18n.EN), "Status: Offline")
+}
+
+func TestServerStatusResult_FormatStatus_Bedrock(t *testing.T) {
+	result := &ServerStatusResult{
+		Server: &models.Server{
+			IP:      "bedrock.example.com",
+			Port:    19132,
+			Name:    "Bedrock",
+			Edition: models.EditionBedrock,
+		},
+		Status: &minecraft.ServerStatus{
+			Online:    true,
+			Version:   "1.20.10",
+			Edition:   "MCPE",
+			GameMode:  "Survival",
+			LevelName: "Bedrock level",
+			Players:   minecraft.PlayersInfo{Online: 2, Max: 10},
+		},
+	}
+
+	formatted := result.FormatStatus(i18n.RU)
+	assert.Contains(t, formatted, "bedrock.example.com:19132")
+	assert.Contains(t, formatted, "Издание: Bedrock")
+	assert.Contains(t, formatted, "Режим: Survival")
+	assert.Contains(t, formatted, "Мир: Bedrock level")
+	assert.Contains(t, formatted, "2/10")
+}
+
+func TestServerStatusResult_FormatStatus_JavaHasNoBedrockDetails(t *testing.T) {
+	result := &ServerStatusResult{
+		Server: &models.Server{IP: "mc.example.com", Port: 25565, Edition: models.EditionJava},
+		Status: &minecraft.ServerStatus{Online: true, Version: "1.20.4"},
+	}
+
+	formatted := result.FormatStatus(i18n.RU)
+	assert.NotContains(t, formatted, "Издание")
+}
+
+func TestServerStatusResult_FormatStatus_MOTD(t *testing.T) {
+	result := &ServerStatusResult{
+		Server: &models.Server{IP: "mc.example.com", Port: 25565, Name: "Test Server"},
+		Status: &minecraft.ServerStatus{
+			Online:  true,
+			Version: "1.20.4",
+			MOTD:    minecraft.ParseLegacyText("   §6§lHypixel§r Network\n  §cSALE!  "),
+		},
+	}
+
+	formatted := result.FormatStatus(i18n.RU)
+	assert.Contains(t, formatted, "🟢 *Test Server*\n\n*Hypixel* Network\nSALE\\!\n\nАдрес:")
+}
+
+func TestServerStatusResult_FormatStatus_Latency(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  minecraft.ServerStatus
+		want    string
+		notWant string
+	}{
+		{
+			name:   "good",
+			status: minecraft.ServerStatus{Latency: 42 * time.Millisecond, DNSTime: 3 * time.Millisecond, ConnectTime: 5 * time.Millisecond},
+			want:   "Пинг: 🟢 42 мс \\(DNS 3 мс, подключение 5 мс\\)\n",
+		},
+		{
+			name:   "fair",
+			status: minecraft.ServerStatus{Latency: 180 * time.Millisecond},
+			want:   "Пинг: 🟡 180 мс\n",
+		},
+		{
+			name:   "poor",
+			status: minecraft.ServerStatus{Latency: 400 * time.Millisecond},
+			want:   "Пинг: 🔴 400 мс\n",
+		},
+		{
+			name:    "not measured",
+			status:  minecraft.ServerStatus{},
+			notWant: "Пинг",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.status
+			status.Online = true
+			status.Version = "1.20.4"
+			result := &ServerStatusResult{
+				Server: &models.Server{IP: "mc.example.com", Port: 25565},
+				Status: &status,
+			}
+
+			formatted := result.FormatStatus(i18n.RU)
+			if tt.want != "" {
+				assert.Contains(t, formatted, tt.want)
+			}
+			if tt.notWant != "" {
+				assert.NotContains(t, formatted, tt.notWant)
+			}
+		})
+	}
+}
+
+func TestServerStatusResult_FormatStatus_SRVTarget(t *testing.T) {
+	result := &ServerStatusResult{
+		Server: &models.Server{IP: "play.example.net", Port: 25565},
+		Status: &minecraft.ServerStatus{
+			Online:       true,
+			Version:      "1.20.4",
+			ResolvedHost: "mc1.example.net",
+			ResolvedPort: 25570,
+		},
+	}
+
+	formatted := result.FormatStatus(i18n.RU)
+	assert.Contains(t, formatted, "`play.example.net` → `mc1.example.net:25570`")
+}
+
+func TestServerStatusResult_FormatStatus_Query(t *testing.T) {
+	result := &ServerStatusResult{
+		Server: &models.Server{IP: "mc.example.com", Port: 25565, QueryEnabled: true},
+		Status: &minecraft.ServerStatus{
+			Online:  true,
+			Version: "1.20.4",
+			Players: minecraft.PlayersInfo{Online: 1, Max: 20, Sample: []minecraft.Player{{Name: "Steve"}}},
+			Query: &minecraft.QueryStatus{
+				Map:      "world",
+				Software: "Paper on Bukkit 1.20.4",
+				Plugins:  []minecraft.Plugin{{Name: "LuckPerms"}, {Name: "EssentialsX"}},
+			},
+		},
+	}
+
+	formatted := result.FormatStatus(i18n.RU)
+	assert.Contains(t, formatted, "Карта: world")
+	assert.Contains(t, formatted, "ПО: Paper on Bukkit 1\\.20\\.4")
+	assert.Contains(t, formatted, "Плагины \\(2\\): LuckPerms, EssentialsX")
+}
+
+func TestServerStatusResult_FormatStatus_LongPlayerList(t *testing.T) {
+	sample := make([]minecraft.Player, maxListedPlayers+5)
+	for i := range sample {
+		sample[i] = minecraft.Player{Name: fmt.Sprintf("Player%d", i)}
+	}
+	result := &ServerStatusResult{
+		Server: &models.Server{IP: "mc.example.com", Port: 25565},
+		Status: &minecraft.ServerStatus{
+			Online:  true,
+			Players: minecraft.PlayersInfo{Online: len(sample), Max: 100, Sample: sample},
+		},
+	}
+
+	formatted := result.FormatStatus(i18n.RU)
+	assert.Contains(t, formatted, fmt.Sprintf("Player%d", maxListedPlayers-1))
+	assert.NotContains(t, formatted, fmt.Sprintf("Player%d", maxListedPlayers))
+	assert.Contains(t, formatted, "и ещё 5")
+}
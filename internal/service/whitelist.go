@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/minecraft"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+// whitelistPerPage is the number of players on one page of the whitelist
+const whitelistPerPage = 20
+
+// ErrInvalidPlayerName is returned for names that cannot belong to a player
+var ErrInvalidPlayerName = errors.New("invalid player name")
+
+// playerNamePattern matches Java player names and Bedrock ones prefixed by Floodgate
+var playerNamePattern = regexp.MustCompile(`^\.?[A-Za-z0-9_]{1,16}$`)
+
+// Actor is the Telegram user who makes a change
+type Actor struct {
+	UserID   int64
+	Username string
+}
+
+// Whitelist is the list of players allowed to join a server
+type Whitelist struct {
+	Server  *models.Server
+	Players []string
+}
+
+// IsValidPlayerName reports whether a name can belong to a player
+func IsValidPlayerName(name string) bool {
+	return playerNamePattern.MatchString(name)
+}
+
+// Whitelist returns the whitelist of a server registered in a chat, read with the `whitelist list` command.
+func (s *RCONService) Whitelist(ctx context.Context, chatID, serverID int64) (*Whitelist, error) {
+	output, err := s.Execute(ctx, chatID, serverID, "whitelist list")
+	if err != nil {
+		return nil, err
+	}
+
+	server, err := s.servers.GetServer(ctx, chatID, serverID)
+	if err != nil {
+		return nil, err
+	}
+	return &Whitelist{Server: server, Players: ParseWhitelist(output)}, nil
+}
+
+// AddToWhitelist adds a player to the whitelist of a server and returns the server's reply.
+func (s *RCONService) AddToWhitelist(ctx context.Context, chatID, serverID int64, player string, actor Actor) (string, error) {
+	return s.changeWhitelist(ctx, chatID, serverID, "add", player, actor)
+}
+
+// RemoveFromWhitelist removes a player from the whitelist of a server and returns the server's reply.
+func (s *RCONService) RemoveFromWhitelist(ctx context.Context, chatID, serverID int64, player string, actor Actor) (string, error) {
+	return s.changeWhitelist(ctx, chatID, serverID, "remove", player, actor)
+}
+
+// changeWhitelist runs a whitelist subcommand for a player, logging who made the change
+func (s *RCONService) changeWhitelist(
+	ctx context.Context,
+	chatID, serverID int64,
+	action, player string,
+	actor Actor,
+) (string, error) {
+	if !IsValidPlayerName(player) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPlayerName, player)
+	}
+
+	output, err := s.Execute(ctx, chatID, serverID, "whitelist "+action+" "+player)
+	if err != nil {
+		return "", err
+	}
+	output = minecraft.ParseLegacyText(output).String()
+
+	log.Info().
+		Int64("chat_id", chatID).
+		Int64("server_id", serverID).
+		Int64("user_id", actor.UserID).
+		Str("username", actor.Username).
+		Str("action", action).
+		Str("player", player).
+		Str("reply", output).
+		Msg("whitelist changed")
+	return output, nil
+}
+
+// ParseWhitelist extracts player names from the reply to `whitelist list`, sorted case-insensitively.
+// Vanilla replies with "There are 2 whitelisted player(s): Alex, Steve" and "There are no whitelisted players";
+// before 1.13 the last two names were joined with "and".
+func ParseWhitelist(output string) []string {
+	text := minecraft.ParseLegacyText(output).String()
+	_, list, found := strings.Cut(text, ":")
+	if !found {
+		return nil
+	}
+	if i := strings.LastIndex(list, " and "); i >= 0 {
+		list = list[:i] + "," + list[i+len(" and "):]
+	}
+
+	players := strings.FieldsFunc(list, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	sort.Slice(players, func(i, j int) bool {
+		return strings.ToLower(players[i]) < strings.ToLower(players[j])
+	})
+	return players
+}
+
+// Pages returns the number of pages of the whitelist, at least one.
+func (w *Whitelist) Pages() int {
+	return max(1, (len(w.Players)+whitelistPerPage-1)/whitelistPerPage)
+}
+
+// Page returns the players on a page of the whitelist. Pages are numbered from zero
+// and clamped to the available ones.
+func (w *Whitelist) Page(page int) []string {
+	page = max(0, min(page, w.Pages()-1))
+	end := min((page+1)*whitelistPerPage, len(w.Players))
+	return w.Players[page*whitelistPerPage : end]
+}
+
+// Format formats a page of the whitelist for display.
+func (w *Whitelist) Format(lang i18n.Lang, page int) string {
+	var sb strings.Builder
+	sb.WriteString(lang.T("whitelist.title", EscapeMarkdown(ServerTitle(w.Server))) + "\n\n")
+
+	if len(w.Players) == 0 {
+		sb.WriteString(lang.T("whitelist.empty"))
+		return sb.String()
+	}
+
+	sb.WriteString(lang.N("whitelist.count", len(w.Players)) + "\n\n")
+	for _, player := range w.Page(page) {
+		fmt.Fprintf(&sb, "• %s\n", EscapeMarkdown(player))
+	}
+
+	sb.WriteString("\n" + lang.T("whitelist.hint"))
+	if pages := w.Pages(); pages > 1 {
+		sb.WriteString("\n" + lang.T("page", max(0, min(page, pages-1))+1, pages))
+	}
+	return sb.String()
+}
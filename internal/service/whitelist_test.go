@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ykhdr/mss-bot/internal/i18n"
+	"github.com/ykhdr/mss-bot/internal/minecraft/mctest"
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+func TestParseWhitelist(t *testing.T) {
+	tests := []struct {
+		output  string
+		players []string
+	}{
+		{"There are no whitelisted players", nil},
+		{"There are 2 whitelisted player(s): Steve, alex", []string{"alex", "Steve"}},
+		{"There are 1 whitelisted players: §6.BedrockGuy", []string{".BedrockGuy"}},
+		{"There are 3 (out of 4 seen) whitelisted players:\nNotch, jeb_ and Dinnerbone", []string{"Dinnerbone", "jeb_", "Notch"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			assert.Equal(t, tt.players, ParseWhitelist(tt.output))
+		})
+	}
+}
+
+func TestIsValidPlayerName(t *testing.T) {
+	for _, name := range []string{"Steve", "jeb_", ".BedrockGuy", "a"} {
+		assert.True(t, IsValidPlayerName(name), name)
+	}
+	for _, name := range []string{"", "Steve Alex", "Steve;stop", "ThisNameIsTooLongToBeReal", "Стив"} {
+		assert.False(t, IsValidPlayerName(name), name)
+	}
+}
+
+func TestWhitelist_Format(t *testing.T) {
+	server := &models.Server{IP: "mc.example.com", Port: 25565, Name: "Main"}
+
+	empty := &Whitelist{Server: server}
+	assert.Equal(t, 1, empty.Pages())
+	assert.Empty(t, empty.Page(0))
+	assert.Contains(t, empty.Format(i18n.RU, 0), "Вайтлист пуст")
+
+	whitelist := &Whitelist{Server: server}
+	for i := range whitelistPerPage + 3 {
+		whitelist.Players = append(whitelist.Players, fmt.Sprintf("player_%02d", i))
+	}
+	assert.Equal(t, 2, whitelist.Pages())
+	assert.Len(t, whitelist.Page(0), whitelistPerPage)
+	assert.Len(t, whitelist.Page(5), 3, "pages are clamped")
+
+	text := whitelist.Format(i18n.RU, 1)
+	assert.Contains(t, text, "*Вайтлист Main*")
+	assert.Contains(t, text, "player\\_22")
+	assert.NotContains(t, text, "player\\_00")
+	assert.Contains(t, text, "Страница 2/2")
+}
+
+func TestRCONService_Whitelist(t *testing.T) {
+	commands := make(chan string, 10)
+	server := mctest.NewServer(mctest.WithRCON("hunter2", func(command string) string {
+		commands <- command
+		switch {
+		case command == "whitelist list":
+			return "There are 2 whitelisted player(s): Steve, Alex"
+		case strings.HasPrefix(command, "whitelist add "):
+			return "Added " + strings.TrimPrefix(command, "whitelist add ") + " to the whitelist"
+		default:
+			return ""
+		}
+	}))
+	defer server.Close()
+
+	mockStorage := NewMockStorage()
+	rcon := newTestRCONService(t, mockStorage)
+	ctx := context.Background()
+
+	require.NoError(t, rcon.servers.SetServerConfig(ctx, 111, 0, models.EditionJava, server.Host, server.Port, "Fake"))
+	stored, err := mockStorage.firstByChatID(ctx, 111)
+	require.NoError(t, err)
+	_, err = rcon.SetRCON(ctx, 111, stored.ID, server.RCONPort, "hunter2")
+	require.NoError(t, err)
+
+	whitelist, err := rcon.Whitelist(ctx, 111, stored.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Alex", "Steve"}, whitelist.Players)
+	assert.Equal(t, "whitelist list", <-commands)
+
+	actor := Actor{UserID: 42, Username: "admin"}
+	reply, err := rcon.AddToWhitelist(ctx, 111, stored.ID, "Notch", actor)
+	require.NoError(t, err)
+	assert.Equal(t, "Added Notch to the whitelist", reply)
+	assert.Equal(t, "whitelist add Notch", <-commands)
+
+	_, err = rcon.RemoveFromWhitelist(ctx, 111, stored.ID, "Alex", actor)
+	require.NoError(t, err)
+	assert.Equal(t, "whitelist remove Alex", <-commands)
+
+	_, err = rcon.AddToWhitelist(ctx, 111, stored.ID, "Steve; stop", actor)
+	assert.ErrorIs(t, err, ErrInvalidPlayerName)
+	assert.Equal(t, "неверное имя игрока", RCONErrorText(i18n.RU, err))
+	assert.Empty(t, commands, "invalid names are not sent to the server")
+}
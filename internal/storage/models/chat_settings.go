@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// ChatSettings represents per-chat bot preferences
+type ChatSettings struct {
+	ChatID              int64
+	PlayerNotifications bool
+	// Language is the code of the language chosen for the chat, empty to use the language of each user
+	Language string
+	// AllowEveryone lets every member of a group change its servers and settings, not only the chat admins
+	AllowEveryone bool
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// DefaultChatSettings returns the settings used for chats that have not changed anything
+func DefaultChatSettings(chatID int64) *ChatSettings {
+	return &ChatSettings{
+		ChatID: chatID,
+	}
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// PinnedMessage is a pinned status card of a server that is kept up to date in place
+type PinnedMessage struct {
+	ID        int64
+	ChatID    int64
+	ServerID  int64
+	MessageID int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
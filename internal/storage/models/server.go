@@ -2,17 +2,67 @@ package models
 
 import "time"
 
+// Edition is the Minecraft edition a server runs
+type Edition string
+
+const (
+	// EditionJava is Minecraft: Java Edition
+	EditionJava Edition = "java"
+	// EditionBedrock is Minecraft: Bedrock Edition (including Geyser proxies)
+	EditionBedrock Edition = "bedrock"
+)
+
 // Server represents a Minecraft server configuration for a chat
 type Server struct {
-	ID        int64
-	ChatID    int64
-	IP        string
-	Port      int
-	Name      string
+	ID           int64
+	ChatID       int64
+	IP           string
+	Port         int // zero for Java servers added without a port, whose SRV record is followed
+	Name         string
+	Edition      Edition
+	QueryEnabled bool // GameSpy4 full stat queries, needs enable-query=true on the server
+	QueryPort    int  // query.port of the server; zero means the game port
+	// ProtocolVersion is advertised in the Java handshake; AutoProtocolVersion uses the server's own protocol
+	ProtocolVersion int
+	// RCONPort is rcon.port of the server; zero disables the remote console
+	RCONPort int
+	// RCONPassword is the rcon.password of the server, encrypted; it is never stored in plain text
+	RCONPassword string
+	// AddedBy is the Telegram user who added the server, zero when unknown
+	AddedBy   int64
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// AutoProtocolVersion makes the bot learn the protocol version from the server's first status response
+const AutoProtocolVersion = 0
+
+// IsBedrock reports whether the server runs Bedrock Edition
+func (s *Server) IsBedrock() bool {
+	return s.Edition == EditionBedrock
+}
+
+// GamePort returns the port the server is reached on unless an SRV record redirects it
+func (s *Server) GamePort() int {
+	if s.Port == 0 {
+		return DefaultPort
+	}
+	return s.Port
+}
+
+// QueryTargetPort returns the port GameSpy4 queries are sent to
+func (s *Server) QueryTargetPort() int {
+	if s.QueryPort != 0 {
+		return s.QueryPort
+	}
+	return s.GamePort()
+}
+
+// RCONEnabled reports whether the remote console of the server is configured
+func (s *Server) RCONEnabled() bool {
+	return s.RCONPort != 0 && s.RCONPassword != ""
+}
+
 // DefaultPort is the default Minecraft server port
 const DefaultPort = 25565
 
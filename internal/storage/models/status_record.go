@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// StatusRecord is the stored outcome of a single server status check
+type StatusRecord struct {
+	ID            int64
+	ServerID      int64
+	Online        bool
+	PlayersOnline int
+	Latency       time.Duration // zero when not measured
+	ErrorKind     string        // empty when the check succeeded
+	Error         string
+	CheckedAt     time.Time
+}
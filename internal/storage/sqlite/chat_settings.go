@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+// GetChatSettings returns settings for a chat, or defaults when none are stored.
+func (s *Storage) GetChatSettings(ctx context.Context, chatID int64) (*models.ChatSettings, error) {
+	log.Debug().Int64("chat_id", chatID).Msg("getting chat settings")
+
+	query, args, err := s.sb.
+		Select("chat_id", "player_notifications", "language", "allow_everyone", "created_at", "updated_at").
+		From("chat_settings").
+		Where(squirrel.Eq{"chat_id": chatID}).
+		ToSql()
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Msg("failed to build query")
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var settings models.ChatSettings
+	err = s.db.QueryRowContext(ctx, query, args...).Scan(
+		&settings.ChatID,
+		&settings.PlayerNotifications,
+		&settings.Language,
+		&settings.AllowEveryone,
+		&settings.CreatedAt,
+		&settings.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		log.Debug().Int64("chat_id", chatID).Msg("chat settings not found, using defaults")
+		return models.DefaultChatSettings(chatID), nil
+	}
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Msg("failed to get chat settings")
+		return nil, fmt.Errorf("failed to get chat settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// UpsertChatSettings creates or updates settings for a chat.
+func (s *Storage) UpsertChatSettings(ctx context.Context, settings *models.ChatSettings) error {
+	log.Debug().
+		Int64("chat_id", settings.ChatID).
+		Bool("player_notifications", settings.PlayerNotifications).
+		Str("language", settings.Language).
+		Bool("allow_everyone", settings.AllowEveryone).
+		Msg("upserting chat settings")
+
+	now := time.Now()
+
+	query, args, err := s.sb.
+		Insert("chat_settings").
+		Columns("chat_id", "player_notifications", "language", "allow_everyone", "created_at", "updated_at").
+		Values(settings.ChatID, settings.PlayerNotifications, settings.Language, settings.AllowEveryone, now, now).
+		Suffix("ON CONFLICT(chat_id) DO UPDATE SET " +
+			"player_notifications = excluded.player_notifications, " +
+			"language = excluded.language, " +
+			"allow_everyone = excluded.allow_everyone, " +
+			"updated_at = excluded.updated_at").
+		ToSql()
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", settings.ChatID).Msg("failed to build upsert query")
+		return fmt.Errorf("failed to build upsert query: %w", err)
+	}
+
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		log.Error().Err(err).Int64("chat_id", settings.ChatID).Msg("failed to upsert chat settings")
+		return fmt.Errorf("failed to upsert chat settings: %w", err)
+	}
+
+	if settings.CreatedAt.IsZero() {
+		settings.CreatedAt = now
+	}
+	settings.UpdatedAt = now
+	log.Info().Int64("chat_id", settings.ChatID).Msg("chat settings saved")
+	return nil
+}
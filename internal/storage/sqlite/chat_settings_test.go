@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+func TestStorage_GetChatSettings_Defaults(t *testing.T) {
+	s := setupTestDB(t)
+	ctx := context.Background()
+
+	settings, err := s.GetChatSettings(ctx, 12345)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(12345), settings.ChatID)
+	assert.False(t, settings.PlayerNotifications)
+}
+
+func TestStorage_UpsertChatSettings(t *testing.T) {
+	s := setupTestDB(t)
+	ctx := context.Background()
+
+	err := s.UpsertChatSettings(ctx, &models.ChatSettings{ChatID: 12345, PlayerNotifications: true})
+	require.NoError(t, err)
+
+	settings, err := s.GetChatSettings(ctx, 12345)
+	require.NoError(t, err)
+	assert.True(t, settings.PlayerNotifications)
+
+	settings.PlayerNotifications = false
+	require.NoError(t, s.UpsertChatSettings(ctx, settings))
+
+	settings, err = s.GetChatSettings(ctx, 12345)
+	require.NoError(t, err)
+	assert.False(t, settings.PlayerNotifications)
+}
+
+func TestStorage_UpsertChatSettings_Language(t *testing.T) {
+	s := setupTestDB(t)
+	ctx := context.Background()
+
+	settings, err := s.GetChatSettings(ctx, 12345)
+	require.NoError(t, err)
+	assert.Empty(t, settings.Language)
+
+	settings.Language = "en"
+	require.NoError(t, s.UpsertChatSettings(ctx, settings))
+
+	settings, err = s.GetChatSettings(ctx, 12345)
+	require.NoError(t, err)
+	assert.Equal(t, "en", settings.Language)
+	assert.False(t, settings.PlayerNotifications)
+}
+
+func TestStorage_UpsertChatSettings_AllowEveryone(t *testing.T) {
+	s := setupTestDB(t)
+	ctx := context.Background()
+
+	settings, err := s.GetChatSettings(ctx, -100123)
+	require.NoError(t, err)
+	assert.False(t, settings.AllowEveryone)
+
+	settings.AllowEveryone = true
+	require.NoError(t, s.UpsertChatSettings(ctx, settings))
+
+	settings, err = s.GetChatSettings(ctx, -100123)
+	require.NoError(t, err)
+	assert.True(t, settings.AllowEveryone)
+}
@@ -22,6 +22,71 @@ var migrations = []Migration{
 		Up:      upCreateServersTable,
 		Down:    downCreateServersTable,
 	},
+	{
+		Version: 2,
+		Up:      upMultipleServersPerChat,
+		Down:    downMultipleServersPerChat,
+	},
+	{
+		Version: 3,
+		Up:      upCreateChatSettingsTable,
+		Down:    downCreateChatSettingsTable,
+	},
+	{
+		Version: 4,
+		Up:      upAddServerEdition,
+		Down:    downAddServerEdition,
+	},
+	{
+		Version: 5,
+		Up:      upAddServerQuery,
+		Down:    downAddServerQuery,
+	},
+	{
+		Version: 6,
+		Up:      upCreateStatusHistoryTable,
+		Down:    downCreateStatusHistoryTable,
+	},
+	{
+		Version: 7,
+		Up:      upAddStatusHistoryLatency,
+		Down:    downAddStatusHistoryLatency,
+	},
+	{
+		Version: 8,
+		Up:      upAddServerProtocolVersion,
+		Down:    downAddServerProtocolVersion,
+	},
+	{
+		Version: 9,
+		Up:      upAddServerRCON,
+		Down:    downAddServerRCON,
+	},
+	{
+		Version: 10,
+		Up:      upCreatePinnedMessagesTable,
+		Down:    downCreatePinnedMessagesTable,
+	},
+	{
+		Version: 11,
+		Up:      upAddServerAddedBy,
+		Down:    downAddServerAddedBy,
+	},
+	{
+		Version: 12,
+		Up:      upAddChatSettingsLanguage,
+		Down:    downAddChatSettingsLanguage,
+	},
+	{
+		Version: 13,
+		Up:      upAddChatSettingsAllowEveryone,
+		Down:    downAddChatSettingsAllowEveryone,
+	},
+	{
+		Version: 14,
+		Up:      upImplicitJavaPort,
+		Down:    downImplicitJavaPort,
+	},
 }
 
 // RunMigrations executes all database migrations
@@ -108,6 +173,267 @@ func downCreateServersTable(ctx context.Context, db *sql.DB) error {
 	return err
 }
 
+// upMultipleServersPerChat drops the UNIQUE constraint on chat_id so a chat can
+// register several servers. SQLite cannot alter constraints, so the table is rebuilt.
+func upMultipleServersPerChat(ctx context.Context, db *sql.DB) error {
+	return rebuildServersTable(ctx, db, `
+		CREATE TABLE servers_new (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			chat_id INTEGER NOT NULL,
+			ip TEXT NOT NULL,
+			port INTEGER NOT NULL DEFAULT 25565,
+			name TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (chat_id, ip, port)
+		)
+	`, `
+		INSERT INTO servers_new (id, chat_id, ip, port, name, created_at, updated_at)
+		SELECT id, chat_id, ip, port, name, created_at, updated_at FROM servers
+	`)
+}
+
+// downMultipleServersPerChat restores one server per chat, keeping the oldest entry.
+func downMultipleServersPerChat(ctx context.Context, db *sql.DB) error {
+	return rebuildServersTable(ctx, db, `
+		CREATE TABLE servers_new (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			chat_id INTEGER NOT NULL UNIQUE,
+			ip TEXT NOT NULL,
+			port INTEGER NOT NULL DEFAULT 25565,
+			name TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`, `
+		INSERT INTO servers_new (id, chat_id, ip, port, name, created_at, updated_at)
+		SELECT id, chat_id, ip, port, name, created_at, updated_at FROM servers
+		WHERE id IN (SELECT MIN(id) FROM servers GROUP BY chat_id)
+	`)
+}
+
+// rebuildServersTable replaces the servers table with servers_new inside a transaction.
+func rebuildServersTable(ctx context.Context, db *sql.DB, createQuery, copyQuery string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // rollback after commit is a no-op
+
+	queries := []string{
+		createQuery,
+		copyQuery,
+		`DROP TABLE servers`,
+		`ALTER TABLE servers_new RENAME TO servers`,
+		`CREATE INDEX IF NOT EXISTS idx_servers_chat_id ON servers(chat_id)`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func upCreateChatSettingsTable(ctx context.Context, db *sql.DB) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS chat_settings (
+			chat_id INTEGER PRIMARY KEY,
+			player_notifications BOOLEAN NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+func downCreateChatSettingsTable(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "DROP TABLE IF EXISTS chat_settings")
+	return err
+}
+
+func upAddServerEdition(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "ALTER TABLE servers ADD COLUMN edition TEXT NOT NULL DEFAULT 'java'")
+	return err
+}
+
+func downAddServerEdition(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "ALTER TABLE servers DROP COLUMN edition")
+	return err
+}
+
+func upAddServerQuery(ctx context.Context, db *sql.DB) error {
+	queries := []string{
+		"ALTER TABLE servers ADD COLUMN query_enabled BOOLEAN NOT NULL DEFAULT 0",
+		"ALTER TABLE servers ADD COLUMN query_port INTEGER NOT NULL DEFAULT 0",
+	}
+	for _, query := range queries {
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func downAddServerQuery(ctx context.Context, db *sql.DB) error {
+	queries := []string{
+		"ALTER TABLE servers DROP COLUMN query_port",
+		"ALTER TABLE servers DROP COLUMN query_enabled",
+	}
+	for _, query := range queries {
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func upCreateStatusHistoryTable(ctx context.Context, db *sql.DB) error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS status_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			server_id INTEGER NOT NULL REFERENCES servers(id) ON DELETE CASCADE,
+			online BOOLEAN NOT NULL,
+			players_online INTEGER NOT NULL DEFAULT 0,
+			error_kind TEXT NOT NULL DEFAULT '',
+			error TEXT NOT NULL DEFAULT '',
+			checked_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_status_history_server_id ON status_history(server_id, id)`,
+	}
+	for _, query := range queries {
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func downCreateStatusHistoryTable(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "DROP TABLE IF EXISTS status_history")
+	return err
+}
+
+func upAddStatusHistoryLatency(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "ALTER TABLE status_history ADD COLUMN latency_ms INTEGER NOT NULL DEFAULT 0")
+	return err
+}
+
+func downAddStatusHistoryLatency(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "ALTER TABLE status_history DROP COLUMN latency_ms")
+	return err
+}
+
+func upAddServerProtocolVersion(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "ALTER TABLE servers ADD COLUMN protocol_version INTEGER NOT NULL DEFAULT 0")
+	return err
+}
+
+func downAddServerProtocolVersion(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "ALTER TABLE servers DROP COLUMN protocol_version")
+	return err
+}
+
+func upAddServerRCON(ctx context.Context, db *sql.DB) error {
+	queries := []string{
+		"ALTER TABLE servers ADD COLUMN rcon_port INTEGER NOT NULL DEFAULT 0",
+		"ALTER TABLE servers ADD COLUMN rcon_password TEXT NOT NULL DEFAULT ''",
+	}
+	for _, query := range queries {
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func downAddServerRCON(ctx context.Context, db *sql.DB) error {
+	queries := []string{
+		"ALTER TABLE servers DROP COLUMN rcon_password",
+		"ALTER TABLE servers DROP COLUMN rcon_port",
+	}
+	for _, query := range queries {
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func upCreatePinnedMessagesTable(ctx context.Context, db *sql.DB) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS pinned_messages (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			chat_id INTEGER NOT NULL,
+			server_id INTEGER NOT NULL REFERENCES servers(id) ON DELETE CASCADE,
+			message_id INTEGER NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(chat_id, server_id)
+		)
+	`
+	_, err := db.ExecContext(ctx, query)
+	return err
+}
+
+func downCreatePinnedMessagesTable(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "DROP TABLE IF EXISTS pinned_messages")
+	return err
+}
+
+func upAddServerAddedBy(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "ALTER TABLE servers ADD COLUMN added_by INTEGER NOT NULL DEFAULT 0")
+	return err
+}
+
+func downAddServerAddedBy(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "ALTER TABLE servers DROP COLUMN added_by")
+	return err
+}
+
+func upAddChatSettingsLanguage(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "ALTER TABLE chat_settings ADD COLUMN language TEXT NOT NULL DEFAULT ''")
+	return err
+}
+
+func downAddChatSettingsLanguage(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "ALTER TABLE chat_settings DROP COLUMN language")
+	return err
+}
+
+func upAddChatSettingsAllowEveryone(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "ALTER TABLE chat_settings ADD COLUMN allow_everyone BOOLEAN NOT NULL DEFAULT 0")
+	return err
+}
+
+func downAddChatSettingsAllowEveryone(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "ALTER TABLE chat_settings DROP COLUMN allow_everyone")
+	return err
+}
+
+// upImplicitJavaPort stores the default port of Java servers as 0, which makes their SRV record followed.
+// Servers added before could not be told apart from ones given the port explicitly, and all followed it.
+func upImplicitJavaPort(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "UPDATE servers SET port = 0 WHERE edition = 'java' AND port = 25565")
+	return err
+}
+
+// downImplicitJavaPort restores the default port, dropping servers that duplicate one given the port explicitly.
+func downImplicitJavaPort(ctx context.Context, db *sql.DB) error {
+	queries := []string{
+		"UPDATE OR IGNORE servers SET port = 25565 WHERE port = 0",
+		"DELETE FROM servers WHERE port = 0",
+	}
+	for _, query := range queries {
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Builder returns a squirrel statement builder configured for SQLite
 func Builder() squirrel.StatementBuilderType {
 	return squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question)
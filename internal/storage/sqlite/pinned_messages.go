@@ -0,0 +1,152 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+// pinnedMessageColumns lists the pinned_messages columns in the order scanPinnedMessage reads them
+var pinnedMessageColumns = []string{"id", "chat_id", "server_id", "message_id", "created_at", "updated_at"}
+
+func scanPinnedMessage(row rowScanner) (*models.PinnedMessage, error) {
+	var pin models.PinnedMessage
+	err := row.Scan(
+		&pin.ID,
+		&pin.ChatID,
+		&pin.ServerID,
+		&pin.MessageID,
+		&pin.CreatedAt,
+		&pin.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &pin, nil
+}
+
+// GetPinnedMessage returns the pinned status card of a server in a chat, or nil when there is none.
+func (s *Storage) GetPinnedMessage(ctx context.Context, chatID, serverID int64) (*models.PinnedMessage, error) {
+	log.Debug().Int64("chat_id", chatID).Int64("server_id", serverID).Msg("getting pinned message")
+
+	query, args, err := s.sb.
+		Select(pinnedMessageColumns...).
+		From("pinned_messages").
+		Where(squirrel.Eq{"chat_id": chatID, "server_id": serverID}).
+		ToSql()
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Msg("failed to build query")
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	pin, err := scanPinnedMessage(s.db.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Msg("failed to get pinned message")
+		return nil, fmt.Errorf("failed to get pinned message: %w", err)
+	}
+
+	return pin, nil
+}
+
+// ListPinnedMessages returns the pinned status cards of all chats.
+func (s *Storage) ListPinnedMessages(ctx context.Context) ([]*models.PinnedMessage, error) {
+	log.Debug().Msg("listing pinned messages")
+
+	query, args, err := s.sb.
+		Select(pinnedMessageColumns...).
+		From("pinned_messages").
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to build query")
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to query pinned messages")
+		return nil, fmt.Errorf("failed to query pinned messages: %w", err)
+	}
+	defer rows.Close()
+
+	var pins []*models.PinnedMessage
+	for rows.Next() {
+		pin, err := scanPinnedMessage(rows)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to scan pinned message")
+			return nil, fmt.Errorf("failed to scan pinned message: %w", err)
+		}
+		pins = append(pins, pin)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error().Err(err).Msg("failed to iterate pinned messages")
+		return nil, fmt.Errorf("failed to iterate pinned messages: %w", err)
+	}
+
+	return pins, nil
+}
+
+// UpsertPinnedMessage creates or replaces the pinned status card of a server in a chat.
+func (s *Storage) UpsertPinnedMessage(ctx context.Context, pin *models.PinnedMessage) error {
+	log.Debug().
+		Int64("chat_id", pin.ChatID).
+		Int64("server_id", pin.ServerID).
+		Int("message_id", pin.MessageID).
+		Msg("upserting pinned message")
+
+	now := time.Now()
+
+	query, args, err := s.sb.
+		Insert("pinned_messages").
+		Columns("chat_id", "server_id", "message_id", "created_at", "updated_at").
+		Values(pin.ChatID, pin.ServerID, pin.MessageID, now, now).
+		Suffix("ON CONFLICT(chat_id, server_id) DO UPDATE SET " +
+			"message_id = excluded.message_id, " +
+			"updated_at = excluded.updated_at " +
+			"RETURNING id, created_at").
+		ToSql()
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", pin.ChatID).Msg("failed to build upsert query")
+		return fmt.Errorf("failed to build upsert query: %w", err)
+	}
+
+	if err := s.db.QueryRowContext(ctx, query, args...).Scan(&pin.ID, &pin.CreatedAt); err != nil {
+		log.Error().Err(err).Int64("chat_id", pin.ChatID).Msg("failed to upsert pinned message")
+		return fmt.Errorf("failed to upsert pinned message: %w", err)
+	}
+
+	pin.UpdatedAt = now
+	log.Info().Int64("chat_id", pin.ChatID).Int64("server_id", pin.ServerID).Msg("pinned message saved")
+	return nil
+}
+
+// DeletePinnedMessage removes a pinned status card.
+func (s *Storage) DeletePinnedMessage(ctx context.Context, id int64) error {
+	log.Debug().Int64("id", id).Msg("deleting pinned message")
+
+	query, args, err := s.sb.
+		Delete("pinned_messages").
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		log.Error().Err(err).Int64("id", id).Msg("failed to build delete query")
+		return fmt.Errorf("failed to build delete query: %w", err)
+	}
+
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		log.Error().Err(err).Int64("id", id).Msg("failed to delete pinned message")
+		return fmt.Errorf("failed to delete pinned message: %w", err)
+	}
+
+	log.Info().Int64("id", id).Msg("pinned message deleted")
+	return nil
+}
@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+func TestStorage_PinnedMessages(t *testing.T) {
+	s := setupTestDB(t)
+	ctx := context.Background()
+
+	server := &models.Server{ChatID: 111, IP: "mc.example.com", Port: 25565}
+	require.NoError(t, s.Upsert(ctx, server))
+
+	pin, err := s.GetPinnedMessage(ctx, 111, server.ID)
+	require.NoError(t, err)
+	assert.Nil(t, pin)
+
+	pin = &models.PinnedMessage{ChatID: 111, ServerID: server.ID, MessageID: 10}
+	require.NoError(t, s.UpsertPinnedMessage(ctx, pin))
+	assert.NotZero(t, pin.ID)
+	firstID := pin.ID
+
+	replaced := &models.PinnedMessage{ChatID: 111, ServerID: server.ID, MessageID: 20}
+	require.NoError(t, s.UpsertPinnedMessage(ctx, replaced))
+	assert.Equal(t, firstID, replaced.ID, "a server has one card per chat")
+
+	got, err := s.GetPinnedMessage(ctx, 111, server.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, 20, got.MessageID)
+
+	pins, err := s.ListPinnedMessages(ctx)
+	require.NoError(t, err)
+	require.Len(t, pins, 1)
+
+	require.NoError(t, s.DeletePinnedMessage(ctx, got.ID))
+	pins, err = s.ListPinnedMessages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, pins)
+}
+
+func TestStorage_PinnedMessages_ServerDeleted(t *testing.T) {
+	s := setupTestDB(t)
+	ctx := context.Background()
+
+	server := &models.Server{ChatID: 111, IP: "mc.example.com", Port: 25565}
+	require.NoError(t, s.Upsert(ctx, server))
+	require.NoError(t, s.UpsertPinnedMessage(ctx, &models.PinnedMessage{ChatID: 111, ServerID: server.ID, MessageID: 10}))
+
+	require.NoError(t, s.DeleteByID(ctx, server.ID))
+
+	pins, err := s.ListPinnedMessages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, pins, "cards of removed servers are dropped")
+}
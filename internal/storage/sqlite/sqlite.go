@@ -60,41 +60,172 @@ func New(dbPath string) (*Storage, error) {
 	}, nil
 }
 
-// GetByChatID returns server configuration for a specific chat.
-func (s *Storage) GetByChatID(ctx context.Context, chatID int64) (*models.Server, error) {
-	log.Debug().Int64("chat_id", chatID).Msg("getting server config by chat_id")
+// serverColumns lists the servers table columns in scan order.
+var serverColumns = []string{
+	"id", "chat_id", "ip", "port", "name", "edition",
+	"query_enabled", "query_port", "protocol_version", "rcon_port", "rcon_password", "added_by",
+	"created_at", "updated_at",
+}
 
-	query, args, err := s.sb.
-		Select("id", "chat_id", "ip", "port", "name", "created_at", "updated_at").
-		From("servers").
-		Where(squirrel.Eq{"chat_id": chatID}).
-		ToSql()
-	if err != nil {
-		log.Error().Err(err).Int64("chat_id", chatID).Msg("failed to build query")
-		return nil, fmt.Errorf("failed to build query: %w", err)
-	}
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
 
+func scanServer(row rowScanner) (*models.Server, error) {
 	var server models.Server
-	err = s.db.QueryRowContext(ctx, query, args...).Scan(
+	err := row.Scan(
 		&server.ID,
 		&server.ChatID,
 		&server.IP,
 		&server.Port,
 		&server.Name,
+		&server.Edition,
+		&server.QueryEnabled,
+		&server.QueryPort,
+		&server.ProtocolVersion,
+		&server.RCONPort,
+		&server.RCONPassword,
+		&server.AddedBy,
 		&server.CreatedAt,
 		&server.UpdatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
+// GetByID returns server configuration by its ID.
+func (s *Storage) GetByID(ctx context.Context, id int64) (*models.Server, error) {
+	log.Debug().Int64("server_id", id).Msg("getting server config by id")
+
+	query, args, err := s.sb.
+		Select(serverColumns...).
+		From("servers").
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		log.Error().Err(err).Int64("server_id", id).Msg("failed to build query")
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	server, err := scanServer(s.db.QueryRowContext(ctx, query, args...))
 	if err == sql.ErrNoRows {
-		log.Debug().Int64("chat_id", chatID).Msg("server config not found")
-		return nil, storage.ErrNotFound{ChatID: chatID}
+		log.Debug().Int64("server_id", id).Msg("server config not found")
+		return nil, storage.ErrNotFound{ID: id}
 	}
 	if err != nil {
-		log.Error().Err(err).Int64("chat_id", chatID).Msg("failed to get server")
+		log.Error().Err(err).Int64("server_id", id).Msg("failed to get server")
 		return nil, fmt.Errorf("failed to get server: %w", err)
 	}
 
-	log.Debug().Int64("chat_id", chatID).Str("ip", server.IP).Int("port", server.Port).Msg("server config found")
-	return &server, nil
+	return server, nil
+}
+
+// ListByChatID returns all server configurations registered in a chat, oldest first.
+func (s *Storage) ListByChatID(ctx context.Context, chatID int64) ([]*models.Server, error) {
+	log.Debug().Int64("chat_id", chatID).Msg("listing server configs by chat_id")
+
+	query, args, err := s.sb.
+		Select(serverColumns...).
+		From("servers").
+		Where(squirrel.Eq{"chat_id": chatID}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		log.Error().Err(err).Int64("chat_id", chatID).Msg("failed to build query")
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	return s.queryServers(ctx, query, args...)
+}
+
+// ListByUserID returns server configurations added by a user or registered in their private chat, oldest first.
+func (s *Storage) ListByUserID(ctx context.Context, userID int64) ([]*models.Server, error) {
+	log.Debug().Int64("user_id", userID).Msg("listing server configs by user")
+
+	query, args, err := s.sb.
+		Select(serverColumns...).
+		From("servers").
+		Where(squirrel.Or{squirrel.Eq{"added_by": userID}, squirrel.Eq{"chat_id": userID}}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("failed to build query")
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	return s.queryServers(ctx, query, args...)
+}
+
+// List returns server configurations of all chats.
+func (s *Storage) List(ctx context.Context) ([]*models.Server, error) {
+	log.Debug().Msg("listing all server configs")
+
+	query, args, err := s.sb.
+		Select(serverColumns...).
+		From("servers").
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to build query")
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	return s.queryServers(ctx, query, args...)
+}
+
+// queryServers runs a select query and scans every returned row.
+func (s *Storage) queryServers(ctx context.Context, query string, args ...any) ([]*models.Server, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to query servers")
+		return nil, fmt.Errorf("failed to query servers: %w", err)
+	}
+	defer rows.Close()
+
+	var servers []*models.Server
+	for rows.Next() {
+		server, err := scanServer(rows)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to scan server")
+			return nil, fmt.Errorf("failed to scan server: %w", err)
+		}
+		servers = append(servers, server)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error().Err(err).Msg("failed to iterate servers")
+		return nil, fmt.Errorf("failed to iterate servers: %w", err)
+	}
+
+	return servers, nil
+}
+
+// findExisting looks up the stored record an upsert should update:
+// by ID when it is set, otherwise by chat and address.
+func (s *Storage) findExisting(ctx context.Context, server *models.Server) (*models.Server, error) {
+	if server.ID != 0 {
+		return s.GetByID(ctx, server.ID)
+	}
+
+	query, args, err := s.sb.
+		Select(serverColumns...).
+		From("servers").
+		Where(squirrel.Eq{"chat_id": server.ChatID, "ip": server.IP, "port": server.Port}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	existing, err := scanServer(s.db.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrNotFound{ChatID: server.ChatID}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server: %w", err)
+	}
+	return existing, nil
 }
 
 // Upsert creates or updates server configuration for a chat.
@@ -104,12 +235,16 @@ func (s *Storage) Upsert(ctx context.Context, server *models.Server) error {
 		Str("ip", server.IP).
 		Int("port", server.Port).
 		Str("name", server.Name).
+		Str("edition", string(server.Edition)).
 		Msg("upserting server config")
 
 	now := time.Now()
+	if server.Edition == "" {
+		server.Edition = models.EditionJava
+	}
 
 	// Try to get existing record
-	existing, err := s.GetByChatID(ctx, server.ChatID)
+	existing, err := s.findExisting(ctx, server)
 	if err != nil && !isNotFound(err) {
 		log.Error().Err(err).Int64("chat_id", server.ChatID).Msg("failed to check existing server")
 		return fmt.Errorf("failed to check existing server: %w", err)
@@ -117,14 +252,20 @@ func (s *Storage) Upsert(ctx context.Context, server *models.Server) error {
 
 	if existing != nil {
 		// Update existing record
-		log.Debug().Int64("chat_id", server.ChatID).Msg("updating existing server config")
+		log.Debug().Int64("chat_id", server.ChatID).Int64("server_id", existing.ID).Msg("updating existing server config")
 		query, args, err := s.sb.
 			Update("servers").
 			Set("ip", server.IP).
 			Set("port", server.Port).
 			Set("name", server.Name).
+			Set("edition", server.Edition).
+			Set("query_enabled", server.QueryEnabled).
+			Set("query_port", server.QueryPort).
+			Set("protocol_version", server.ProtocolVersion).
+			Set("rcon_port", server.RCONPort).
+			Set("rcon_password", server.RCONPassword).
 			Set("updated_at", now).
-			Where(squirrel.Eq{"chat_id": server.ChatID}).
+			Where(squirrel.Eq{"id": existing.ID}).
 			ToSql()
 		if err != nil {
 			log.Error().Err(err).Int64("chat_id", server.ChatID).Msg("failed to build update query")
@@ -146,8 +287,16 @@ func (s *Storage) Upsert(ctx context.Context, server *models.Server) error {
 		log.Debug().Int64("chat_id", server.ChatID).Msg("inserting new server config")
 		query, args, err := s.sb.
 			Insert("servers").
-			Columns("chat_id", "ip", "port", "name", "created_at", "updated_at").
-			Values(server.ChatID, server.IP, server.Port, server.Name, now, now).
+			Columns(
+				"chat_id", "ip", "port", "name", "edition",
+				"query_enabled", "query_port", "protocol_version", "rcon_port", "rcon_password", "added_by",
+				"created_at", "updated_at",
+			).
+			Values(
+				server.ChatID, server.IP, server.Port, server.Name, server.Edition,
+				server.QueryEnabled, server.QueryPort, server.ProtocolVersion, server.RCONPort, server.RCONPassword, server.AddedBy,
+				now, now,
+			).
 			ToSql()
 		if err != nil {
 			log.Error().Err(err).Int64("chat_id", server.ChatID).Msg("failed to build insert query")
@@ -175,7 +324,7 @@ func (s *Storage) Upsert(ctx context.Context, server *models.Server) error {
 	return nil
 }
 
-// Delete removes server configuration for a chat.
+// Delete removes all server configurations for a chat.
 func (s *Storage) Delete(ctx context.Context, chatID int64) error {
 	log.Debug().Int64("chat_id", chatID).Msg("deleting server config")
 
@@ -198,6 +347,29 @@ func (s *Storage) Delete(ctx context.Context, chatID int64) error {
 	return nil
 }
 
+// DeleteByID removes a single server configuration.
+func (s *Storage) DeleteByID(ctx context.Context, id int64) error {
+	log.Debug().Int64("server_id", id).Msg("deleting server config by id")
+
+	query, args, err := s.sb.
+		Delete("servers").
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		log.Error().Err(err).Int64("server_id", id).Msg("failed to build delete query")
+		return fmt.Errorf("failed to build delete query: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Error().Err(err).Int64("server_id", id).Msg("failed to delete server")
+		return fmt.Errorf("failed to delete server: %w", err)
+	}
+
+	log.Info().Int64("server_id", id).Msg("server config deleted")
+	return nil
+}
+
 // Close closes the database connection.
 func (s *Storage) Close() error {
 	log.Debug().Msg("closing database connection")
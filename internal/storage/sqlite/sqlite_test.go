@@ -77,7 +77,7 @@ func TestStorage_Upsert_Update(t *testing.T) {
 	assert.True(t, server.UpdatedAt.After(originalCreatedAt) || server.UpdatedAt.Equal(originalCreatedAt))
 }
 
-func TestStorage_GetByChatID_Found(t *testing.T) {
+func TestStorage_Delete(t *testing.T) {
 	s := setupTestDB(t)
 	ctx := context.Background()
 
@@ -91,80 +91,256 @@ func TestStorage_GetByChatID_Found(t *testing.T) {
 	err := s.Upsert(ctx, server)
 	require.NoError(t, err)
 
-	found, err := s.GetByChatID(ctx, 12345)
+	err = s.Delete(ctx, 12345)
 	require.NoError(t, err)
 
-	assert.Equal(t, server.ID, found.ID)
-	assert.Equal(t, server.ChatID, found.ChatID)
-	assert.Equal(t, server.IP, found.IP)
-	assert.Equal(t, server.Port, found.Port)
-	assert.Equal(t, server.Name, found.Name)
+	list, err := s.ListByChatID(ctx, 12345)
+	require.NoError(t, err)
+	assert.Empty(t, list)
 }
 
-func TestStorage_GetByChatID_NotFound(t *testing.T) {
+func TestStorage_Delete_NonExistent(t *testing.T) {
 	s := setupTestDB(t)
 	ctx := context.Background()
 
-	_, err := s.GetByChatID(ctx, 99999)
-	assert.Error(t, err)
+	// Should not error when deleting non-existent record
+	err := s.Delete(ctx, 99999)
+	assert.NoError(t, err)
+}
+
+func TestStorage_MultipleChatIDs(t *testing.T) {
+	s := setupTestDB(t)
+	ctx := context.Background()
 
-	var notFound storage.ErrNotFound
-	assert.ErrorAs(t, err, &notFound)
-	assert.Equal(t, int64(99999), notFound.ChatID)
+	servers := []*models.Server{
+		{ChatID: 111, IP: "server1.com", Port: 25565, Name: "Server 1"},
+		{ChatID: 222, IP: "server2.com", Port: 25566, Name: "Server 2"},
+		{ChatID: 333, IP: "server3.com", Port: 25567, Name: "Server 3"},
+	}
+
+	for _, server := range servers {
+		err := s.Upsert(ctx, server)
+		require.NoError(t, err)
+	}
+
+	for _, server := range servers {
+		list, err := s.ListByChatID(ctx, server.ChatID)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, server.IP, list[0].IP)
+		assert.Equal(t, server.Name, list[0].Name)
+	}
 }
 
-func TestStorage_Delete(t *testing.T) {
+func TestStorage_MultipleServersPerChat(t *testing.T) {
 	s := setupTestDB(t)
 	ctx := context.Background()
 
-	server := &models.Server{
-		ChatID: 12345,
-		IP:     "mc.example.com",
-		Port:   25565,
-		Name:   "Test Server",
+	servers := []*models.Server{
+		{ChatID: 111, IP: "lobby.example.com", Port: 25565, Name: "Lobby"},
+		{ChatID: 111, IP: "survival.example.com", Port: 25565, Name: "Survival"},
+		{ChatID: 111, IP: "creative.example.com", Port: 25566, Name: "Creative"},
+		{ChatID: 222, IP: "other.example.com", Port: 25565, Name: "Other"},
 	}
 
-	err := s.Upsert(ctx, server)
+	for _, server := range servers {
+		err := s.Upsert(ctx, server)
+		require.NoError(t, err)
+	}
+
+	list, err := s.ListByChatID(ctx, 111)
 	require.NoError(t, err)
+	require.Len(t, list, 3)
+	assert.Equal(t, "Lobby", list[0].Name)
+	assert.Equal(t, "Survival", list[1].Name)
+	assert.Equal(t, "Creative", list[2].Name)
+}
 
-	err = s.Delete(ctx, 12345)
+func TestStorage_ListByChatID_Empty(t *testing.T) {
+	s := setupTestDB(t)
+	ctx := context.Background()
+
+	list, err := s.ListByChatID(ctx, 99999)
 	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestStorage_Upsert_SameAddressUpdatesName(t *testing.T) {
+	s := setupTestDB(t)
+	ctx := context.Background()
+
+	original := &models.Server{ChatID: 111, IP: "mc.example.com", Port: 25565, Name: "Old"}
+	require.NoError(t, s.Upsert(ctx, original))
+
+	renamed := &models.Server{ChatID: 111, IP: "mc.example.com", Port: 25565, Name: "New"}
+	require.NoError(t, s.Upsert(ctx, renamed))
+
+	assert.Equal(t, original.ID, renamed.ID)
+
+	list, err := s.ListByChatID(ctx, 111)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "New", list[0].Name)
+}
+
+func TestStorage_GetByID(t *testing.T) {
+	s := setupTestDB(t)
+	ctx := context.Background()
+
+	server := &models.Server{ChatID: 111, IP: "mc.example.com", Port: 25565, Name: "Test"}
+	require.NoError(t, s.Upsert(ctx, server))
 
-	_, err = s.GetByChatID(ctx, 12345)
-	assert.Error(t, err)
+	found, err := s.GetByID(ctx, server.ID)
+	require.NoError(t, err)
+	assert.Equal(t, server.ChatID, found.ChatID)
+	assert.Equal(t, server.IP, found.IP)
 
+	_, err = s.GetByID(ctx, server.ID+100)
 	var notFound storage.ErrNotFound
 	assert.ErrorAs(t, err, &notFound)
+	assert.Equal(t, server.ID+100, notFound.ID)
 }
 
-func TestStorage_Delete_NonExistent(t *testing.T) {
+func TestStorage_DeleteByID(t *testing.T) {
 	s := setupTestDB(t)
 	ctx := context.Background()
 
-	// Should not error when deleting non-existent record
-	err := s.Delete(ctx, 99999)
-	assert.NoError(t, err)
+	first := &models.Server{ChatID: 111, IP: "one.example.com", Port: 25565}
+	second := &models.Server{ChatID: 111, IP: "two.example.com", Port: 25565}
+	require.NoError(t, s.Upsert(ctx, first))
+	require.NoError(t, s.Upsert(ctx, second))
+
+	require.NoError(t, s.DeleteByID(ctx, first.ID))
+
+	list, err := s.ListByChatID(ctx, 111)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, second.ID, list[0].ID)
 }
 
-func TestStorage_MultipleChatIDs(t *testing.T) {
+func TestStorage_List(t *testing.T) {
 	s := setupTestDB(t)
 	ctx := context.Background()
 
 	servers := []*models.Server{
-		{ChatID: 111, IP: "server1.com", Port: 25565, Name: "Server 1"},
-		{ChatID: 222, IP: "server2.com", Port: 25566, Name: "Server 2"},
-		{ChatID: 333, IP: "server3.com", Port: 25567, Name: "Server 3"},
+		{ChatID: 111, IP: "one.example.com", Port: 25565},
+		{ChatID: 222, IP: "two.example.com", Port: 25565},
+		{ChatID: 111, IP: "three.example.com", Port: 25565},
 	}
-
 	for _, server := range servers {
-		err := s.Upsert(ctx, server)
-		require.NoError(t, err)
+		require.NoError(t, s.Upsert(ctx, server))
 	}
 
-	for _, server := range servers {
-		found, err := s.GetByChatID(ctx, server.ChatID)
-		require.NoError(t, err)
-		assert.Equal(t, server.IP, found.IP)
-		assert.Equal(t, server.Name, found.Name)
+	list, err := s.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	for i, server := range servers {
+		assert.Equal(t, server.ID, list[i].ID)
+	}
+}
+
+func TestStorage_Edition(t *testing.T) {
+	s := setupTestDB(t)
+	ctx := context.Background()
+
+	java := &models.Server{ChatID: 111, IP: "java.example.com", Port: 25565}
+	bedrock := &models.Server{ChatID: 111, IP: "bedrock.example.com", Port: 19132, Edition: models.EditionBedrock}
+	require.NoError(t, s.Upsert(ctx, java))
+	require.NoError(t, s.Upsert(ctx, bedrock))
+
+	list, err := s.ListByChatID(ctx, 111)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, models.EditionJava, list[0].Edition)
+	assert.Equal(t, models.EditionBedrock, list[1].Edition)
+	assert.True(t, list[1].IsBedrock())
+}
+
+func TestStorage_QuerySettings(t *testing.T) {
+	s := setupTestDB(t)
+	ctx := context.Background()
+
+	server := &models.Server{ChatID: 111, IP: "mc.example.com", Port: 25565}
+	require.NoError(t, s.Upsert(ctx, server))
+
+	got, err := s.GetByID(ctx, server.ID)
+	require.NoError(t, err)
+	assert.False(t, got.QueryEnabled)
+	assert.Equal(t, 25565, got.QueryTargetPort())
+
+	server.QueryEnabled = true
+	server.QueryPort = 25575
+	require.NoError(t, s.Upsert(ctx, server))
+
+	got, err = s.GetByID(ctx, server.ID)
+	require.NoError(t, err)
+	assert.True(t, got.QueryEnabled)
+	assert.Equal(t, 25575, got.QueryTargetPort())
+}
+
+func TestStorage_ProtocolVersion(t *testing.T) {
+	s := setupTestDB(t)
+	ctx := context.Background()
+
+	server := &models.Server{ChatID: 111, IP: "mc.example.com", Port: 25565}
+	require.NoError(t, s.Upsert(ctx, server))
+
+	got, err := s.GetByID(ctx, server.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.AutoProtocolVersion, got.ProtocolVersion)
+
+	server.ProtocolVersion = 765
+	require.NoError(t, s.Upsert(ctx, server))
+
+	got, err = s.GetByID(ctx, server.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 765, got.ProtocolVersion)
+}
+
+func TestStorage_RCON(t *testing.T) {
+	s := setupTestDB(t)
+	ctx := context.Background()
+
+	server := &models.Server{ChatID: 111, IP: "mc.example.com", Port: 25565}
+	require.NoError(t, s.Upsert(ctx, server))
+
+	got, err := s.GetByID(ctx, server.ID)
+	require.NoError(t, err)
+	assert.False(t, got.RCONEnabled())
+
+	server.RCONPort = 25575
+	server.RCONPassword = "sealed"
+	require.NoError(t, s.Upsert(ctx, server))
+
+	got, err = s.GetByID(ctx, server.ID)
+	require.NoError(t, err)
+	assert.True(t, got.RCONEnabled())
+	assert.Equal(t, 25575, got.RCONPort)
+	assert.Equal(t, "sealed", got.RCONPassword)
+}
+
+func TestStorage_ListByUserID(t *testing.T) {
+	s := setupTestDB(t)
+	ctx := context.Background()
+
+	added := &models.Server{ChatID: -100, IP: "group.example.com", Port: 25565, AddedBy: 42}
+	private := &models.Server{ChatID: 42, IP: "private.example.com", Port: 25565}
+	other := &models.Server{ChatID: -100, IP: "other.example.com", Port: 25565, AddedBy: 7}
+	for _, server := range []*models.Server{added, private, other} {
+		require.NoError(t, s.Upsert(ctx, server))
 	}
+
+	list, err := s.ListByUserID(ctx, 42)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, added.ID, list[0].ID)
+	assert.Equal(t, int64(42), list[0].AddedBy)
+	assert.Equal(t, private.ID, list[1].ID)
+
+	added.Name = "Renamed"
+	added.AddedBy = 7
+	require.NoError(t, s.Upsert(ctx, added))
+	got, err := s.GetByID(ctx, added.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), got.AddedBy, "updates keep the user who added the server")
 }
@@ -0,0 +1,79 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+// statusHistoryLimit is the number of status checks kept per server.
+const statusHistoryLimit = 1000
+
+// AddStatusRecord stores the outcome of a status check, dropping the oldest records of the server over the limit.
+func (s *Storage) AddStatusRecord(ctx context.Context, record *models.StatusRecord) error {
+	log.Debug().
+		Int64("server_id", record.ServerID).
+		Bool("online", record.Online).
+		Str("error_kind", record.ErrorKind).
+		Msg("adding status record")
+
+	if record.CheckedAt.IsZero() {
+		record.CheckedAt = time.Now()
+	}
+
+	query, args, err := s.sb.
+		Insert("status_history").
+		Columns("server_id", "online", "players_online", "latency_ms", "error_kind", "error", "checked_at").
+		Values(
+			record.ServerID,
+			record.Online,
+			record.PlayersOnline,
+			record.Latency.Milliseconds(),
+			record.ErrorKind,
+			record.Error,
+			record.CheckedAt,
+		).
+		ToSql()
+	if err != nil {
+		log.Error().Err(err).Int64("server_id", record.ServerID).Msg("failed to build insert query")
+		return fmt.Errorf("failed to build insert query: %w", err)
+	}
+
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Error().Err(err).Int64("server_id", record.ServerID).Msg("failed to insert status record")
+		return fmt.Errorf("failed to insert status record: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		log.Error().Err(err).Int64("server_id", record.ServerID).Msg("failed to get last insert id")
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	record.ID = id
+
+	query, args, err = s.sb.
+		Delete("status_history").
+		Where(squirrel.Eq{"server_id": record.ServerID}).
+		Where(squirrel.Expr(
+			"id NOT IN (SELECT id FROM status_history WHERE server_id = ? ORDER BY id DESC LIMIT ?)",
+			record.ServerID, statusHistoryLimit,
+		)).
+		ToSql()
+	if err != nil {
+		log.Error().Err(err).Int64("server_id", record.ServerID).Msg("failed to build prune query")
+		return fmt.Errorf("failed to build prune query: %w", err)
+	}
+
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		log.Error().Err(err).Int64("server_id", record.ServerID).Msg("failed to prune status history")
+		return fmt.Errorf("failed to prune status history: %w", err)
+	}
+
+	return nil
+}
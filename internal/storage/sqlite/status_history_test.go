@@ -0,0 +1,109 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ykhdr/mss-bot/internal/storage/models"
+)
+
+// statusHistory returns the latest status checks of a server, newest first
+func statusHistory(t *testing.T, s *Storage, serverID int64, limit int) []*models.StatusRecord {
+	t.Helper()
+
+	rows, err := s.db.Query(
+		`SELECT online, players_online, latency_ms, error_kind, error, checked_at
+		FROM status_history WHERE server_id = ? ORDER BY id DESC LIMIT ?`,
+		serverID, limit,
+	)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var records []*models.StatusRecord
+	for rows.Next() {
+		record := &models.StatusRecord{ServerID: serverID}
+		var latencyMS int64
+		require.NoError(t, rows.Scan(
+			&record.Online, &record.PlayersOnline, &latencyMS, &record.ErrorKind, &record.Error, &record.CheckedAt,
+		))
+		record.Latency = time.Duration(latencyMS) * time.Millisecond
+		records = append(records, record)
+	}
+	require.NoError(t, rows.Err())
+	return records
+}
+
+func TestStorage_StatusHistory(t *testing.T) {
+	s := setupTestDB(t)
+	ctx := context.Background()
+
+	server := &models.Server{ChatID: 111, IP: "mc.example.com", Port: 25565}
+	require.NoError(t, s.Upsert(ctx, server))
+
+	require.NoError(t, s.AddStatusRecord(ctx, &models.StatusRecord{
+		ServerID:      server.ID,
+		Online:        true,
+		PlayersOnline: 4,
+		Latency:       42 * time.Millisecond,
+	}))
+	require.NoError(t, s.AddStatusRecord(ctx, &models.StatusRecord{
+		ServerID:  server.ID,
+		ErrorKind: "timeout",
+		Error:     "i/o timeout",
+	}))
+
+	records := statusHistory(t, s, server.ID, 10)
+	require.Len(t, records, 2)
+
+	assert.False(t, records[0].Online)
+	assert.Equal(t, "timeout", records[0].ErrorKind)
+	assert.Equal(t, "i/o timeout", records[0].Error)
+	assert.True(t, records[1].Online)
+	assert.Equal(t, 4, records[1].PlayersOnline)
+	assert.Equal(t, 42*time.Millisecond, records[1].Latency)
+	assert.Zero(t, records[0].Latency)
+	assert.False(t, records[1].CheckedAt.IsZero())
+
+	records = statusHistory(t, s, server.ID, 1)
+	assert.Len(t, records, 1)
+}
+
+func TestStorage_StatusHistory_Limit(t *testing.T) {
+	s := setupTestDB(t)
+	ctx := context.Background()
+
+	first := &models.Server{ChatID: 111, IP: "a.example.com", Port: 25565}
+	second := &models.Server{ChatID: 111, IP: "b.example.com", Port: 25565}
+	require.NoError(t, s.Upsert(ctx, first))
+	require.NoError(t, s.Upsert(ctx, second))
+
+	require.NoError(t, s.AddStatusRecord(ctx, &models.StatusRecord{ServerID: second.ID, Online: true}))
+	for i := 0; i < statusHistoryLimit+5; i++ {
+		require.NoError(t, s.AddStatusRecord(ctx, &models.StatusRecord{ServerID: first.ID, PlayersOnline: i}))
+	}
+
+	records := statusHistory(t, s, first.ID, statusHistoryLimit*2)
+	require.Len(t, records, statusHistoryLimit)
+	assert.Equal(t, statusHistoryLimit+4, records[0].PlayersOnline)
+
+	records = statusHistory(t, s, second.ID, 10)
+	assert.Len(t, records, 1, "other servers are not pruned")
+}
+
+func TestStorage_StatusHistory_DeletedWithServer(t *testing.T) {
+	s := setupTestDB(t)
+	ctx := context.Background()
+
+	server := &models.Server{ChatID: 111, IP: "mc.example.com", Port: 25565}
+	require.NoError(t, s.Upsert(ctx, server))
+	require.NoError(t, s.AddStatusRecord(ctx, &models.StatusRecord{ServerID: server.ID, Online: true}))
+
+	require.NoError(t, s.DeleteByID(ctx, server.ID))
+
+	records := statusHistory(t, s, server.ID, 10)
+	assert.Empty(t, records)
+}
@@ -8,22 +8,66 @@ import (
 
 // ServerStorage defines the interface for server configuration storage
 type ServerStorage interface {
-	// GetByChatID returns server configuration for a specific chat
-	GetByChatID(ctx context.Context, chatID int64) (*models.Server, error)
+	// GetByID returns server configuration by its ID
+	GetByID(ctx context.Context, id int64) (*models.Server, error)
 
-	// Upsert creates or updates server configuration for a chat
+	// ListByChatID returns all server configurations registered in a chat
+	ListByChatID(ctx context.Context, chatID int64) ([]*models.Server, error)
+
+	// ListByUserID returns server configurations added by a user or registered in their private chat
+	ListByUserID(ctx context.Context, userID int64) ([]*models.Server, error)
+
+	// List returns server configurations of all chats
+	List(ctx context.Context) ([]*models.Server, error)
+
+	// Upsert creates or updates server configuration for a chat.
+	// Servers are matched by ID when it is set, otherwise by chat and address.
 	Upsert(ctx context.Context, server *models.Server) error
 
-	// Delete removes server configuration for a chat
+	// Delete removes all server configurations for a chat
 	Delete(ctx context.Context, chatID int64) error
 
+	// DeleteByID removes a single server configuration
+	DeleteByID(ctx context.Context, id int64) error
+
 	// Close closes the storage connection
 	Close() error
 }
 
+// ChatSettingsStorage defines the interface for per-chat settings storage
+type ChatSettingsStorage interface {
+	// GetChatSettings returns settings for a chat, or defaults when none are stored
+	GetChatSettings(ctx context.Context, chatID int64) (*models.ChatSettings, error)
+
+	// UpsertChatSettings creates or updates settings for a chat
+	UpsertChatSettings(ctx context.Context, settings *models.ChatSettings) error
+}
+
+// StatusHistoryStorage defines the interface for server status check history
+type StatusHistoryStorage interface {
+	// AddStatusRecord stores the outcome of a status check, dropping the oldest records of the server over the limit
+	AddStatusRecord(ctx context.Context, record *models.StatusRecord) error
+}
+
+// PinnedMessageStorage defines the interface for pinned status cards
+type PinnedMessageStorage interface {
+	// GetPinnedMessage returns the pinned status card of a server in a chat, or nil when there is none
+	GetPinnedMessage(ctx context.Context, chatID, serverID int64) (*models.PinnedMessage, error)
+
+	// ListPinnedMessages returns the pinned status cards of all chats
+	ListPinnedMessages(ctx context.Context) ([]*models.PinnedMessage, error)
+
+	// UpsertPinnedMessage creates or replaces the pinned status card of a server in a chat
+	UpsertPinnedMessage(ctx context.Context, pin *models.PinnedMessage) error
+
+	// DeletePinnedMessage removes a pinned status card
+	DeletePinnedMessage(ctx context.Context, id int64) error
+}
+
 // ErrNotFound is returned when a server configuration is not found
 type ErrNotFound struct {
 	ChatID int64
+	ID     int64
 }
 
 func (e ErrNotFound) Error() string {